@@ -9,6 +9,7 @@ import (
 	"mcolomerc/mcp-server/internal/openapi"
 	"mcolomerc/mcp-server/internal/server"
 	"mcolomerc/mcp-server/internal/tools"
+	"mcolomerc/mcp-server/internal/tracing"
 	"os"
 	"os/signal"
 	"syscall"
@@ -22,6 +23,7 @@ func main() {
 	envFile := flag.String("env", "", "Path to environment file")
 	mode := flag.String("mode", "both", "Server mode: 'stdio', 'http', or 'both'")
 	monitorInterval := flag.String("monitor", "30s", "Resource monitoring interval (e.g., 30s, 1m, 5m). Set to 'off' to disable")
+	selfTest := flag.Bool("selftest", false, "Resolve the mapping and schema of every generated action and resource, report failures and exit")
 	flag.Parse()
 
 	// Setup context for graceful shutdown
@@ -65,6 +67,47 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Export traces when enabled; the exporter is configured with the standard OTEL_* variables
+	if cfg.OTelEnabled {
+		shutdownTracing, err := tracing.Setup(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to set up tracing: %v\n", err)
+			os.Exit(1)
+		}
+		defer func() {
+			shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancelShutdown()
+			if err := shutdownTracing(shutdownCtx); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to flush traces: %v\n", err)
+			}
+		}()
+		fmt.Fprintf(os.Stderr, "OpenTelemetry tracing enabled\n")
+	}
+
+	// Apply request body content type preferences before tools are generated
+	tools.SetContentTypePreferences(cfg.ContentTypePreference, map[string][]string{
+		tools.ServiceKafka:          cfg.KafkaContentTypePreference,
+		tools.ServiceFlink:          cfg.FlinkContentTypePreference,
+		tools.ServiceSchemaRegistry: cfg.SchemaRegistryContentTypePreference,
+	})
+
+	// Register extra semantic actions before tools are generated
+	actionRules, err := tools.ParseSemanticActionRules(cfg.SemanticActionRules)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid SEMANTIC_ACTION_RULES: %v\n", err)
+		os.Exit(1)
+	}
+	tools.SetSemanticActionRules(tools.WithoutReservedActions(actionRules, server.ReservedToolNames(cfg)))
+
+	// Apply the tool generation settings before the specs are loaded
+	tools.SetHideDeprecated(cfg.HideDeprecated)
+	tools.SetResourceIDParams(cfg.ResourceIDParams)
+	tools.SetSingularResources(cfg.SingularResources)
+	tools.SetCanonicalResourceNames(cfg.CanonicalResourceNames)
+	tools.SetRateLimitHints(cfg.RateLimitHints)
+	tools.SetTagFilter(cfg.IncludeTags, cfg.ExcludeTags)
+	tools.SetMaxTools(cfg.MaxTools, cfg.MaxToolsPriority)
+
 	// Load and parse OpenAPI specs
 	spec, telemetrySpec, err := openapi.LoadBothSpecs()
 	if err != nil {
@@ -82,6 +125,19 @@ func main() {
 	// Create the composite MCPServer instance with config, specs and semanticTools
 	mcpServer := server.NewCompositeServer(cfg, spec, telemetrySpec, semanticTools)
 
+	// In self-test mode check the generated tools and exit instead of serving
+	if *selfTest {
+		report := mcpServer.SelfTest()
+		for _, failure := range report.Failures {
+			fmt.Fprintf(os.Stderr, "FAIL %s %s: %s\n", failure.Action, failure.Resource, failure.Error)
+		}
+		fmt.Fprintf(os.Stderr, "Self-test checked %d action/resource pairs, %d failed\n", report.Checked, len(report.Failures))
+		if !report.Passed() {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Connect monitor to server if monitoring is enabled
 	if monitor != nil {
 		mcpServer.SetMonitor(monitor)
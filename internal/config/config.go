@@ -41,13 +41,122 @@ type Config struct {
 	PromptsFolder           string // Optional: folder path containing prompt .txt files
 	DirectivesFolder        string // Optional: folder path containing directive .txt files
 	EnableDirectives        bool   // Optional: enable/disable directives (default: true)
+	MaxPromptBytes          int    // Optional: most bytes a prompt composed with its directives may have; 0 means no limit
+	PromptOverflow          string // Optional: what to do with a prompt over MAX_PROMPT_BYTES: warn or truncate (default: warn)
 
 	// LLM Detection Configuration (Optional)
-	LLMDetectionEnabled    bool   // Optional: enable external LLM-based prompt injection detection
-	LLMDetectionURL        string // Optional: URL for LLM API endpoint
-	LLMDetectionModel      string // Optional: model name for detection
-	LLMDetectionTimeoutSec int    // Optional: timeout in seconds for LLM requests
-	LLMDetectionAPIKey     string // Optional: API key for LLM service
+	LLMDetectionEnabled          bool    // Optional: enable external LLM-based prompt injection detection
+	LLMDetectionURL              string  // Optional: URL for LLM API endpoint
+	LLMDetectionModel            string  // Optional: model name for detection
+	LLMDetectionTimeoutSec       int     // Optional: timeout in seconds for LLM requests
+	LLMDetectionAPIKey           string  // Optional: API key for LLM service
+	LLMDetectionTemperature      float64 // Optional: sampling temperature sent with each LLM request
+	LLMDetectionMaxTokens        int     // Optional: maximum tokens the LLM may generate (0 = model default)
+	LLMDetectionSystemPromptFile string  // Optional: file whose contents replace the built-in detection system prompt
+	LLMHighSeverityConfidence    float64 // Optional: LLM confidence above which a malicious verdict is high severity (default: 0.8)
+	LLMMinConfidence             float64 // Optional: LLM confidence below which verdicts are ignored (default: 0, none ignored)
+	LLMDetectionFailClosed       bool    // Optional: block tool calls when the LLM cannot be consulted instead of relying on regex alone
+
+	// Request Body Content Type Configuration (Optional)
+	ContentTypePreference               []string // Optional: preferred request body content types, in order
+	KafkaContentTypePreference          []string // Optional: content type order for Kafka REST endpoints
+	FlinkContentTypePreference          []string // Optional: content type order for Flink endpoints
+	SchemaRegistryContentTypePreference []string // Optional: content type order for Schema Registry endpoints
+
+	// Base URL Override Configuration (Optional)
+	AllowBaseURLOverride        bool     // Optional: accept per-call base_url_override arguments (default: false)
+	BaseURLOverrideAllowedHosts []string // Optional: hosts (and their subdomains) an override may target
+
+	// Spec Base Path Configuration (Optional)
+	SpecBasePath string // Optional: gateway path prefix added in front of spec paths, e.g. /confluent-proxy
+
+	// Spec Servers Configuration (Optional)
+	UseSpecServers      bool              // Optional: take base URLs from the spec's servers entries (default: false)
+	SpecServerVariables map[string]string // Optional: values for {variable} templates in spec server URLs
+
+	// Semantic Action Configuration (Optional)
+	SemanticActionRules []string          // Optional: extra actions as action=suffix or action=METHOD suffix
+	ActionAliases       map[string]string // Optional: action mapped to the tool name exposed to clients for it, e.g. get=read,delete=remove
+
+	// Argument Validation Configuration (Optional)
+	StrictArgs        bool // Optional: reject tool arguments the operation does not define (default: false)
+	ValidateIDFormats bool // Optional: reject environment, cluster and compute pool id arguments without their id prefix (default: false)
+
+	// Default Scope Configuration (Optional)
+	InjectDefaultScope bool // Optional: fill environment and organization parameters declared by cloud and telemetry operations from CONFLUENT_ENV_ID and FLINK_ORG_ID (default: true)
+
+	// Resource Identifier Configuration (Optional)
+	ResourceIDParams map[string]string // Optional: resource type mapped to the argument that identifies one instance, e.g. topics=topic_name
+
+	// Resource MIME Type Configuration (Optional)
+	ResourceMIMETypes map[string]string // Optional: resource type mapped to the MIME type of its MCP resources, e.g. schemas=application/schema+json
+
+	// Tool Generation Configuration (Optional)
+	HideDeprecated         bool     // Optional: skip operations marked deprecated instead of annotating them (default: false)
+	SingularResources      []string // Optional: singular path segments recognized as resources, e.g. config,mode
+	CanonicalResourceNames bool     // Optional: collapse singular/plural variants of a resource name into its plural form (default: false)
+	RateLimitHints         bool     // Optional: add the x-ratelimit-* extensions of spec operations to tool descriptions (default: false)
+	IncludeTags            []string // Optional: generate tools only for operations with one of these spec tags
+	ExcludeTags            []string // Optional: leave out operations with one of these spec tags
+	MaxTools               int      // Optional: maximum number of exposed tools, 0 for no limit (default: 0)
+	MaxToolsPriority       []string // Optional: tool names kept first when MAX_TOOLS is reached
+
+	// Result Chaining Configuration (Optional)
+	EnableResultChaining bool // Optional: resolve "$last..." argument references from the previous result (default: false)
+
+	// Output Configuration (Optional)
+	PrettyJSON   bool // Optional: indent JSON tool results for readability (default: false, compact)
+	ReproOnError bool // Optional: add a curl command repeating the call to failed API call errors, always on when LOG=DEBUG (default: false)
+	UnwrapData   bool // Optional: return the data array of list results directly (default: false)
+
+	// Invocation Concurrency Configuration (Optional)
+	MaxConcurrentInvocations  int // Optional: maximum simultaneous tool invocations (default: 0, unlimited)
+	InvocationQueueTimeoutSec int // Optional: seconds to wait for a free slot before failing (default: 30)
+
+	// Retry Configuration (Optional)
+	MaxRetries       int      // Optional: retries for transient failures of idempotent calls (default: 0, no retries)
+	RetryBackoffMs   int      // Optional: delay before the first retry, growing linearly per attempt (default: 500)
+	RetryableActions []string // Optional: semantic actions that are safe to retry (default: get,list,update,delete)
+
+	// HTTP Client Configuration (Optional)
+	HTTPTimeoutSec          int // Optional: per-request timeout in seconds (default: 30)
+	HTTPMaxIdleConnsPerHost int // Optional: idle keep-alive connections kept per API host (default: 10)
+	HTTPIdleConnTimeoutSec  int // Optional: seconds an idle connection is kept open (default: 90)
+
+	// Invocation Budget Configuration (Optional)
+	InvocationBudgetSec int // Optional: total seconds one tool invocation may spend on requests, retries and pages (default: 0, no budget)
+
+	// Response Cache Configuration (Optional)
+	ResponseCacheTTLSec     int // Optional: seconds a GET response is kept for conditional revalidation, 0 disables the cache (default: 0)
+	ResponseCacheMaxEntries int // Optional: maximum number of cached GET responses (default: 100)
+
+	// Pagination Configuration (Optional)
+	ContinuationTokenTTLSec int // Optional: seconds a list continuation token stays valid (default: 300)
+	MaxAutoPages            int // Optional: most pages an all_pages list call follows (default: 20)
+
+	// Kafka Cluster Configuration (Optional)
+	KafkaClusters map[string]string // Optional: Kafka cluster IDs mapped to their REST endpoints, from the KAFKA_CLUSTERS JSON object
+
+	// Service Headers Configuration (Optional)
+	ServiceHeaders map[string]map[string]string // Optional: extra request headers per service, from the SERVICE_HEADERS JSON object
+
+	// Credential Profile Configuration (Optional)
+	CredentialProfilesFile string                       // Optional: JSON file of named credential sets selectable per call
+	CredentialProfiles     map[string]CredentialProfile // Loaded from CredentialProfilesFile
+	EnvironmentProfiles    map[string]string            // Optional: environment IDs mapped to the credential profile used for calls in them
+
+	// Custom Tools Configuration (Optional)
+	CustomToolsFile string                 // Optional: JSON file of handcrafted tools mapped to existing endpoints
+	CustomTools     []CustomToolDefinition // Loaded from CustomToolsFile
+
+	// Client Token Configuration (Optional)
+	UseClientToken bool // Optional: authenticate with the MCP client's bearer token instead of configured credentials (default: false)
+
+	// Tracing Configuration (Optional)
+	OTelEnabled bool // Optional: export OpenTelemetry traces of tool invocations and API calls (default: false)
+
+	// Admin Tools Configuration (Optional)
+	EnableAdminTools bool // Optional: register administrative tools such as test_guardrails (default: false)
 }
 
 // LoadConfig loads and validates configuration from environment variables
@@ -56,18 +165,18 @@ func LoadConfig(path string) (*Config, error) {
 	_ = godotenv.Load(path)
 
 	cfg := &Config{
-		OpenAPISpecURL:          os.Getenv("OPENAPI_SPEC_URL"),
-		TelemetryOpenAPISpecURL: os.Getenv("TELEMETRY_OPENAPI_SPEC_URL"),
+		OpenAPISpecURL:          InterpolateEnv(os.Getenv("OPENAPI_SPEC_URL")),
+		TelemetryOpenAPISpecURL: InterpolateEnv(os.Getenv("TELEMETRY_OPENAPI_SPEC_URL")),
 		ConfluentEnvID:          os.Getenv("CONFLUENT_ENV_ID"),
 		ConfluentCloudAPIKey:    os.Getenv("CONFLUENT_CLOUD_API_KEY"),
 		ConfluentCloudAPISecret: os.Getenv("CONFLUENT_CLOUD_API_SECRET"),
 		BootstrapServers:        os.Getenv("BOOTSTRAP_SERVERS"),
 		KafkaAPIKey:             os.Getenv("KAFKA_API_KEY"),
 		KafkaAPISecret:          os.Getenv("KAFKA_API_SECRET"),
-		KafkaRestEndpoint:       os.Getenv("KAFKA_REST_ENDPOINT"),
+		KafkaRestEndpoint:       InterpolateEnv(os.Getenv("KAFKA_REST_ENDPOINT")),
 		KafkaClusterID:          os.Getenv("KAFKA_CLUSTER_ID"),
 		FlinkOrgID:              os.Getenv("FLINK_ORG_ID"),
-		FlinkRestEndpoint:       os.Getenv("FLINK_REST_ENDPOINT"),
+		FlinkRestEndpoint:       InterpolateEnv(os.Getenv("FLINK_REST_ENDPOINT")),
 		FlinkEnvName:            os.Getenv("FLINK_ENV_NAME"),
 		FlinkDatabaseName:       os.Getenv("FLINK_DATABASE_NAME"),
 		FlinkAPIKey:             os.Getenv("FLINK_API_KEY"),
@@ -75,20 +184,121 @@ func LoadConfig(path string) (*Config, error) {
 		FlinkComputePoolID:      os.Getenv("FLINK_COMPUTE_POOL_ID"),
 		SchemaRegistryAPIKey:    os.Getenv("SCHEMA_REGISTRY_API_KEY"),
 		SchemaRegistryAPISecret: os.Getenv("SCHEMA_REGISTRY_API_SECRET"),
-		SchemaRegistryEndpoint:  os.Getenv("SCHEMA_REGISTRY_ENDPOINT"),
+		SchemaRegistryEndpoint:  InterpolateEnv(os.Getenv("SCHEMA_REGISTRY_ENDPOINT")),
 		TableflowAPIKey:         os.Getenv("TABLEFLOW_API_KEY"),
 		TableflowAPISecret:      os.Getenv("TABLEFLOW_API_SECRET"),
 		LOG:                     os.Getenv("LOG"),                      // Optional field
 		PromptsFolder:           os.Getenv("PROMPTS_FOLDER"),           // Optional field
 		DirectivesFolder:        os.Getenv("DIRECTIVES_FOLDER"),        // Optional field
 		EnableDirectives:        getEnvBool("ENABLE_DIRECTIVES", true), // Optional field, default true,
+		MaxPromptBytes:          getEnvInt("MAX_PROMPT_BYTES", 0),
+		PromptOverflow:          getEnvString("PROMPT_OVERFLOW", "warn"),
 
 		// LLM Detection Configuration (Optional)
-		LLMDetectionEnabled:    getEnvBool("LLM_DETECTION_ENABLED", false),
-		LLMDetectionURL:        getEnvString("LLM_DETECTION_URL", "http://localhost:11434/api/chat"),
-		LLMDetectionModel:      getEnvString("LLM_DETECTION_MODEL", "llama3.2:1b"),
-		LLMDetectionTimeoutSec: getEnvInt("LLM_DETECTION_TIMEOUT", 10),
-		LLMDetectionAPIKey:     os.Getenv("LLM_DETECTION_API_KEY"), // Optional, empty by default
+		LLMDetectionEnabled:          getEnvBool("LLM_DETECTION_ENABLED", false),
+		LLMDetectionURL:              getEnvString("LLM_DETECTION_URL", "http://localhost:11434/api/chat"),
+		LLMDetectionModel:            getEnvString("LLM_DETECTION_MODEL", "llama3.2:1b"),
+		LLMDetectionTimeoutSec:       getEnvInt("LLM_DETECTION_TIMEOUT", 10),
+		LLMDetectionAPIKey:           os.Getenv("LLM_DETECTION_API_KEY"), // Optional, empty by default
+		LLMDetectionTemperature:      getEnvFloat("LLM_DETECTION_TEMPERATURE", 0),
+		LLMDetectionMaxTokens:        getEnvInt("LLM_DETECTION_MAX_TOKENS", 0),
+		LLMDetectionSystemPromptFile: os.Getenv("LLM_DETECTION_SYSTEM_PROMPT_FILE"),
+		LLMHighSeverityConfidence:    getEnvFloat("LLM_HIGH_SEVERITY_CONFIDENCE", 0.8),
+		LLMMinConfidence:             getEnvFloat("LLM_MIN_CONFIDENCE", 0),
+		LLMDetectionFailClosed:       getEnvBool("LLM_DETECTION_FAIL_CLOSED", false),
+
+		// Request Body Content Type Configuration (Optional)
+		ContentTypePreference:               getEnvList("CONTENT_TYPE_PREFERENCE"),
+		KafkaContentTypePreference:          getEnvList("KAFKA_CONTENT_TYPE_PREFERENCE"),
+		FlinkContentTypePreference:          getEnvList("FLINK_CONTENT_TYPE_PREFERENCE"),
+		SchemaRegistryContentTypePreference: getEnvList("SCHEMA_REGISTRY_CONTENT_TYPE_PREFERENCE"),
+
+		// Base URL Override Configuration (Optional)
+		AllowBaseURLOverride:        getEnvBool("ALLOW_BASE_URL_OVERRIDE", false),
+		BaseURLOverrideAllowedHosts: getEnvList("BASE_URL_OVERRIDE_ALLOWED_HOSTS"),
+
+		// Spec Base Path Configuration (Optional)
+		SpecBasePath: getEnvString("SPEC_BASE_PATH", ""),
+
+		// Spec Servers Configuration (Optional)
+		UseSpecServers:      getEnvBool("USE_SPEC_SERVERS", false),
+		SpecServerVariables: getEnvMap("SPEC_SERVER_VARIABLES"),
+
+		// Semantic Action Configuration (Optional)
+		SemanticActionRules: getEnvList("SEMANTIC_ACTION_RULES"),
+		ActionAliases:       getEnvMap("ACTION_ALIASES"),
+
+		// Argument Validation Configuration (Optional)
+		StrictArgs:        getEnvBool("STRICT_ARGS", false),
+		ValidateIDFormats: getEnvBool("VALIDATE_ID_FORMATS", false),
+
+		// Default Scope Configuration (Optional)
+		InjectDefaultScope: getEnvBool("INJECT_DEFAULT_SCOPE", true),
+
+		// Resource Identifier Configuration (Optional)
+		ResourceIDParams: getEnvMap("RESOURCE_ID_PARAMS"),
+
+		// Resource MIME Type Configuration (Optional)
+		ResourceMIMETypes: getEnvMap("RESOURCE_MIME_TYPES"),
+
+		// Tool Generation Configuration (Optional)
+		HideDeprecated:         getEnvBool("HIDE_DEPRECATED", false),
+		SingularResources:      getEnvList("SINGULAR_RESOURCES"),
+		CanonicalResourceNames: getEnvBool("CANONICAL_RESOURCE_NAMES", false),
+		RateLimitHints:         getEnvBool("RATE_LIMIT_HINTS", false),
+		IncludeTags:            getEnvList("INCLUDE_TAGS"),
+		ExcludeTags:            getEnvList("EXCLUDE_TAGS"),
+		MaxTools:               getEnvInt("MAX_TOOLS", 0),
+		MaxToolsPriority:       getEnvList("MAX_TOOLS_PRIORITY"),
+
+		// Result Chaining Configuration (Optional)
+		EnableResultChaining: getEnvBool("ENABLE_RESULT_CHAINING", false),
+
+		// Output Configuration (Optional)
+		PrettyJSON:   getEnvBool("PRETTY_JSON", false),
+		UnwrapData:   getEnvBool("UNWRAP_DATA", false),
+		ReproOnError: getEnvBool("REPRO_ON_ERROR", false),
+
+		// Invocation Concurrency Configuration (Optional)
+		MaxConcurrentInvocations:  getEnvInt("MAX_CONCURRENT_INVOCATIONS", 0),
+		InvocationQueueTimeoutSec: getEnvInt("INVOCATION_QUEUE_TIMEOUT", 30),
+
+		// Retry Configuration (Optional)
+		MaxRetries:       getEnvInt("MAX_RETRIES", 0),
+		RetryBackoffMs:   getEnvInt("RETRY_BACKOFF_MS", 500),
+		RetryableActions: getEnvList("RETRYABLE_ACTIONS"),
+
+		// HTTP Client Configuration (Optional)
+		HTTPTimeoutSec:          getEnvInt("HTTP_TIMEOUT", 30),
+		HTTPMaxIdleConnsPerHost: getEnvInt("HTTP_MAX_IDLE_CONNS_PER_HOST", 10),
+		HTTPIdleConnTimeoutSec:  getEnvInt("HTTP_IDLE_CONN_TIMEOUT", 90),
+
+		// Invocation Budget Configuration (Optional)
+		InvocationBudgetSec: getEnvInt("INVOCATION_BUDGET_SECONDS", 0),
+
+		// Response Cache Configuration (Optional)
+		ResponseCacheTTLSec:     getEnvInt("RESPONSE_CACHE_TTL", 0),
+		ResponseCacheMaxEntries: getEnvInt("RESPONSE_CACHE_MAX_ENTRIES", 100),
+
+		// Pagination Configuration (Optional)
+		ContinuationTokenTTLSec: getEnvInt("CONTINUATION_TOKEN_TTL", 300),
+		MaxAutoPages:            getEnvInt("MAX_AUTO_PAGES", 20),
+
+		// Credential Profile Configuration (Optional)
+		CredentialProfilesFile: getEnvString("CREDENTIAL_PROFILES", ""),
+		EnvironmentProfiles:    getEnvMap("ENVIRONMENT_PROFILES"),
+
+		// Custom Tools Configuration (Optional)
+		CustomToolsFile: getEnvString("CUSTOM_TOOLS", ""),
+
+		// Client Token Configuration (Optional)
+		UseClientToken: getEnvBool("USE_CLIENT_TOKEN", false),
+
+		// Tracing Configuration (Optional)
+		OTelEnabled: getEnvBool("OTEL_ENABLED", false),
+
+		// Admin Tools Configuration (Optional)
+		EnableAdminTools: getEnvBool("ENABLE_ADMIN_TOOLS", false),
 	}
 
 	missing := []string{}
@@ -137,6 +347,41 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, errors.New("SCHEMA_REGISTRY_ENDPOINT must be a valid URL")
 	}
 
+	// Kafka Cluster Configuration (Optional)
+	kafkaClusters, err := ParseKafkaClusters(os.Getenv("KAFKA_CLUSTERS"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.KafkaClusters = kafkaClusters
+
+	// Service Headers Configuration (Optional)
+	serviceHeaders, err := ParseServiceHeaders(os.Getenv("SERVICE_HEADERS"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.ServiceHeaders = serviceHeaders
+
+	if cfg.CredentialProfilesFile != "" {
+		profiles, err := LoadCredentialProfiles(cfg.CredentialProfilesFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.CredentialProfiles = profiles
+	}
+	for environmentID, profile := range cfg.EnvironmentProfiles {
+		if _, exists := cfg.CredentialProfiles[profile]; !exists && profile != DefaultProfileName {
+			return nil, fmt.Errorf("ENVIRONMENT_PROFILES maps %s to unknown credential profile '%s'", environmentID, profile)
+		}
+	}
+
+	if cfg.CustomToolsFile != "" {
+		customTools, err := LoadCustomTools(cfg.CustomToolsFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.CustomTools = customTools
+	}
+
 	return cfg, nil
 }
 
@@ -170,6 +415,19 @@ func getEnvInt(key string, defaultValue int) int {
 	return intValue
 }
 
+// getEnvFloat gets a floating point value from environment variable with a default
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return floatValue
+}
+
 // getEnvString gets a string value from environment variable with a default
 func getEnvString(key string, defaultValue string) string {
 	value := os.Getenv(key)
@@ -178,3 +436,35 @@ func getEnvString(key string, defaultValue string) string {
 	}
 	return value
 }
+
+// getEnvList gets a comma-separated list from environment variable, skipping empty entries
+func getEnvList(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
+
+// getEnvMap gets a comma-separated list of key=value pairs from environment variable,
+// skipping entries without a key
+func getEnvMap(key string) map[string]string {
+	items := getEnvList(key)
+	if len(items) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(items))
+	for _, item := range items {
+		name, value, _ := strings.Cut(item, "=")
+		if name = strings.TrimSpace(name); name != "" {
+			values[name] = strings.TrimSpace(value)
+		}
+	}
+	return values
+}
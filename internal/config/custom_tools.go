@@ -0,0 +1,53 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CustomToolDefinition describes a handcrafted tool loaded from the CUSTOM_TOOLS file.
+// Each tool calls one existing API endpoint; Defaults are merged under the call arguments.
+type CustomToolDefinition struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Method      string                 `json:"method"`
+	Path        string                 `json:"path"`                 // API path pattern with {placeholders}
+	Parameters  map[string]interface{} `json:"parameters,omitempty"` // JSON Schema properties of the tool arguments
+	Required    []string               `json:"required,omitempty"`
+	Defaults    map[string]interface{} `json:"defaults,omitempty"`
+}
+
+// LoadCustomTools reads a JSON file with a list of custom tool definitions
+func LoadCustomTools(path string) ([]CustomToolDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom tools: %w", err)
+	}
+
+	var definitions []CustomToolDefinition
+	if err := json.Unmarshal(data, &definitions); err != nil {
+		return nil, fmt.Errorf("failed to parse custom tools %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool, len(definitions))
+	for i := range definitions {
+		def := &definitions[i]
+		if def.Name == "" || def.Path == "" {
+			return nil, fmt.Errorf("custom tool %d in %s needs a name and a path", i+1, path)
+		}
+		if seen[def.Name] {
+			return nil, fmt.Errorf("custom tool '%s' is defined more than once in %s", def.Name, path)
+		}
+		seen[def.Name] = true
+
+		def.Method = strings.ToUpper(def.Method)
+		switch def.Method {
+		case "GET", "POST", "PUT", "PATCH", "DELETE":
+		default:
+			return nil, fmt.Errorf("custom tool '%s' has unsupported method '%s'", def.Name, def.Method)
+		}
+	}
+	return definitions, nil
+}
@@ -0,0 +1,48 @@
+package config
+
+// ConfiguredServices reports, per service, whether its endpoint and credentials are configured.
+// Telemetry is served with the Cloud API credentials.
+func (c *Config) ConfiguredServices() map[string]bool {
+	cloud := c.ConfluentCloudAPIKey != "" && c.ConfluentCloudAPISecret != ""
+	return map[string]bool{
+		"cloud":           cloud,
+		"kafka":           (c.KafkaRestEndpoint != "" || len(c.KafkaClusters) > 0) && c.KafkaAPIKey != "" && c.KafkaAPISecret != "",
+		"flink":           c.FlinkRestEndpoint != "" && c.FlinkAPIKey != "" && c.FlinkAPISecret != "",
+		"schema_registry": c.SchemaRegistryEndpoint != "" && c.SchemaRegistryAPIKey != "" && c.SchemaRegistryAPISecret != "",
+		"tableflow":       c.TableflowAPIKey != "" && c.TableflowAPISecret != "",
+		"telemetry":       cloud,
+	}
+}
+
+// EnabledFeatures reports, per optional feature, whether the configuration enables it
+func (c *Config) EnabledFeatures() map[string]bool {
+	return map[string]bool{
+		"strict_args":         c.StrictArgs,
+		"validate_id_formats": c.ValidateIDFormats,
+		"result_chaining":     c.EnableResultChaining,
+		"response_cache":      c.ResponseCacheTTLSec > 0,
+		"credential_profiles": len(c.CredentialProfiles) > 0,
+		"client_token":        c.UseClientToken,
+		"spec_servers":        c.UseSpecServers,
+		"directives":          c.EnableDirectives,
+		"tracing":             c.OTelEnabled,
+		"admin_tools":         c.EnableAdminTools,
+	}
+}
+
+// ActiveFeatures returns the names of the configured services and enabled features, e.g. flink or
+// strict_args, as directives name them in their requires list
+func (c *Config) ActiveFeatures() map[string]bool {
+	active := make(map[string]bool)
+	for name, enabled := range c.ConfiguredServices() {
+		if enabled {
+			active[name] = true
+		}
+	}
+	for name, enabled := range c.EnabledFeatures() {
+		if enabled {
+			active[name] = true
+		}
+	}
+	return active
+}
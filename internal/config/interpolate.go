@@ -0,0 +1,22 @@
+package config
+
+import (
+	"os"
+	"regexp"
+)
+
+// envReferencePattern matches a ${VAR} reference
+var envReferencePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// InterpolateEnv replaces ${VAR} references in a spec URL or endpoint with the value of the
+// environment variable, e.g. https://${REGION}.example/spec.json. References to unset variables
+// are left as they are so the resulting URL shows what is missing.
+func InterpolateEnv(value string) string {
+	return envReferencePattern.ReplaceAllStringFunc(value, func(reference string) string {
+		name := envReferencePattern.FindStringSubmatch(reference)[1]
+		if resolved, ok := os.LookupEnv(name); ok {
+			return resolved
+		}
+		return reference
+	})
+}
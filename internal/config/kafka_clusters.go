@@ -0,0 +1,40 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParseKafkaClusters parses the KAFKA_CLUSTERS JSON object mapping Kafka cluster IDs to their REST
+// endpoints. Trailing slashes are removed so endpoints can be joined with API paths.
+func ParseKafkaClusters(value string) (map[string]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var clusters map[string]string
+	if err := json.Unmarshal([]byte(value), &clusters); err != nil {
+		return nil, fmt.Errorf("failed to parse KAFKA_CLUSTERS: %w", err)
+	}
+	for clusterID, endpoint := range clusters {
+		if !strings.HasPrefix(clusterID, "lkc-") {
+			return nil, fmt.Errorf("KAFKA_CLUSTERS cluster ID '%s' must start with 'lkc-'", clusterID)
+		}
+		if _, err := url.ParseRequestURI(endpoint); err != nil {
+			return nil, fmt.Errorf("KAFKA_CLUSTERS endpoint of %s must be a valid URL", clusterID)
+		}
+		clusters[clusterID] = strings.TrimSuffix(endpoint, "/")
+	}
+	return clusters, nil
+}
+
+// KafkaRestEndpointFor returns the REST endpoint of a Kafka cluster: its KAFKA_CLUSTERS entry, or
+// KAFKA_REST_ENDPOINT for the configured cluster and any cluster not listed
+func (c *Config) KafkaRestEndpointFor(clusterID string) string {
+	if endpoint, exists := c.KafkaClusters[clusterID]; exists {
+		return endpoint
+	}
+	return c.KafkaRestEndpoint
+}
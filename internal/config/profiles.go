@@ -0,0 +1,101 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultProfileName selects the credentials configured through environment variables
+const DefaultProfileName = "default"
+
+// CredentialProfile is a named set of API credentials loaded from the CREDENTIAL_PROFILES file.
+// Empty fields fall back to the environment-configured credentials.
+type CredentialProfile struct {
+	ConfluentCloudAPIKey    string `json:"confluent_cloud_api_key,omitempty"`
+	ConfluentCloudAPISecret string `json:"confluent_cloud_api_secret,omitempty"`
+	KafkaAPIKey             string `json:"kafka_api_key,omitempty"`
+	KafkaAPISecret          string `json:"kafka_api_secret,omitempty"`
+	FlinkAPIKey             string `json:"flink_api_key,omitempty"`
+	FlinkAPISecret          string `json:"flink_api_secret,omitempty"`
+	SchemaRegistryAPIKey    string `json:"schema_registry_api_key,omitempty"`
+	SchemaRegistryAPISecret string `json:"schema_registry_api_secret,omitempty"`
+	TableflowAPIKey         string `json:"tableflow_api_key,omitempty"`
+	TableflowAPISecret      string `json:"tableflow_api_secret,omitempty"`
+	// SchemaRegistryEndpoint is the Schema Registry the profile's Schema Registry credentials
+	// belong to; a per-call schema_registry_endpoint override is only sent them when it matches
+	SchemaRegistryEndpoint string `json:"schema_registry_endpoint,omitempty"`
+}
+
+// LoadCredentialProfiles reads a JSON file mapping profile names to credential sets
+func LoadCredentialProfiles(path string) (map[string]CredentialProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credential profiles: %w", err)
+	}
+
+	var profiles map[string]CredentialProfile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse credential profiles %s: %w", path, err)
+	}
+	if _, exists := profiles[DefaultProfileName]; exists {
+		return nil, fmt.Errorf("credential profile '%s' is reserved for the environment-configured credentials", DefaultProfileName)
+	}
+	return profiles, nil
+}
+
+// WithProfile returns a copy of the config whose credentials are replaced by the named profile.
+// An empty name or DefaultProfileName returns the config unchanged.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	if name == "" || name == DefaultProfileName {
+		return c, nil
+	}
+
+	profile, exists := c.CredentialProfiles[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown credential profile '%s'", name)
+	}
+
+	profiled := *c
+	overrides := []struct {
+		target *string
+		value  string
+	}{
+		{&profiled.ConfluentCloudAPIKey, profile.ConfluentCloudAPIKey},
+		{&profiled.ConfluentCloudAPISecret, profile.ConfluentCloudAPISecret},
+		{&profiled.KafkaAPIKey, profile.KafkaAPIKey},
+		{&profiled.KafkaAPISecret, profile.KafkaAPISecret},
+		{&profiled.FlinkAPIKey, profile.FlinkAPIKey},
+		{&profiled.FlinkAPISecret, profile.FlinkAPISecret},
+		{&profiled.SchemaRegistryAPIKey, profile.SchemaRegistryAPIKey},
+		{&profiled.SchemaRegistryAPISecret, profile.SchemaRegistryAPISecret},
+		{&profiled.TableflowAPIKey, profile.TableflowAPIKey},
+		{&profiled.TableflowAPISecret, profile.TableflowAPISecret},
+	}
+	for _, override := range overrides {
+		if override.value != "" {
+			*override.target = override.value
+		}
+	}
+	return &profiled, nil
+}
+
+// ProfileForEnvironment returns the credential profile mapped to an environment in
+// ENVIRONMENT_PROFILES, or an empty name when the environment has no profile of its own
+func (c *Config) ProfileForEnvironment(environmentID string) string {
+	if environmentID == "" {
+		return ""
+	}
+	return c.EnvironmentProfiles[environmentID]
+}
+
+// SchemaRegistryCredentialsFor reports whether the named profile holds Schema Registry
+// credentials for endpoint, so calls overriding the Schema Registry endpoint with it may use them
+func (c *Config) SchemaRegistryCredentialsFor(name, endpoint string) bool {
+	profile, exists := c.CredentialProfiles[name]
+	if !exists || profile.SchemaRegistryAPIKey == "" || profile.SchemaRegistryAPISecret == "" {
+		return false
+	}
+	return strings.TrimSuffix(profile.SchemaRegistryEndpoint, "/") == strings.TrimSuffix(endpoint, "/")
+}
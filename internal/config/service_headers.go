@@ -0,0 +1,28 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseServiceHeaders parses the SERVICE_HEADERS JSON object mapping service names (cloud, kafka,
+// flink, schema-registry, tableflow, telemetry) to the extra headers sent on their calls
+func ParseServiceHeaders(value string) (map[string]map[string]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var headers map[string]map[string]string
+	if err := json.Unmarshal([]byte(value), &headers); err != nil {
+		return nil, fmt.Errorf("failed to parse SERVICE_HEADERS: %w", err)
+	}
+	for service, serviceHeaders := range headers {
+		for name := range serviceHeaders {
+			if strings.TrimSpace(name) == "" || strings.ContainsAny(name, " :\r\n") {
+				return nil, fmt.Errorf("SERVICE_HEADERS header name '%s' of %s is not valid", name, service)
+			}
+		}
+	}
+	return headers, nil
+}
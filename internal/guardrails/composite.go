@@ -5,6 +5,7 @@ import (
 	"mcolomerc/mcp-server/internal/logger"
 	"os"
 	"strconv"
+	"strings"
 )
 
 // CompositeGuardrails combines multiple guardrail mechanisms
@@ -16,11 +17,11 @@ type CompositeGuardrails struct {
 
 // GuardrailsResult represents the combined result of all guardrail checks
 type GuardrailsResult struct {
-	Blocked          bool
-	InjectionResult  DetectionResult
-	LoopResult       LoopDetectionResult
-	BlockingReason   string
-	AllowedToExecute bool
+	Blocked          bool                `json:"blocked"`
+	InjectionResult  DetectionResult     `json:"injection_result"`
+	LoopResult       LoopDetectionResult `json:"loop_result"`
+	BlockingReason   string              `json:"blocking_reason,omitempty"`
+	AllowedToExecute bool                `json:"allowed_to_execute"`
 }
 
 // NewCompositeGuardrails creates a new composite guardrails instance
@@ -34,11 +35,26 @@ func NewCompositeGuardrails(cfg *config.Config) *CompositeGuardrails {
 			cfg.LLMDetectionURL, cfg.LLMDetectionModel, cfg.LLMDetectionTimeoutSec)
 
 		llmConfig := ExternalLLMConfig{
-			Enabled:    cfg.LLMDetectionEnabled,
-			URL:        cfg.LLMDetectionURL,
-			Model:      cfg.LLMDetectionModel,
-			TimeoutSec: cfg.LLMDetectionTimeoutSec,
-			APIKey:     cfg.LLMDetectionAPIKey,
+			Enabled:     cfg.LLMDetectionEnabled,
+			URL:         cfg.LLMDetectionURL,
+			Model:       cfg.LLMDetectionModel,
+			TimeoutSec:  cfg.LLMDetectionTimeoutSec,
+			APIKey:      cfg.LLMDetectionAPIKey,
+			Temperature: cfg.LLMDetectionTemperature,
+			MaxTokens:   cfg.LLMDetectionMaxTokens,
+
+			HighSeverityConfidence: cfg.LLMHighSeverityConfidence,
+			MinConfidence:          cfg.LLMMinConfidence,
+			FailClosed:             cfg.LLMDetectionFailClosed,
+		}
+		if cfg.LLMDetectionSystemPromptFile != "" {
+			prompt, err := os.ReadFile(cfg.LLMDetectionSystemPromptFile)
+			if err != nil {
+				logger.Error("Failed to read LLM detection system prompt file %s, using built-in prompt: %v\n",
+					cfg.LLMDetectionSystemPromptFile, err)
+			} else {
+				llmConfig.SystemPrompt = strings.TrimSpace(string(prompt))
+			}
 		}
 		injectionDetector.ConfigureLLM(llmConfig)
 		logger.Debug("LLM detection configuration completed successfully\n")
@@ -51,6 +67,13 @@ func NewCompositeGuardrails(cfg *config.Config) *CompositeGuardrails {
 		TimeWindowSeconds:      getEnvInt("LOOP_DETECTION_TIME_WINDOW", 60),
 		CooldownSeconds:        getEnvInt("LOOP_DETECTION_COOLDOWN", 30),
 		EnableGlobalProtection: getEnvBool("LOOP_DETECTION_GLOBAL", true),
+
+		MaxConsecutiveOverrides: parseLoopOverrides(os.Getenv("LOOP_DETECTION_OVERRIDES")),
+		PollableCalls:           DefaultPollableCalls,
+		PollableMaxConsecutive:  getEnvInt("LOOP_DETECTION_POLLABLE_MAX", DefaultPollableMaxConsecutive),
+	}
+	if value, set := os.LookupEnv("LOOP_DETECTION_POLLABLE"); set {
+		loopConfig.PollableCalls = parsePollableCalls(value)
 	}
 
 	loopDetector := NewLoopDetection(loopConfig)
@@ -89,6 +112,12 @@ func (cg *CompositeGuardrails) ValidateToolInput(toolName string, args map[strin
 		}
 		return result
 	}
+	if injectionResult.LLMUnavailable {
+		result.Blocked = true
+		result.AllowedToExecute = false
+		result.BlockingReason = LLMUnavailableReason
+		return result
+	}
 
 	// 2. Check for loop patterns
 	loopResult := cg.loopDetector.CheckForLoop(toolName, args)
@@ -104,6 +133,37 @@ func (cg *CompositeGuardrails) ValidateToolInput(toolName string, args map[strin
 	return result
 }
 
+// ValidateBatchItemInput validates one item of a batch call. Items are still checked for
+// injection, but loop detection is skipped because the batch was checked as a single call.
+func (cg *CompositeGuardrails) ValidateBatchItemInput(toolName string, args map[string]interface{}) GuardrailsResult {
+	result := GuardrailsResult{
+		Blocked:          false,
+		AllowedToExecute: true,
+	}
+
+	if !cg.enabled {
+		return result
+	}
+
+	injectionResult := cg.injectionDetector.ValidateToolInput(toolName, args)
+	result.InjectionResult = injectionResult
+
+	if injectionResult.Detected {
+		result.Blocked = true
+		result.AllowedToExecute = false
+		result.BlockingReason = "Prompt injection detected"
+		if injectionResult.HighSeverity {
+			result.BlockingReason = "High-risk prompt injection detected"
+		}
+	} else if injectionResult.LLMUnavailable {
+		result.Blocked = true
+		result.AllowedToExecute = false
+		result.BlockingReason = LLMUnavailableReason
+	}
+
+	return result
+}
+
 // GetInjectionDetector returns the injection detector for direct access
 func (cg *CompositeGuardrails) GetInjectionDetector() *InjectionDetection {
 	return cg.injectionDetector
@@ -125,6 +185,37 @@ func (cg *CompositeGuardrails) GetStats() map[string]interface{} {
 	}
 }
 
+// GuardrailsStatus summarizes which guardrails are active and the limits of loop detection
+type GuardrailsStatus struct {
+	Enabled               bool `json:"enabled"`
+	InjectionDetection    bool `json:"injection_detection"`
+	LLMDetection          bool `json:"llm_detection"`
+	LoopDetection         bool `json:"loop_detection"`
+	MaxConsecutiveCalls   int  `json:"max_consecutive_calls"`
+	LoopTimeWindowSeconds int  `json:"loop_time_window_seconds"`
+	LoopCooldownSeconds   int  `json:"loop_cooldown_seconds"`
+	// Pollable calls repeat up to PollableMaxConsecutiveCalls times instead of MaxConsecutiveCalls
+	PollableCalls               []string `json:"pollable_calls,omitempty"`
+	PollableMaxConsecutiveCalls int      `json:"pollable_max_consecutive_calls,omitempty"`
+}
+
+// Status returns the active guardrails and their limits
+func (cg *CompositeGuardrails) Status() GuardrailsStatus {
+	loopConfig := cg.loopDetector.config
+	return GuardrailsStatus{
+		Enabled:               cg.enabled,
+		InjectionDetection:    cg.enabled && cg.injectionDetector.enabled,
+		LLMDetection:          cg.enabled && cg.injectionDetector.enabled && cg.injectionDetector.llmConfig.Enabled,
+		LoopDetection:         cg.enabled && loopConfig.Enabled,
+		MaxConsecutiveCalls:   loopConfig.MaxConsecutiveCalls,
+		LoopTimeWindowSeconds: loopConfig.TimeWindowSeconds,
+		LoopCooldownSeconds:   loopConfig.CooldownSeconds,
+
+		PollableCalls:               loopConfig.PollableCalls,
+		PollableMaxConsecutiveCalls: loopConfig.PollableMaxConsecutive,
+	}
+}
+
 // ClearAllCooldowns clears all cooldowns (for testing or manual intervention)
 func (cg *CompositeGuardrails) ClearAllCooldowns() {
 	cg.loopDetector.ClearCooldowns()
@@ -156,3 +247,35 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// parsePollableCalls parses a comma-separated list of pollable calls such as
+// "get:statements,get_telemetry". An empty list makes every call subject to the strict limit.
+func parsePollableCalls(value string) []string {
+	pollable := []string{}
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			pollable = append(pollable, entry)
+		}
+	}
+	return pollable
+}
+
+// parseLoopOverrides parses per-action consecutive call limits such as
+// "get:statements=20,list=5". Malformed entries are logged and skipped.
+func parseLoopOverrides(value string) map[string]int {
+	overrides := make(map[string]int)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, limit, found := strings.Cut(entry, "=")
+		parsed, err := strconv.Atoi(strings.TrimSpace(limit))
+		if !found || strings.TrimSpace(key) == "" || err != nil || parsed <= 0 {
+			logger.Error("Ignoring invalid LOOP_DETECTION_OVERRIDES entry %q: expected action[:resource]=limit\n", entry)
+			continue
+		}
+		overrides[strings.TrimSpace(key)] = parsed
+	}
+	return overrides
+}
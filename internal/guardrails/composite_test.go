@@ -2,6 +2,8 @@ package guardrails
 
 import (
 	"mcolomerc/mcp-server/internal/config"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -138,3 +140,45 @@ func TestCompositeGuardrails(t *testing.T) {
 		}
 	})
 }
+
+func TestLLMDetectionFailClosed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	guardrails := func(failClosed bool) *CompositeGuardrails {
+		return NewCompositeGuardrails(&config.Config{
+			LLMDetectionEnabled:    true,
+			LLMDetectionURL:        server.URL,
+			LLMDetectionModel:      "llama3.2:1b",
+			LLMDetectionTimeoutSec: 5,
+			LLMDetectionFailClosed: failClosed,
+		})
+	}
+	benign := map[string]interface{}{"resource": "topics"}
+
+	t.Run("Fail open falls back to regex", func(t *testing.T) {
+		if result := guardrails(false).ValidateToolInput("list", benign); result.Blocked {
+			t.Errorf("Expected a benign call to pass when the LLM is down, got %+v", result)
+		}
+	})
+
+	cg := guardrails(true)
+
+	t.Run("Fail closed blocks the call", func(t *testing.T) {
+		result := cg.ValidateToolInput("list", benign)
+		if !result.Blocked || result.AllowedToExecute || result.BlockingReason != LLMUnavailableReason {
+			t.Errorf("Expected the call to be blocked with %q, got %+v", LLMUnavailableReason, result)
+		}
+		if !result.InjectionResult.LLMUnavailable || result.InjectionResult.Detected {
+			t.Errorf("Expected an unclassified rather than detected input, got %+v", result.InjectionResult)
+		}
+	})
+
+	t.Run("Fail closed blocks batch items", func(t *testing.T) {
+		if result := cg.ValidateBatchItemInput("list", benign); !result.Blocked {
+			t.Errorf("Expected the batch item to be blocked, got %+v", result)
+		}
+	})
+}
@@ -1,6 +1,11 @@
 package guardrails
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -185,3 +190,107 @@ func TestLLMDetectionWithFallback(t *testing.T) {
 		t.Error("Expected regex patterns to be detected")
 	}
 }
+
+func TestLLMDetectionRequestParameters(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(LLMResponse{Choices: []LLMChoice{{Message: LLMMessage{
+			Role:    "assistant",
+			Content: `{"is_malicious": false, "confidence": 0.9, "category": "benign", "severity": "low"}`,
+		}}}})
+	}))
+	defer server.Close()
+
+	detect := func(t *testing.T, path string) LLMRequest {
+		t.Helper()
+		detector := NewInjectionDetection()
+		detector.ConfigureLLM(ExternalLLMConfig{
+			Enabled:      true,
+			URL:          server.URL + path,
+			Model:        "test-model",
+			TimeoutSec:   5,
+			Temperature:  0.2,
+			MaxTokens:    256,
+			SystemPrompt: "Custom detection prompt",
+		})
+		if _, err := detector.detectWithLLM("list my topics"); err != nil {
+			t.Fatalf("detectWithLLM returned error: %v", err)
+		}
+		var received LLMRequest
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Fatalf("Failed to decode LLM request: %v", err)
+		}
+		if received.Model != "test-model" {
+			t.Errorf("Expected model test-model, got %s", received.Model)
+		}
+		if received.Temperature != 0.2 || received.MaxTokens != 256 {
+			t.Errorf("Expected temperature 0.2 and max_tokens 256, got %v and %d", received.Temperature, received.MaxTokens)
+		}
+		if len(received.Messages) == 0 || received.Messages[0].Content != "Custom detection prompt" {
+			t.Errorf("Expected custom system prompt, got %+v", received.Messages)
+		}
+		return received
+	}
+
+	t.Run("Ollama chat API gets options", func(t *testing.T) {
+		received := detect(t, "/api/chat")
+		if received.Options == nil || received.Options.Temperature != 0.2 || received.Options.NumPredict != 256 {
+			t.Errorf("Expected options with temperature 0.2 and num_predict 256, got %+v", received.Options)
+		}
+	})
+
+	t.Run("OpenAI-compatible endpoint gets no options", func(t *testing.T) {
+		detect(t, "/v1/chat/completions")
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &fields); err != nil {
+			t.Fatalf("Failed to decode LLM request: %v", err)
+		}
+		if _, found := fields["options"]; found {
+			t.Errorf("Expected no options field, got %s", body)
+		}
+	})
+}
+
+func TestLLMConfidenceThresholds(t *testing.T) {
+	var confidence float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		verdict := fmt.Sprintf(`{"is_malicious": true, "confidence": %.2f, "category": "injection", "severity": "medium"}`, confidence)
+		json.NewEncoder(w).Encode(LLMResponse{Choices: []LLMChoice{{Message: LLMMessage{Role: "assistant", Content: verdict}}}})
+	}))
+	defer server.Close()
+
+	detector := NewInjectionDetection()
+	detector.ConfigureLLM(ExternalLLMConfig{
+		Enabled:                true,
+		URL:                    server.URL,
+		Model:                  "test-model",
+		TimeoutSec:             5,
+		HighSeverityConfidence: 0.9,
+		MinConfidence:          0.5,
+	})
+
+	tests := []struct {
+		confidence   float64
+		detected     bool
+		highSeverity bool
+	}{
+		{0.49, false, false},
+		{0.51, true, false},
+		{0.89, true, false},
+		{0.91, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("confidence %.2f", tt.confidence), func(t *testing.T) {
+			confidence = tt.confidence
+			result := detector.DetectInjection("list my topics")
+			if result.Detected != tt.detected || result.HighSeverity != tt.highSeverity {
+				t.Errorf("Expected detected=%v high_severity=%v, got detected=%v high_severity=%v",
+					tt.detected, tt.highSeverity, result.Detected, result.HighSeverity)
+			}
+		})
+	}
+}
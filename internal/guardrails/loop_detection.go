@@ -17,8 +17,22 @@ type LoopDetectionConfig struct {
 	TimeWindowSeconds      int
 	CooldownSeconds        int
 	EnableGlobalProtection bool
+	// MaxConsecutiveOverrides replaces MaxConsecutiveCalls for an "action" or "action:resource",
+	// e.g. a higher limit for get calls polling a Flink statement's status
+	MaxConsecutiveOverrides map[string]int
+	// PollableCalls are read-only "action" or "action:resource" calls that poll a status and so
+	// naturally repeat; they get PollableMaxConsecutive instead of the strict limit
+	PollableCalls          []string
+	PollableMaxConsecutive int
 }
 
+// DefaultPollableCalls are the status polling calls exempt from the strict consecutive call limit
+// unless LOOP_DETECTION_POLLABLE replaces them
+var DefaultPollableCalls = []string{"get:statements", "get:status", "get:clusters", "get:compute-pools", "get_telemetry"}
+
+// DefaultPollableMaxConsecutive is the consecutive call limit of pollable calls when none is configured
+const DefaultPollableMaxConsecutive = 10
+
 // ToolCall represents a single tool call with its parameters
 type ToolCall struct {
 	ToolName  string
@@ -38,11 +52,11 @@ type LoopDetection struct {
 
 // LoopDetectionResult represents the result of loop detection
 type LoopDetectionResult struct {
-	IsLoop           bool
-	ConsecutiveCalls int
-	MaxAllowed       int
-	CooldownUntil    *time.Time
-	Message          string
+	IsLoop           bool       `json:"is_loop"`
+	ConsecutiveCalls int        `json:"consecutive_calls"`
+	MaxAllowed       int        `json:"max_allowed"`
+	CooldownUntil    *time.Time `json:"cooldown_until,omitempty"`
+	Message          string     `json:"message,omitempty"`
 }
 
 // NewLoopDetection creates a new loop detection instance
@@ -56,6 +70,9 @@ func NewLoopDetection(config LoopDetectionConfig) *LoopDetection {
 	if config.CooldownSeconds == 0 {
 		config.CooldownSeconds = 30 // Default: 30 second cooldown
 	}
+	if config.PollableMaxConsecutive == 0 {
+		config.PollableMaxConsecutive = DefaultPollableMaxConsecutive
+	}
 
 	return &LoopDetection{
 		config:    config,
@@ -85,6 +102,26 @@ func (ld *LoopDetection) generateCallHash(toolName string, args map[string]inter
 	return hex.EncodeToString(hash[:])
 }
 
+// maxConsecutiveFor returns the consecutive call limit of a tool call, preferring an override for
+// its action and resource over one for the action alone, then the softer limit of pollable calls
+func (ld *LoopDetection) maxConsecutiveFor(toolName string, args map[string]interface{}) int {
+	resource, _ := args["resource"].(string)
+	if resource != "" {
+		if limit, exists := ld.config.MaxConsecutiveOverrides[toolName+":"+resource]; exists {
+			return limit
+		}
+	}
+	if limit, exists := ld.config.MaxConsecutiveOverrides[toolName]; exists {
+		return limit
+	}
+	for _, pollable := range ld.config.PollableCalls {
+		if pollable == toolName || (resource != "" && pollable == toolName+":"+resource) {
+			return max(ld.config.PollableMaxConsecutive, ld.config.MaxConsecutiveCalls)
+		}
+	}
+	return ld.config.MaxConsecutiveCalls
+}
+
 // CheckForLoop checks if the current tool call would create a loop
 func (ld *LoopDetection) CheckForLoop(toolName string, args map[string]interface{}) LoopDetectionResult {
 	if !ld.config.Enabled {
@@ -96,6 +133,7 @@ func (ld *LoopDetection) CheckForLoop(toolName string, args map[string]interface
 
 	now := time.Now()
 	callHash := ld.generateCallHash(toolName, args)
+	maxConsecutive := ld.maxConsecutiveFor(toolName, args)
 
 	// Check if this call is in cooldown
 	ld.cooldownMu.RLock()
@@ -146,7 +184,7 @@ func (ld *LoopDetection) CheckForLoop(toolName string, args map[string]interface
 	}
 
 	// Check if we've exceeded the limit
-	if consecutiveCount > ld.config.MaxConsecutiveCalls {
+	if consecutiveCount > maxConsecutive {
 		// Set cooldown
 		cooldownEnd := now.Add(time.Duration(ld.config.CooldownSeconds) * time.Second)
 		ld.cooldownMu.Lock()
@@ -154,15 +192,15 @@ func (ld *LoopDetection) CheckForLoop(toolName string, args map[string]interface
 		ld.cooldownMu.Unlock()
 
 		logger.Debug("Loop detected: %s called %d times consecutively (max: %d). Cooldown until %s",
-			toolName, consecutiveCount, ld.config.MaxConsecutiveCalls, cooldownEnd.Format("15:04:05"))
+			toolName, consecutiveCount, maxConsecutive, cooldownEnd.Format("15:04:05"))
 
 		return LoopDetectionResult{
 			IsLoop:           true,
 			ConsecutiveCalls: consecutiveCount,
-			MaxAllowed:       ld.config.MaxConsecutiveCalls,
+			MaxAllowed:       maxConsecutive,
 			CooldownUntil:    &cooldownEnd,
 			Message: fmt.Sprintf("Loop detected: %s called %d times consecutively (max: %d). Cooldown applied until %s",
-				toolName, consecutiveCount, ld.config.MaxConsecutiveCalls, cooldownEnd.Format("15:04:05")),
+				toolName, consecutiveCount, maxConsecutive, cooldownEnd.Format("15:04:05")),
 		}
 	}
 
@@ -172,13 +210,13 @@ func (ld *LoopDetection) CheckForLoop(toolName string, args map[string]interface
 	// Log for monitoring
 	if consecutiveCount > 1 {
 		logger.Debug("Consecutive call detected: %s called %d times (max: %d)",
-			toolName, consecutiveCount, ld.config.MaxConsecutiveCalls)
+			toolName, consecutiveCount, maxConsecutive)
 	}
 
 	return LoopDetectionResult{
 		IsLoop:           false,
 		ConsecutiveCalls: consecutiveCount,
-		MaxAllowed:       ld.config.MaxConsecutiveCalls,
+		MaxAllowed:       maxConsecutive,
 	}
 }
 
@@ -233,6 +271,8 @@ func (ld *LoopDetection) GetStats() map[string]interface{} {
 		"recent_calls_count":  len(ld.callQueue),
 		"active_cooldowns":    len(ld.cooldowns),
 		"global_protection":   ld.config.EnableGlobalProtection,
+		"pollable_calls":      ld.config.PollableCalls,
+		"pollable_max":        ld.config.PollableMaxConsecutive,
 	}
 
 	return stats
@@ -1,6 +1,7 @@
 package guardrails
 
 import (
+	"mcolomerc/mcp-server/internal/config"
 	"testing"
 	"time"
 )
@@ -160,3 +161,84 @@ func TestHashGeneration(t *testing.T) {
 		t.Error("Different parameters should generate different hash")
 	}
 }
+
+func TestLoopDetectionOverrides(t *testing.T) {
+	detector := NewLoopDetection(LoopDetectionConfig{
+		Enabled:                 true,
+		MaxConsecutiveCalls:     2,
+		TimeWindowSeconds:       60,
+		CooldownSeconds:         30,
+		MaxConsecutiveOverrides: parseLoopOverrides("get:statements=5, list=3, bogus"),
+	})
+
+	tests := []struct {
+		name    string
+		tool    string
+		args    map[string]interface{}
+		allowed int
+	}{
+		{name: "polling resource", tool: "get", args: map[string]interface{}{"resource": "statements", "statement_name": "s-1"}, allowed: 5},
+		{name: "other resource of the action", tool: "get", args: map[string]interface{}{"resource": "topics", "topic_name": "orders"}, allowed: 2},
+		{name: "action override", tool: "list", args: map[string]interface{}{"resource": "topics"}, allowed: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 1; i <= tt.allowed; i++ {
+				if result := detector.CheckForLoop(tt.tool, tt.args); result.IsLoop {
+					t.Fatalf("Call %d should be allowed: %s", i, result.Message)
+				}
+			}
+			result := detector.CheckForLoop(tt.tool, tt.args)
+			if !result.IsLoop || result.MaxAllowed != tt.allowed {
+				t.Errorf("Expected a loop after %d calls, got %+v", tt.allowed, result)
+			}
+		})
+	}
+}
+
+func TestLoopDetectionPollableCalls(t *testing.T) {
+	detector := NewLoopDetection(LoopDetectionConfig{
+		Enabled:                 true,
+		MaxConsecutiveCalls:     2,
+		TimeWindowSeconds:       60,
+		CooldownSeconds:         30,
+		MaxConsecutiveOverrides: parseLoopOverrides("get:compute-pools=4"),
+		PollableCalls:           DefaultPollableCalls,
+		PollableMaxConsecutive:  6,
+	})
+
+	tests := []struct {
+		name    string
+		tool    string
+		args    map[string]interface{}
+		allowed int
+	}{
+		{name: "pollable resource", tool: "get", args: map[string]interface{}{"resource": "statements", "statement_name": "s-1"}, allowed: 6},
+		{name: "pollable tool", tool: "get_telemetry", args: map[string]interface{}{"resource": "query"}, allowed: 6},
+		{name: "other resource stays strict", tool: "get", args: map[string]interface{}{"resource": "topics", "topic_name": "orders"}, allowed: 2},
+		{name: "other action on a pollable resource stays strict", tool: "delete", args: map[string]interface{}{"resource": "statements", "statement_name": "s-1"}, allowed: 2},
+		{name: "override wins over pollable", tool: "get", args: map[string]interface{}{"resource": "compute-pools", "id": "lfcp-1"}, allowed: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 1; i <= tt.allowed; i++ {
+				if result := detector.CheckForLoop(tt.tool, tt.args); result.IsLoop {
+					t.Fatalf("Call %d should be allowed: %s", i, result.Message)
+				}
+			}
+			result := detector.CheckForLoop(tt.tool, tt.args)
+			if !result.IsLoop || result.MaxAllowed != tt.allowed {
+				t.Errorf("Expected a loop after %d calls, got %+v", tt.allowed, result)
+			}
+		})
+	}
+
+	t.Run("Pollable calls can be configured away", func(t *testing.T) {
+		t.Setenv("LOOP_DETECTION_POLLABLE", "")
+		t.Setenv("LOOP_DETECTION_MAX_CONSECUTIVE", "2")
+		loops := NewCompositeGuardrails(&config.Config{}).GetLoopDetector()
+		if limit := loops.maxConsecutiveFor("get", map[string]interface{}{"resource": "statements"}); limit != 2 {
+			t.Errorf("Expected the strict limit without pollable calls, got %d", limit)
+		}
+	})
+}
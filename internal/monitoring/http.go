@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"time"
 )
 
 // HTTPHandler provides HTTP endpoints for metrics
@@ -105,4 +107,81 @@ func (h *HTTPHandler) PrometheusHandler(w http.ResponseWriter, r *http.Request)
 	fmt.Fprintf(w, "# HELP mcp_cgo_calls_total Total number of CGO calls\n")
 	fmt.Fprintf(w, "# TYPE mcp_cgo_calls_total counter\n")
 	fmt.Fprintf(w, "mcp_cgo_calls_total %d\n", metrics.CPU.NumCgoCall)
+
+	if metrics.Invocations != nil {
+		fmt.Fprintf(w, "# HELP mcp_invocations_in_flight Number of tool invocations currently running\n")
+		fmt.Fprintf(w, "# TYPE mcp_invocations_in_flight gauge\n")
+		fmt.Fprintf(w, "mcp_invocations_in_flight %d\n", metrics.Invocations.InFlight)
+
+		fmt.Fprintf(w, "# HELP mcp_invocations_max_concurrent Configured concurrent invocation limit (0 means unlimited)\n")
+		fmt.Fprintf(w, "# TYPE mcp_invocations_max_concurrent gauge\n")
+		fmt.Fprintf(w, "mcp_invocations_max_concurrent %d\n", metrics.Invocations.MaxConcurrent)
+
+		fmt.Fprintf(w, "# HELP mcp_invocations_rejected_total Tool invocations rejected because the limit was reached\n")
+		fmt.Fprintf(w, "# TYPE mcp_invocations_rejected_total counter\n")
+		fmt.Fprintf(w, "mcp_invocations_rejected_total %d\n", metrics.Invocations.Rejected)
+	}
+
+	if metrics.Coverage != nil {
+		fmt.Fprintf(w, "# HELP mcp_registry_spec_paths Number of paths in the OpenAPI spec\n")
+		fmt.Fprintf(w, "# TYPE mcp_registry_spec_paths gauge\n")
+		fmt.Fprintf(w, "mcp_registry_spec_paths %d\n", metrics.Coverage.SpecPaths)
+
+		fmt.Fprintf(w, "# HELP mcp_registry_mapped_paths Number of spec paths mapped to a semantic action\n")
+		fmt.Fprintf(w, "# TYPE mcp_registry_mapped_paths gauge\n")
+		fmt.Fprintf(w, "mcp_registry_mapped_paths %d\n", metrics.Coverage.MappedPaths)
+
+		fmt.Fprintf(w, "# HELP mcp_registry_tools Number of generated tools\n")
+		fmt.Fprintf(w, "# TYPE mcp_registry_tools gauge\n")
+		fmt.Fprintf(w, "mcp_registry_tools %d\n", metrics.Coverage.Tools)
+
+		fmt.Fprintf(w, "# HELP mcp_registry_action_resources Number of resources supported per action\n")
+		fmt.Fprintf(w, "# TYPE mcp_registry_action_resources gauge\n")
+		for _, action := range sortedKeys(metrics.Coverage.ResourcesPerAction) {
+			fmt.Fprintf(w, "mcp_registry_action_resources{action=%q} %d\n", action, metrics.Coverage.ResourcesPerAction[action])
+		}
+
+		skipped := metrics.Coverage.SkippedByReason()
+		fmt.Fprintf(w, "# HELP mcp_registry_skipped_paths Number of spec paths without a mapping per reason\n")
+		fmt.Fprintf(w, "# TYPE mcp_registry_skipped_paths gauge\n")
+		for _, reason := range sortedKeys(skipped) {
+			fmt.Fprintf(w, "mcp_registry_skipped_paths{reason=%q} %d\n", reason, skipped[reason])
+		}
+	}
+
+	if metrics.Inventory != nil {
+		fmt.Fprintf(w, "# HELP mcp_prompts_loaded Number of loaded prompts\n")
+		fmt.Fprintf(w, "# TYPE mcp_prompts_loaded gauge\n")
+		fmt.Fprintf(w, "mcp_prompts_loaded %d\n", metrics.Inventory.Prompts)
+
+		fmt.Fprintf(w, "# HELP mcp_tools_loaded Number of generated and custom tools\n")
+		fmt.Fprintf(w, "# TYPE mcp_tools_loaded gauge\n")
+		fmt.Fprintf(w, "mcp_tools_loaded %d\n", metrics.Inventory.Tools)
+
+		fmt.Fprintf(w, "# HELP mcp_spec_last_load_timestamp_seconds Unix time the OpenAPI specs were last loaded\n")
+		fmt.Fprintf(w, "# TYPE mcp_spec_last_load_timestamp_seconds gauge\n")
+		fmt.Fprintf(w, "mcp_spec_last_load_timestamp_seconds %d\n", unixSeconds(metrics.Inventory.SpecLoadedAt))
+
+		fmt.Fprintf(w, "# HELP mcp_prompts_last_load_timestamp_seconds Unix time prompts were last loaded (0 if never)\n")
+		fmt.Fprintf(w, "# TYPE mcp_prompts_last_load_timestamp_seconds gauge\n")
+		fmt.Fprintf(w, "mcp_prompts_last_load_timestamp_seconds %d\n", unixSeconds(metrics.Inventory.PromptsLoadedAt))
+	}
+}
+
+// unixSeconds returns a time as Unix seconds, or 0 for the zero time
+func unixSeconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// sortedKeys returns the keys of a count map in sorted order, for stable output
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
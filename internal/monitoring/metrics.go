@@ -10,10 +10,13 @@ import (
 
 // ResourceMetrics holds various system and runtime metrics
 type ResourceMetrics struct {
-	Memory     MemoryMetrics `json:"memory"`
-	CPU        CPUMetrics    `json:"cpu"`
-	Goroutines int           `json:"goroutines"`
-	Timestamp  time.Time     `json:"timestamp"`
+	Memory      MemoryMetrics      `json:"memory"`
+	CPU         CPUMetrics         `json:"cpu"`
+	Goroutines  int                `json:"goroutines"`
+	Invocations *InvocationMetrics `json:"invocations,omitempty"`
+	Coverage    *CoverageMetrics   `json:"coverage,omitempty"`
+	Inventory   *InventoryMetrics  `json:"inventory,omitempty"`
+	Timestamp   time.Time          `json:"timestamp"`
 }
 
 // MemoryMetrics holds memory-related metrics
@@ -38,10 +41,46 @@ type CPUMetrics struct {
 	NumCgoCall int64 `json:"num_cgo_call"`
 }
 
+// InvocationMetrics holds tool invocation concurrency metrics
+type InvocationMetrics struct {
+	InFlight      int64 `json:"in_flight"`
+	MaxConcurrent int64 `json:"max_concurrent"` // 0 means unlimited
+	Rejected      int64 `json:"rejected"`
+}
+
+// CoverageMetrics holds how much of the OpenAPI spec the generated tools cover
+type CoverageMetrics struct {
+	SpecPaths          int               `json:"spec_paths"`
+	MappedPaths        int               `json:"mapped_paths"`
+	Tools              int               `json:"tools"`
+	ResourcesPerAction map[string]int    `json:"resources_per_action"`
+	SkippedPaths       map[string]string `json:"skipped_paths,omitempty"` // path -> reason
+}
+
+// SkippedByReason counts the skipped paths per reason
+func (c CoverageMetrics) SkippedByReason() map[string]int {
+	counts := make(map[string]int)
+	for _, reason := range c.SkippedPaths {
+		counts[reason]++
+	}
+	return counts
+}
+
+// InventoryMetrics holds how many prompts and tools are loaded, and when they were last loaded
+type InventoryMetrics struct {
+	Prompts         int       `json:"prompts"`
+	Tools           int       `json:"tools"` // Generated and custom tools
+	SpecLoadedAt    time.Time `json:"spec_loaded_at"`
+	PromptsLoadedAt time.Time `json:"prompts_loaded_at"` // Zero until prompts load successfully
+}
+
 // Monitor represents a resource monitor
 type Monitor struct {
-	interval time.Duration
-	stopCh   chan struct{}
+	interval           time.Duration
+	stopCh             chan struct{}
+	invocationProvider func() InvocationMetrics
+	coverageProvider   func() CoverageMetrics
+	inventoryProvider  func() InventoryMetrics
 }
 
 // NewMonitor creates a new resource monitor
@@ -80,9 +119,39 @@ func (m *Monitor) GetCurrentMetrics() *ResourceMetrics {
 		Timestamp:  time.Now(),
 	}
 
+	if m.invocationProvider != nil {
+		invocations := m.invocationProvider()
+		metrics.Invocations = &invocations
+	}
+
+	if m.coverageProvider != nil {
+		coverage := m.coverageProvider()
+		metrics.Coverage = &coverage
+	}
+
+	if m.inventoryProvider != nil {
+		inventory := m.inventoryProvider()
+		metrics.Inventory = &inventory
+	}
+
 	return metrics
 }
 
+// SetInvocationMetricsProvider sets the source of tool invocation metrics
+func (m *Monitor) SetInvocationMetricsProvider(provider func() InvocationMetrics) {
+	m.invocationProvider = provider
+}
+
+// SetCoverageMetricsProvider sets the source of spec coverage metrics
+func (m *Monitor) SetCoverageMetricsProvider(provider func() CoverageMetrics) {
+	m.coverageProvider = provider
+}
+
+// SetInventoryMetricsProvider sets the source of prompt and tool inventory metrics
+func (m *Monitor) SetInventoryMetricsProvider(provider func() InventoryMetrics) {
+	m.inventoryProvider = provider
+}
+
 // StartPeriodicLogging starts periodic logging of metrics
 func (m *Monitor) StartPeriodicLogging(ctx context.Context) {
 	ticker := time.NewTicker(m.interval)
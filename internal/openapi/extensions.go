@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExtensionPrefix starts the names of specification extension fields, e.g. x-ratelimit-limit
+const ExtensionPrefix = "x-"
+
+// RateLimitExtensionPrefix starts the names of the rate-limit extensions of an operation
+const RateLimitExtensionPrefix = "x-ratelimit-"
+
+// operationFields has the fields of Operation without its unmarshal methods
+type operationFields Operation
+
+// UnmarshalJSON decodes an operation and collects its x- prefixed extension fields
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	var fields operationFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*o = Operation(fields)
+	o.Extensions = extensionFields(raw)
+	return nil
+}
+
+// UnmarshalYAML decodes an operation and collects its x- prefixed extension fields
+func (o *Operation) UnmarshalYAML(value *yaml.Node) error {
+	var fields operationFields
+	if err := value.Decode(&fields); err != nil {
+		return err
+	}
+	var raw map[string]interface{}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	*o = Operation(fields)
+	o.Extensions = extensionFields(raw)
+	return nil
+}
+
+// extensionFields returns the x- prefixed entries of a decoded object, or nil when it has none
+func extensionFields(raw map[string]interface{}) map[string]interface{} {
+	var extensions map[string]interface{}
+	for name, value := range raw {
+		if !strings.HasPrefix(strings.ToLower(name), ExtensionPrefix) {
+			continue
+		}
+		if extensions == nil {
+			extensions = make(map[string]interface{})
+		}
+		extensions[name] = value
+	}
+	return extensions
+}
+
+// RateLimitHints returns the x-ratelimit-* extensions of the operation keyed by the rest of their
+// name, e.g. x-ratelimit-limit: 100 becomes "limit": "100". Nil when the spec declares none.
+func (o *Operation) RateLimitHints() map[string]string {
+	var hints map[string]string
+	for name, value := range o.Extensions {
+		lower := strings.ToLower(name)
+		if !strings.HasPrefix(lower, RateLimitExtensionPrefix) || len(lower) == len(RateLimitExtensionPrefix) {
+			continue
+		}
+		if hints == nil {
+			hints = make(map[string]string)
+		}
+		hints[strings.TrimPrefix(lower, RateLimitExtensionPrefix)] = fmt.Sprint(value)
+	}
+	return hints
+}
@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultSpecFetchTimeout bounds a remote spec download unless SPEC_FETCH_TIMEOUT overrides it
+	DefaultSpecFetchTimeout = 30 * time.Second
+	// DefaultSpecMaxBytes caps the size of a remote spec unless SPEC_MAX_BYTES overrides it
+	DefaultSpecMaxBytes int64 = 50 << 20
+)
+
+// errSpecTooLarge reports a remote spec above SPEC_MAX_BYTES
+var errSpecTooLarge = errors.New("spec exceeds SPEC_MAX_BYTES")
+
+// specFetchTimeout returns the SPEC_FETCH_TIMEOUT in seconds, or the default
+func specFetchTimeout() time.Duration {
+	if seconds, err := strconv.Atoi(os.Getenv("SPEC_FETCH_TIMEOUT")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return DefaultSpecFetchTimeout
+}
+
+// specMaxBytes returns the SPEC_MAX_BYTES, or the default
+func specMaxBytes() int64 {
+	if maxBytes, err := strconv.ParseInt(os.Getenv("SPEC_MAX_BYTES"), 10, 64); err == nil && maxBytes > 0 {
+		return maxBytes
+	}
+	return DefaultSpecMaxBytes
+}
+
+// fetchRemoteSpec downloads a spec within the SPEC_FETCH_TIMEOUT and SPEC_MAX_BYTES limits, so a
+// slow or huge remote spec fails startup with a clear error instead of hanging it. name is used
+// in errors, e.g. "OpenAPI spec".
+func fetchRemoteSpec(specURL, name string) ([]byte, error) {
+	timeout := specFetchTimeout()
+	client := &http.Client{Timeout: timeout}
+
+	resp, err := client.Get(specURL)
+	if err != nil {
+		if isTimeout(err) {
+			return nil, fmt.Errorf("failed to fetch %s from remote: no response within %v (SPEC_FETCH_TIMEOUT): %w", name, timeout, err)
+		}
+		return nil, fmt.Errorf("failed to fetch %s from remote: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: HTTP %d", name, resp.StatusCode)
+	}
+
+	maxBytes := specMaxBytes()
+	if resp.ContentLength > maxBytes {
+		return nil, fmt.Errorf("failed to fetch %s: %w (%d bytes, limit %d)", name, errSpecTooLarge, resp.ContentLength, maxBytes)
+	}
+	// Read one byte past the limit to tell a spec of exactly maxBytes from a larger one
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		if isTimeout(err) {
+			return nil, fmt.Errorf("failed to read %s body: not complete within %v (SPEC_FETCH_TIMEOUT): %w", name, timeout, err)
+		}
+		return nil, fmt.Errorf("failed to read %s body: %w", name, err)
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, fmt.Errorf("failed to fetch %s: %w (limit %d bytes)", name, errSpecTooLarge, maxBytes)
+	}
+	return body, nil
+}
+
+// isTimeout reports whether err is a timeout of the HTTP client
+func isTimeout(err error) bool {
+	var timeoutErr interface{ Timeout() bool }
+	return errors.As(err, &timeoutErr) && timeoutErr.Timeout()
+}
@@ -0,0 +1,91 @@
+package openapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadSpecRemoteLimits(t *testing.T) {
+	const spec = `{"openapi": "3.0.0", "paths": {"/org/v2/environments": {"get": {}}}}`
+
+	t.Run("Spec within the limits loads", func(t *testing.T) {
+		apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(spec))
+		}))
+		defer apiServer.Close()
+		t.Setenv("OPENAPI_SPEC_URL", apiServer.URL+"/spec.json")
+		t.Setenv("SPEC_MAX_BYTES", "1024")
+
+		loaded, err := LoadSpec()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, ok := loaded.Paths["/org/v2/environments"]; !ok {
+			t.Errorf("Expected the spec paths, got %v", loaded.Paths)
+		}
+	})
+
+	t.Run("Slow spec times out", func(t *testing.T) {
+		release := make(chan struct{})
+		apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-time.After(5 * time.Second):
+			}
+			w.Write([]byte(spec))
+		}))
+		defer apiServer.Close()
+		defer close(release)
+		t.Setenv("OPENAPI_SPEC_URL", apiServer.URL+"/spec.json")
+		t.Setenv("SPEC_FETCH_TIMEOUT", "1")
+
+		_, err := LoadSpec()
+		if err == nil || !strings.Contains(err.Error(), "SPEC_FETCH_TIMEOUT") {
+			t.Errorf("Expected a SPEC_FETCH_TIMEOUT error, got %v", err)
+		}
+	})
+
+	t.Run("Oversized spec is rejected", func(t *testing.T) {
+		for _, declareLength := range []bool{true, false} {
+			apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !declareLength {
+					// Flushing first sends the body chunked, without a Content-Length
+					w.(http.Flusher).Flush()
+				}
+				w.Write([]byte(spec))
+			}))
+			t.Setenv("TELEMETRY_OPENAPI_SPEC_URL", apiServer.URL+"/telemetry.json")
+			t.Setenv("SPEC_MAX_BYTES", "16")
+
+			_, err := LoadTelemetrySpec()
+			apiServer.Close()
+			if !errors.Is(err, errSpecTooLarge) {
+				t.Errorf("Expected an oversized spec error (content length declared: %v), got %v", declareLength, err)
+			}
+		}
+	})
+}
+
+func TestLoadSpecInterpolatesURL(t *testing.T) {
+	var requested string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.Write([]byte(`{"openapi": "3.0.0", "paths": {"/org/v2/environments": {"get": {}}}}`))
+	}))
+	defer apiServer.Close()
+
+	t.Setenv("SPEC_HOST", strings.TrimPrefix(apiServer.URL, "http://"))
+	t.Setenv("SPEC_REGION", "eu-west-1")
+	t.Setenv("OPENAPI_SPEC_URL", "http://${SPEC_HOST}/${SPEC_REGION}/spec.json")
+
+	if _, err := LoadSpec(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requested != "/eu-west-1/spec.json" {
+		t.Errorf("Expected the interpolated spec path, got %q", requested)
+	}
+}
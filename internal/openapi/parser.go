@@ -1,12 +1,16 @@
 package openapi
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mcolomerc/mcp-server/internal/config"
 	"mcolomerc/mcp-server/internal/logger"
-	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -15,7 +19,9 @@ import (
 // OpenAPISpec represents the structure of the OpenAPI specification.
 type OpenAPISpec struct {
 	OpenAPI    string                `json:"openapi"`
+	Swagger    string                `json:"swagger,omitempty"` // Set by Swagger 2.0 documents, which are rejected
 	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
 	Paths      map[string]PathItem   `json:"paths"`
 	Security   []map[string][]string `json:"security,omitempty"`
 	Components *Components           `json:"components,omitempty"`
@@ -25,6 +31,7 @@ type OpenAPISpec struct {
 type Components struct {
 	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
 	RequestBodies   map[string]RequestBody    `json:"requestBodies,omitempty"`
+	Responses       map[string]Response       `json:"responses,omitempty"`
 	Schemas         map[string]Schema         `json:"schemas,omitempty"`
 	// ... add other component fields as needed ...
 }
@@ -55,31 +62,43 @@ type PathItem struct {
 	Delete *Operation `json:"delete,omitempty"`
 	Patch  *Operation `json:"patch,omitempty"`
 	// Add other HTTP methods as needed
+	Servers []Server `json:"servers,omitempty"` // Overrides the spec-level servers for this path
 }
 
 // Operation describes a single API operation.
 type Operation struct {
-	Summary     string                `json:"summary"`
-	Description string                `json:"description"`
-	Parameters  []Parameter           `json:"parameters,omitempty"`
-	RequestBody *RequestBody          `json:"requestBody,omitempty"`
-	Security    []map[string][]string `json:"security,omitempty"`
+	OperationID string                 `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Summary     string                 `json:"summary"`
+	Description string                 `json:"description"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]Response    `json:"responses,omitempty"` // Keyed by status code, e.g. "200" or "default"
+	Security    []map[string][]string  `json:"security,omitempty"`
+	Deprecated  bool                   `json:"deprecated,omitempty"`
+	Tags        []string               `json:"tags,omitempty"` // Groups the operation belongs to, usually its service or resource
+	Extensions  map[string]interface{} `json:"-" yaml:"-"`     // x- prefixed specification extensions, e.g. x-ratelimit-limit
 }
 
 // Parameter describes a single parameter for an operation.
 type Parameter struct {
-	Name     string  `json:"name"`
-	In       string  `json:"in"` // e.g., "query", "header", "path", "cookie"
-	Required bool    `json:"required"`
-	Schema   *Schema `json:"schema,omitempty"`
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // e.g., "query", "header", "path", "cookie"
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema,omitempty"`
+	Style       string  `json:"style,omitempty"`   // Serialization style, e.g. "form" or "pipeDelimited"
+	Explode     *bool   `json:"explode,omitempty"` // Nil means the style's default
 }
 
 // Schema describes the structure of a parameter's schema.
 type Schema struct {
-	Type       string             `json:"type"`
-	Properties map[string]*Schema `json:"properties,omitempty"`
-	Required   []string           `json:"required,omitempty"`
-	Items      *Schema            `json:"items,omitempty"`
+	Ref         string             `json:"$ref,omitempty"` // Reference to a components schema, e.g. the items of an array
+	Type        string             `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []interface{}      `json:"enum,omitempty"`
 }
 
 // RequestBody describes the request body of an operation.
@@ -88,9 +107,43 @@ type RequestBody struct {
 	Content map[string]MediaType `json:"content,omitempty"`
 }
 
+// Response describes a response of an operation.
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
 // MediaType describes a single media type.
 type MediaType struct {
-	Schema interface{} `json:"schema,omitempty"`
+	Schema   interface{}        `json:"schema,omitempty"`
+	Example  interface{}        `json:"example,omitempty"`
+	Examples map[string]Example `json:"examples,omitempty"`
+}
+
+// Example describes a named example of a media type.
+type Example struct {
+	Summary string      `json:"summary,omitempty"`
+	Value   interface{} `json:"value,omitempty"`
+}
+
+// ExampleValue returns the example of the media type: its example, or else the value of the
+// first named example in name order. It returns nil when the media type has no example.
+func (m MediaType) ExampleValue() interface{} {
+	if m.Example != nil {
+		return m.Example
+	}
+	names := make([]string, 0, len(m.Examples))
+	for name := range m.Examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if value := m.Examples[name].Value; value != nil {
+			return value
+		}
+	}
+	return nil
 }
 
 // ParseOpenAPISpec reads and parses the OpenAPI specification from a file.
@@ -106,12 +159,7 @@ func ParseOpenAPISpec(filePath string) (*OpenAPISpec, error) {
 		return nil, err
 	}
 
-	var spec OpenAPISpec
-	if err := json.Unmarshal(bytes, &spec); err != nil {
-		return nil, err
-	}
-
-	return &spec, nil
+	return ParseOpenAPISpecBytes(bytes)
 }
 
 // ParseOpenAPISpecBytes parses the OpenAPI spec from a byte slice.
@@ -120,6 +168,9 @@ func ParseOpenAPISpecBytes(data []byte) (*OpenAPISpec, error) {
 	if err := json.Unmarshal(data, &spec); err != nil {
 		return nil, err
 	}
+	if err := checkSpecVersion(&spec); err != nil {
+		return nil, err
+	}
 	return &spec, nil
 }
 
@@ -136,12 +187,7 @@ func ParseOpenAPISpecYAML(filename string) (*OpenAPISpec, error) {
 		return nil, err
 	}
 
-	var spec OpenAPISpec
-	if err := yaml.Unmarshal(bytes, &spec); err != nil {
-		return nil, err
-	}
-
-	return &spec, nil
+	return ParseOpenAPISpecBytesYAML(bytes)
 }
 
 // ParseOpenAPISpecBytesYAML parses the OpenAPI spec from a YAML byte slice.
@@ -150,28 +196,35 @@ func ParseOpenAPISpecBytesYAML(data []byte) (*OpenAPISpec, error) {
 	if err := yaml.Unmarshal(data, &spec); err != nil {
 		return nil, err
 	}
+	if err := checkSpecVersion(&spec); err != nil {
+		return nil, err
+	}
 	return &spec, nil
 }
 
+// checkSpecVersion rejects documents the parser cannot model. Only OpenAPI 3.0.x and 3.1.x are
+// supported; a Swagger 2.0 file would otherwise parse without error and produce zero tools.
+func checkSpecVersion(spec *OpenAPISpec) error {
+	if spec.Swagger != "" {
+		return fmt.Errorf("unsupported spec version: Swagger %s documents are not supported, convert the spec to OpenAPI 3.0 or 3.1", spec.Swagger)
+	}
+	if spec.OpenAPI != "" && !strings.HasPrefix(spec.OpenAPI, "3.0") && !strings.HasPrefix(spec.OpenAPI, "3.1") {
+		return fmt.Errorf("unsupported spec version: OpenAPI %s, expected 3.0.x or 3.1.x", spec.OpenAPI)
+	}
+	return nil
+}
+
 // LoadSpec loads an OpenAPI spec from a file path or URL, or from the default if empty.
 func LoadSpec() (*OpenAPISpec, error) {
-	specPath := os.Getenv("OPENAPI_SPEC_URL")
+	specPath := config.InterpolateEnv(os.Getenv("OPENAPI_SPEC_URL"))
 	if specPath == "" {
 		specPath = "api-spec/confluent-apispec.json"
 	}
 
 	if strings.HasPrefix(specPath, "http://") || strings.HasPrefix(specPath, "https://") {
-		resp, err := http.Get(specPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch OpenAPI spec from remote: %w", err)
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != 200 {
-			return nil, fmt.Errorf("failed to fetch OpenAPI spec: HTTP %d", resp.StatusCode)
-		}
-		body, err := io.ReadAll(resp.Body)
+		body, err := fetchRemoteSpec(specPath, "OpenAPI spec")
 		if err != nil {
-			return nil, fmt.Errorf("failed to read OpenAPI spec body: %w", err)
+			return nil, err
 		}
 		return ParseOpenAPISpecBytes(body)
 	}
@@ -180,32 +233,51 @@ func LoadSpec() (*OpenAPISpec, error) {
 
 // LoadTelemetrySpec loads the Confluent Telemetry OpenAPI spec from a file path or URL.
 func LoadTelemetrySpec() (*OpenAPISpec, error) {
-	specPath := os.Getenv("TELEMETRY_OPENAPI_SPEC_URL")
+	specPath := config.InterpolateEnv(os.Getenv("TELEMETRY_OPENAPI_SPEC_URL"))
 	if specPath == "" {
 		specPath = "api-spec/confluent-telemetry-apispec.yaml"
 	}
 
 	if strings.HasPrefix(specPath, "http://") || strings.HasPrefix(specPath, "https://") {
-		resp, err := http.Get(specPath)
+		body, err := fetchRemoteSpec(specPath, "Telemetry OpenAPI spec")
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch Telemetry OpenAPI spec from remote: %w", err)
+			return nil, err
 		}
-		defer resp.Body.Close()
-		if resp.StatusCode != 200 {
-			return nil, fmt.Errorf("failed to fetch Telemetry OpenAPI spec: HTTP %d", resp.StatusCode)
-		}
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read Telemetry OpenAPI spec body: %w", err)
-		}
-		return ParseOpenAPISpecBytesYAML(body)
+		return parseSpecBytesForPath(remotePath(specPath), body)
 	}
 
-	// Determine if it's YAML or JSON based on file extension
-	if strings.HasSuffix(strings.ToLower(specPath), ".yaml") || strings.HasSuffix(strings.ToLower(specPath), ".yml") {
-		return ParseOpenAPISpecYAML(specPath)
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, err
 	}
-	return ParseOpenAPISpec(specPath)
+	return parseSpecBytesForPath(specPath, data)
+}
+
+// parseSpecBytesForPath parses a spec as YAML or JSON based on the path extension. Paths
+// without a .json/.yaml/.yml extension are sniffed from the content instead.
+func parseSpecBytesForPath(specPath string, data []byte) (*OpenAPISpec, error) {
+	switch strings.ToLower(path.Ext(specPath)) {
+	case ".yaml", ".yml":
+		return ParseOpenAPISpecBytesYAML(data)
+	case ".json":
+		return ParseOpenAPISpecBytes(data)
+	}
+
+	// A JSON document starts with '{'; anything else is treated as YAML
+	content := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if len(content) > 0 && content[0] == '{' {
+		return ParseOpenAPISpecBytes(data)
+	}
+	return ParseOpenAPISpecBytesYAML(data)
+}
+
+// remotePath returns the path portion of a spec URL so query strings don't hide the extension
+func remotePath(specURL string) string {
+	parsed, err := url.Parse(specURL)
+	if err != nil {
+		return specURL
+	}
+	return parsed.Path
 }
 
 // LoadBothSpecs loads both the main Confluent API spec and the Telemetry API spec.
@@ -215,9 +287,15 @@ func LoadBothSpecs() (*OpenAPISpec, *OpenAPISpec, error) {
 		return nil, nil, fmt.Errorf("failed to load main OpenAPI spec: %w", err)
 	}
 
+	// Telemetry is optional unless a telemetry spec was explicitly configured: without it the
+	// server runs with the main spec tools only
 	telemetrySpec, err := LoadTelemetrySpec()
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to load telemetry OpenAPI spec: %w", err)
+		if os.Getenv("TELEMETRY_OPENAPI_SPEC_URL") != "" {
+			return nil, nil, fmt.Errorf("failed to load telemetry OpenAPI spec: %w", err)
+		}
+		logger.Info("Warning: telemetry OpenAPI spec not loaded, continuing without telemetry tools: %v\n", err)
+		telemetrySpec = &OpenAPISpec{}
 	}
 
 	return mainSpec, telemetrySpec, nil
@@ -261,6 +339,43 @@ func (spec *OpenAPISpec) ResolveRequestBodyRef(requestBody *RequestBody) *Reques
 	return requestBody
 }
 
+// ResolveResponseRef resolves a reference to a response component. A reference that cannot be
+// resolved is returned unchanged.
+func (spec *OpenAPISpec) ResolveResponseRef(response Response) Response {
+	if !strings.HasPrefix(response.Ref, "#/components/responses/") || spec.Components == nil {
+		return response
+	}
+	if resolved, exists := spec.Components.Responses[strings.TrimPrefix(response.Ref, "#/components/responses/")]; exists {
+		return resolved
+	}
+	return response
+}
+
+// SuccessResponseContentTypes returns the media types an operation declares for its 2xx
+// responses, sorted, or nil if the spec does not define the operation
+func (spec *OpenAPISpec) SuccessResponseContentTypes(method, path string) []string {
+	operation := spec.FindOperation(method, path)
+	if operation == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var contentTypes []string
+	for code, response := range operation.Responses {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		for contentType := range spec.ResolveResponseRef(response).Content {
+			if !seen[contentType] {
+				seen[contentType] = true
+				contentTypes = append(contentTypes, contentType)
+			}
+		}
+	}
+	sort.Strings(contentTypes)
+	return contentTypes
+}
+
 // ResolveSchemaRef resolves a schema reference if needed
 func (spec *OpenAPISpec) ResolveSchemaRef(schema interface{}) interface{} {
 	logger.Debug("ResolveSchemaRef called with schema: %+v\n", schema)
@@ -269,6 +384,11 @@ func (spec *OpenAPISpec) ResolveSchemaRef(schema interface{}) interface{} {
 		return nil
 	}
 
+	// A parsed schema keeps only its reference, e.g. the items of an array property
+	if typed, ok := schema.(*Schema); ok && typed != nil && typed.Ref != "" {
+		return spec.ResolveSchemaRef(map[string]interface{}{"$ref": typed.Ref})
+	}
+
 	// Check if it's a map with a $ref
 	if schemaMap, ok := schema.(map[string]interface{}); ok {
 		if ref, hasRef := schemaMap["$ref"]; hasRef {
@@ -326,19 +446,7 @@ func (spec *OpenAPISpec) GetSecurityTypeForEndpoint(method, path string) string
 	}
 
 	// Get the operation for the HTTP method
-	var operation *Operation
-	switch strings.ToUpper(method) {
-	case "GET":
-		operation = pathItem.Get
-	case "POST":
-		operation = pathItem.Post
-	case "PUT":
-		operation = pathItem.Put
-	case "DELETE":
-		operation = pathItem.Delete
-	case "PATCH":
-		operation = pathItem.Patch
-	}
+	operation := pathItem.operationForMethod(method)
 
 	if operation == nil {
 		// Fall back to global security if operation not found but path exists
@@ -361,6 +469,51 @@ func (spec *OpenAPISpec) GetSecurityTypeForEndpoint(method, path string) string
 	return "" // No security found
 }
 
+// FindParameter returns the definition of a parameter of the given location ("query", "path", ...)
+// for a method and request path, or nil if the spec does not declare it
+func (spec *OpenAPISpec) FindParameter(method, path, in, name string) *Parameter {
+	operation := spec.FindOperation(method, path)
+	if operation == nil {
+		return nil
+	}
+	for i := range operation.Parameters {
+		if operation.Parameters[i].Name == name && operation.Parameters[i].In == in {
+			return &operation.Parameters[i]
+		}
+	}
+	return nil
+}
+
+// FindOperation returns the operation for a method and path (a request path or a path template),
+// or nil if the spec does not define it
+func (spec *OpenAPISpec) FindOperation(method, path string) *Operation {
+	if spec == nil || spec.Paths == nil {
+		return nil
+	}
+	pathItem := spec.findPathItem(path)
+	if pathItem == nil {
+		return nil
+	}
+	return pathItem.operationForMethod(method)
+}
+
+// operationForMethod returns the path item's operation for an HTTP method
+func (p *PathItem) operationForMethod(method string) *Operation {
+	switch strings.ToUpper(method) {
+	case "GET":
+		return p.Get
+	case "POST":
+		return p.Post
+	case "PUT":
+		return p.Put
+	case "DELETE":
+		return p.Delete
+	case "PATCH":
+		return p.Patch
+	}
+	return nil
+}
+
 // findPathItem finds the path item for a given path, supporting OpenAPI path templates
 func (spec *OpenAPISpec) findPathItem(path string) *PathItem {
 	// Try exact match first
@@ -393,8 +546,8 @@ func matchesPathPattern(requestPath, specPath string) bool {
 		if strings.HasPrefix(specPart, "{") && strings.HasSuffix(specPart, "}") {
 			continue
 		}
-		// Otherwise, require exact match
-		if requestParts[i] != specPart {
+		// Otherwise, require the same segment, comparing percent-encoded forms decoded
+		if !pathSegmentsEqual(requestParts[i], specPart) {
 			return false
 		}
 	}
@@ -402,6 +555,17 @@ func matchesPathPattern(requestPath, specPath string) bool {
 	return true
 }
 
+// pathSegmentsEqual reports whether two path segments are the same once percent-decoded, so
+// "my%20topics" matches "my topics". Segments that are not valid escapes are compared as is.
+func pathSegmentsEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	decodedA, errA := url.PathUnescape(a)
+	decodedB, errB := url.PathUnescape(b)
+	return errA == nil && errB == nil && decodedA == decodedB
+}
+
 // extractSecurityType extracts the security type from a security requirement array
 func extractSecurityType(securityRequirements []map[string][]string) string {
 	if len(securityRequirements) == 0 {
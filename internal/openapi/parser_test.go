@@ -1,7 +1,14 @@
 package openapi
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestParseOpenAPISpecBytes(t *testing.T) {
@@ -116,6 +123,66 @@ func TestParseOpenAPISpecBytes_EmptyJSON(t *testing.T) {
 	}
 }
 
+func TestParseOpenAPISpecBytes_Version(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		yaml        bool
+		expectError string
+	}{
+		{
+			name:        "Swagger 2.0 is rejected",
+			data:        `{"swagger": "2.0", "info": {"title": "Legacy API", "version": "1.0"}, "paths": {}}`,
+			expectError: "Swagger 2.0",
+		},
+		{
+			name:        "Swagger 2.0 YAML is rejected",
+			data:        "swagger: \"2.0\"\ninfo:\n  title: Legacy API\npaths: {}\n",
+			yaml:        true,
+			expectError: "Swagger 2.0",
+		},
+		{
+			name:        "Unknown OpenAPI major version is rejected",
+			data:        `{"openapi": "4.0.0", "info": {"title": "Future API", "version": "1.0"}, "paths": {}}`,
+			expectError: "OpenAPI 4.0.0",
+		},
+		{
+			name: "OpenAPI 3.1 is accepted",
+			data: `{"openapi": "3.1.0", "info": {"title": "Test API", "version": "1.0"}, "paths": {"/topics": {"get": {"summary": "List topics"}}}}`,
+		},
+		{
+			name: "OpenAPI 3.0 YAML is accepted",
+			data: "openapi: 3.0.3\ninfo:\n  title: Test API\npaths: {}\n",
+			yaml: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var spec *OpenAPISpec
+			var err error
+			if tt.yaml {
+				spec, err = ParseOpenAPISpecBytesYAML([]byte(tt.data))
+			} else {
+				spec, err = ParseOpenAPISpecBytes([]byte(tt.data))
+			}
+
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !strings.HasPrefix(spec.OpenAPI, "3.") {
+				t.Errorf("Expected a 3.x version, got %q", spec.OpenAPI)
+			}
+		})
+	}
+}
+
 func TestResolveRequestBodyRef(t *testing.T) {
 	spec := &OpenAPISpec{
 		Components: &Components{
@@ -384,3 +451,261 @@ func TestParameter_Validation(t *testing.T) {
 		t.Errorf("Expected parameter schema type 'string', got '%s'", param.Schema.Type)
 	}
 }
+
+func TestParseOperationID(t *testing.T) {
+	jsonSpec := `{"openapi": "3.0.3", "paths": {"/topics": {"get": {"operationId": "listKafkaTopics"}}}}`
+	yamlSpec := `
+openapi: 3.0.3
+paths:
+  /topics:
+    get:
+      operationId: listKafkaTopics
+`
+
+	fromJSON, err := ParseOpenAPISpecBytes([]byte(jsonSpec))
+	if err != nil {
+		t.Fatalf("Expected no error parsing JSON, got %v", err)
+	}
+	fromYAML, err := ParseOpenAPISpecBytesYAML([]byte(yamlSpec))
+	if err != nil {
+		t.Fatalf("Expected no error parsing YAML, got %v", err)
+	}
+
+	for name, spec := range map[string]*OpenAPISpec{"JSON": fromJSON, "YAML": fromYAML} {
+		get := spec.Paths["/topics"].Get
+		if get == nil {
+			t.Fatalf("%s: expected GET operation to exist", name)
+		}
+		if get.OperationID != "listKafkaTopics" {
+			t.Errorf("%s: expected operationId 'listKafkaTopics', got '%s'", name, get.OperationID)
+		}
+	}
+}
+
+func TestParseDeprecated(t *testing.T) {
+	jsonSpec := `{"openapi": "3.0.3", "paths": {"/topics": {"get": {"deprecated": true}, "post": {}}}}`
+	yamlSpec := `
+openapi: 3.0.3
+paths:
+  /topics:
+    get:
+      deprecated: true
+    post:
+      summary: Create topic
+`
+
+	fromJSON, err := ParseOpenAPISpecBytes([]byte(jsonSpec))
+	if err != nil {
+		t.Fatalf("Expected no error parsing JSON, got %v", err)
+	}
+	fromYAML, err := ParseOpenAPISpecBytesYAML([]byte(yamlSpec))
+	if err != nil {
+		t.Fatalf("Expected no error parsing YAML, got %v", err)
+	}
+
+	for name, spec := range map[string]*OpenAPISpec{"JSON": fromJSON, "YAML": fromYAML} {
+		pathItem := spec.Paths["/topics"]
+		if pathItem.Get == nil || !pathItem.Get.Deprecated {
+			t.Errorf("%s: expected GET to be deprecated", name)
+		}
+		if pathItem.Post == nil || pathItem.Post.Deprecated {
+			t.Errorf("%s: expected POST not to be deprecated", name)
+		}
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	jsonSpec := `{"openapi": "3.0.3", "paths": {"/topics": {"get": {"tags": ["Topic (v3)"]}}}}`
+	yamlSpec := `
+openapi: 3.0.3
+paths:
+  /topics:
+    get:
+      tags:
+        - Topic (v3)
+`
+
+	fromJSON, err := ParseOpenAPISpecBytes([]byte(jsonSpec))
+	if err != nil {
+		t.Fatalf("Expected no error parsing JSON, got %v", err)
+	}
+	fromYAML, err := ParseOpenAPISpecBytesYAML([]byte(yamlSpec))
+	if err != nil {
+		t.Fatalf("Expected no error parsing YAML, got %v", err)
+	}
+
+	for name, spec := range map[string]*OpenAPISpec{"JSON": fromJSON, "YAML": fromYAML} {
+		pathItem := spec.Paths["/topics"]
+		if pathItem.Get == nil || len(pathItem.Get.Tags) != 1 || pathItem.Get.Tags[0] != "Topic (v3)" {
+			t.Errorf("%s: expected GET to be tagged Topic (v3), got %+v", name, pathItem.Get)
+		}
+	}
+}
+
+func TestMatchesPathPatternEncodedSegments(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestPath string
+		specPath    string
+		expected    bool
+	}{
+		{name: "Id with an encoded slash", requestPath: "/subjects/team%2Forders/versions", specPath: "/subjects/{subject}/versions", expected: true},
+		{name: "Id with encoded spaces", requestPath: "/subjects/orders%20value/versions", specPath: "/subjects/{subject}/versions", expected: true},
+		{name: "Encoded literal segment", requestPath: "/catalog/v1/%74ypes", specPath: "/catalog/v1/types", expected: true},
+		{name: "Encoded literal in the spec", requestPath: "/catalog/v1/types defs", specPath: "/catalog/v1/types%20defs", expected: true},
+		{name: "Unencoded slash adds a segment", requestPath: "/subjects/team/orders/versions", specPath: "/subjects/{subject}/versions", expected: false},
+		{name: "Different literal", requestPath: "/subjects/orders/schemas", specPath: "/subjects/{subject}/versions", expected: false},
+		{name: "Invalid escape", requestPath: "/subjects/100%/versions", specPath: "/subjects/100%/versions", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if matched := matchesPathPattern(tt.requestPath, tt.specPath); matched != tt.expected {
+				t.Errorf("Expected matchesPathPattern(%q, %q) = %v, got %v", tt.requestPath, tt.specPath, tt.expected, matched)
+			}
+		})
+	}
+}
+
+func TestParseExtensions(t *testing.T) {
+	jsonSpec := `{"openapi": "3.0.3", "paths": {"/topics": {"get": {"summary": "List topics", "x-ratelimit-limit": 100, "x-ratelimit-window": "60s"}, "post": {}}}}`
+	yamlSpec := `
+openapi: 3.0.3
+paths:
+  /topics:
+    get:
+      summary: List topics
+      x-ratelimit-limit: 100
+      x-ratelimit-window: 60s
+    post:
+      summary: Create topic
+`
+
+	fromJSON, err := ParseOpenAPISpecBytes([]byte(jsonSpec))
+	if err != nil {
+		t.Fatalf("Expected no error parsing JSON, got %v", err)
+	}
+	fromYAML, err := ParseOpenAPISpecBytesYAML([]byte(yamlSpec))
+	if err != nil {
+		t.Fatalf("Expected no error parsing YAML, got %v", err)
+	}
+
+	for name, spec := range map[string]*OpenAPISpec{"JSON": fromJSON, "YAML": fromYAML} {
+		get := spec.Paths["/topics"].Get
+		if get == nil || get.Summary != "List topics" {
+			t.Fatalf("%s: expected the regular fields to be parsed, got %+v", name, get)
+		}
+		if _, exists := get.Extensions["x-ratelimit-limit"]; !exists {
+			t.Errorf("%s: expected x-ratelimit-limit in the extensions, got %v", name, get.Extensions)
+		}
+		hints := get.RateLimitHints()
+		if hints["limit"] != "100" || hints["window"] != "60s" {
+			t.Errorf("%s: expected limit=100 and window=60s, got %v", name, hints)
+		}
+		if post := spec.Paths["/topics"].Post; post == nil || post.Extensions != nil || post.RateLimitHints() != nil {
+			t.Errorf("%s: expected no extensions on POST, got %+v", name, post)
+		}
+	}
+}
+
+func TestParseRequestBodyExamples(t *testing.T) {
+	jsonSpec := `{"openapi": "3.0.3", "paths": {"/topics": {
+		"post": {"requestBody": {"content": {"application/json": {"example": {"topic_name": "orders"}}}}},
+		"put": {"requestBody": {"content": {"application/json": {"examples": {
+			"b-compacted": {"summary": "Compacted", "value": {"topic_name": "compacted"}},
+			"a-plain": {"summary": "Plain", "value": {"topic_name": "plain"}}
+		}}}}}
+	}}}`
+	yamlMediaType := `
+examples:
+  b-compacted:
+    summary: Compacted
+    value:
+      topic_name: compacted
+  a-plain:
+    summary: Plain
+    value:
+      topic_name: plain
+`
+
+	spec, err := ParseOpenAPISpecBytes([]byte(jsonSpec))
+	if err != nil {
+		t.Fatalf("Expected no error parsing JSON, got %v", err)
+	}
+	var fromYAML MediaType
+	if err := yaml.Unmarshal([]byte(yamlMediaType), &fromYAML); err != nil {
+		t.Fatalf("Expected no error parsing YAML, got %v", err)
+	}
+
+	post := spec.Paths["/topics"].Post.RequestBody.Content["application/json"].ExampleValue()
+	if example, ok := post.(map[string]interface{}); !ok || example["topic_name"] != "orders" {
+		t.Errorf("Expected the POST example, got %v", post)
+	}
+
+	for name, mediaType := range map[string]MediaType{"JSON": spec.Paths["/topics"].Put.RequestBody.Content["application/json"], "YAML": fromYAML} {
+		if mediaType.Examples["a-plain"].Summary != "Plain" {
+			t.Errorf("%s: expected named examples to keep their summary, got %+v", name, mediaType.Examples)
+		}
+		if example, ok := mediaType.ExampleValue().(map[string]interface{}); !ok || example["topic_name"] != "plain" {
+			t.Errorf("%s: expected the first named example in name order, got %v", name, mediaType.ExampleValue())
+		}
+	}
+
+	if value := (MediaType{}).ExampleValue(); value != nil {
+		t.Errorf("Expected no example for an empty media type, got %v", value)
+	}
+}
+
+func TestLoadTelemetrySpec_DetectsFormat(t *testing.T) {
+	jsonSpec := `{"openapi": "3.0.0", "info": {"title": "Telemetry", "version": "2"}, "paths": {"/v2/metrics/{dataset}/query": {"post": {"operationId": "QueryV2"}}}}`
+	yamlSpec := "openapi: 3.0.0\ninfo:\n  title: Telemetry\n  version: \"2\"\npaths:\n  /v2/metrics/{dataset}/query:\n    post:\n      operationId: QueryV2\n"
+
+	dir := t.TempDir()
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "json") {
+			w.Write([]byte(jsonSpec))
+			return
+		}
+		w.Write([]byte(yamlSpec))
+	}))
+	defer remote.Close()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		url     string
+	}{
+		{name: "Extensionless JSON file", file: "telemetry-spec", content: "\n  " + jsonSpec},
+		{name: "Extensionless YAML file", file: "telemetry", content: yamlSpec},
+		{name: "JSON with .txt extension", file: "telemetry.txt", content: jsonSpec},
+		{name: "YAML with .yaml extension", file: "telemetry.yaml", content: yamlSpec},
+		{name: "Remote JSON without extension", url: remote.URL + "/spec/json?version=2"},
+		{name: "Remote YAML without extension", url: remote.URL + "/spec/yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specPath := tt.url
+			if tt.file != "" {
+				specPath = filepath.Join(dir, tt.file)
+				if err := os.WriteFile(specPath, []byte(tt.content), 0o600); err != nil {
+					t.Fatalf("Failed to write spec: %v", err)
+				}
+			}
+			t.Setenv("TELEMETRY_OPENAPI_SPEC_URL", specPath)
+
+			spec, err := LoadTelemetrySpec()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			operation := spec.FindOperation("POST", "/v2/metrics/{dataset}/query")
+			if operation == nil {
+				t.Fatal("Expected the query operation to be parsed")
+			}
+			if operation.OperationID != "QueryV2" {
+				t.Errorf("Expected operationId %q, got %q", "QueryV2", operation.OperationID)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Server describes a target host of the API; its URL may contain {variable} templates
+type Server struct {
+	URL         string                    `json:"url"`
+	Description string                    `json:"description,omitempty"`
+	Variables   map[string]ServerVariable `json:"variables,omitempty"`
+}
+
+// ServerVariable describes a variable used in a server URL template
+type ServerVariable struct {
+	Default     string   `json:"default"`
+	Enum        []string `json:"enum,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// serverVariablePattern matches {variable} templates in server URLs
+var serverVariablePattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// ResolveURL substitutes the server URL's variables, taking each from values when set and from
+// the variable's default otherwise. Values outside a variable's enum are rejected.
+func (s Server) ResolveURL(values map[string]string) (string, error) {
+	var resolveErr error
+	resolved := serverVariablePattern.ReplaceAllStringFunc(s.URL, func(match string) string {
+		name := match[1 : len(match)-1]
+		variable, declared := s.Variables[name]
+
+		value, ok := values[name]
+		if !ok || value == "" {
+			value = variable.Default
+		}
+		if value == "" {
+			if resolveErr == nil {
+				resolveErr = fmt.Errorf("server URL %s: no value for variable '%s'", s.URL, name)
+			}
+			return match
+		}
+		if declared && len(variable.Enum) > 0 && !slices.Contains(variable.Enum, value) {
+			if resolveErr == nil {
+				resolveErr = fmt.Errorf("server URL %s: '%s' is not a valid value for variable '%s', expected one of %s",
+					s.URL, value, name, strings.Join(variable.Enum, ", "))
+			}
+			return match
+		}
+		return value
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return strings.TrimSuffix(resolved, "/"), nil
+}
+
+// ServersForPath returns the servers that apply to a path (a request path or a path template):
+// the path's own servers if it declares any, otherwise the spec-level servers
+func (spec *OpenAPISpec) ServersForPath(path string) []Server {
+	if spec == nil {
+		return nil
+	}
+	if spec.Paths != nil {
+		if pathItem := spec.findPathItem(path); pathItem != nil && len(pathItem.Servers) > 0 {
+			return pathItem.Servers
+		}
+	}
+	return spec.Servers
+}
+
+// ResolveServerURL resolves the first server that applies to a path, or returns an empty string
+// when the spec declares no servers
+func (spec *OpenAPISpec) ResolveServerURL(path string, values map[string]string) (string, error) {
+	servers := spec.ServersForPath(path)
+	if len(servers) == 0 {
+		return "", nil
+	}
+	return servers[0].ResolveURL(values)
+}
+
+// ServerVariableNames lists the variables used in the URL of the server that applies to a path
+func (spec *OpenAPISpec) ServerVariableNames(path string) []string {
+	servers := spec.ServersForPath(path)
+	if len(servers) == 0 {
+		return nil
+	}
+	var names []string
+	for _, match := range serverVariablePattern.FindAllStringSubmatch(servers[0].URL, -1) {
+		if !slices.Contains(names, match[1]) {
+			names = append(names, match[1])
+		}
+	}
+	slices.Sort(names)
+	return names
+}
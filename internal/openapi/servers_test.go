@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveServerURL(t *testing.T) {
+	spec, err := ParseOpenAPISpecBytes([]byte(`{
+		"openapi": "3.0.0",
+		"servers": [{
+			"url": "https://{instance}.{region}.confluent.cloud/",
+			"variables": {
+				"instance": {"default": "api"},
+				"region": {"default": "us-east-1", "enum": ["us-east-1", "eu-west-1"]}
+			}
+		}],
+		"paths": {
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				"servers": [{"url": "https://{cluster}.kafka.example.com"}],
+				"get": {"operationId": "listTopics"}
+			},
+			"/org/v2/environments": {
+				"get": {"operationId": "listEnvironments"}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		values      map[string]string
+		expected    string
+		expectError string
+	}{
+		{
+			name:     "Defaults fill every variable",
+			path:     "/org/v2/environments",
+			expected: "https://api.us-east-1.confluent.cloud",
+		},
+		{
+			name:     "Values override defaults",
+			path:     "/org/v2/environments",
+			values:   map[string]string{"region": "eu-west-1"},
+			expected: "https://api.eu-west-1.confluent.cloud",
+		},
+		{
+			name:        "Values outside the enum are rejected",
+			path:        "/org/v2/environments",
+			values:      map[string]string{"region": "ap-south-1"},
+			expectError: "not a valid value for variable 'region'",
+		},
+		{
+			name:     "Path-level servers take precedence",
+			path:     "/kafka/v3/clusters/lkc-1/topics",
+			values:   map[string]string{"cluster": "pkc-123"},
+			expected: "https://pkc-123.kafka.example.com",
+		},
+		{
+			name:        "Variables without a value or default are an error",
+			path:        "/kafka/v3/clusters/lkc-1/topics",
+			expectError: "no value for variable 'cluster'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, err := spec.ResolveServerURL(tt.path, tt.values)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if url != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, url)
+			}
+		})
+	}
+
+	t.Run("Spec without servers resolves to nothing", func(t *testing.T) {
+		url, err := (&OpenAPISpec{}).ResolveServerURL("/org/v2/environments", nil)
+		if err != nil || url != "" {
+			t.Errorf("Expected no URL, got %q (%v)", url, err)
+		}
+	})
+
+	t.Run("Lists the variables of the applicable server", func(t *testing.T) {
+		names := spec.ServerVariableNames("/org/v2/environments")
+		if strings.Join(names, ",") != "instance,region" {
+			t.Errorf("Expected instance and region, got %v", names)
+		}
+	})
+}
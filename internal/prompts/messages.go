@@ -0,0 +1,100 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Roles a prompt template message may take
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// PromptMessage is one message of a prompt template
+type PromptMessage struct {
+	Role    string `yaml:"role"`
+	Content string `yaml:"content"`
+}
+
+// promptFrontMatter is the optional header of a prompt file, between two --- lines. A prompt with
+// messages is a template returned as those messages, e.g. for few-shot examples:
+//
+//	---
+//	description: Name a topic
+//	messages:
+//	  - role: system
+//	    content: Topic names in {environment} use the domain.entity.event pattern.
+//	  - role: user
+//	    content: A topic for orders being placed
+//	  - role: assistant
+//	    content: sales.order.placed
+//	---
+//	A topic for payments being refunded
+//
+// Text after the front matter is sent as a last user message.
+type promptFrontMatter struct {
+	Description string          `yaml:"description"`
+	Messages    []PromptMessage `yaml:"messages"`
+}
+
+// parsePromptTemplate splits a prompt file into the messages and description of its front matter,
+// if any, and the rest of the file
+func parsePromptTemplate(content string) (messages []PromptMessage, description, body string, err error) {
+	header, body, found, err := splitFrontMatter(content)
+	if err != nil || !found {
+		return nil, "", body, err
+	}
+
+	var frontMatter promptFrontMatter
+	if err := yaml.Unmarshal([]byte(header), &frontMatter); err != nil {
+		return nil, "", "", err
+	}
+	for i, message := range frontMatter.Messages {
+		switch message.Role {
+		case RoleSystem, RoleUser, RoleAssistant:
+		default:
+			return nil, "", "", fmt.Errorf("message %d has role '%s', expected system, user or assistant", i+1, message.Role)
+		}
+		frontMatter.Messages[i].Content = strings.TrimSpace(message.Content)
+	}
+	return frontMatter.Messages, strings.TrimSpace(frontMatter.Description), body, nil
+}
+
+// templateText renders template messages as one text, for listing a prompt and detecting the
+// arguments it uses
+func templateText(messages []PromptMessage) string {
+	parts := make([]string, 0, len(messages))
+	for _, message := range messages {
+		parts = append(parts, fmt.Sprintf("[%s]\n%s", message.Role, message.Content))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// GetPromptMessages returns the messages of a prompt with argument overrides and variable
+// substitution applied, and the directives leading the first message. A prompt without template
+// messages is a single user message.
+func (pm *PromptManager) GetPromptMessages(name string, args map[string]interface{}) ([]PromptMessage, error) {
+	messages, isTemplate := pm.promptMessages[name]
+	if !isTemplate {
+		content, err := pm.GetPromptContentWithArguments(name, args)
+		if err != nil {
+			return nil, err
+		}
+		return []PromptMessage{{Role: RoleUser, Content: content}}, nil
+	}
+
+	result := make([]PromptMessage, 0, len(messages))
+	for _, message := range messages {
+		content, err := pm.substituteVariables(applyPromptArguments(message.Content, args))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, PromptMessage{Role: message.Role, Content: content})
+	}
+	result[0].Content = pm.composePromptWithDirectives(name, result[0].Content)
+	return result, nil
+}
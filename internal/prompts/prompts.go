@@ -5,20 +5,25 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"mcolomerc/mcp-server/internal/config"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
 )
 
 // PromptManager handles loading and managing prompts from external files
 type PromptManager struct {
 	prompts          map[string]mcp.Prompt
-	promptContent    map[string]string // Store prompt content separately
+	promptContent    map[string]string          // Store prompt content separately
+	promptMessages   map[string][]PromptMessage // Messages of prompts defined as templates
 	folder           string
 	config           *config.Config // Add config for variable substitution
 	directives       string         // Combined directives content
 	directivesFolder string         // Path to directives folder
+	loadedAt         time.Time      // When prompts last loaded successfully
 }
 
 // NewPromptManager creates a new prompt manager
@@ -57,6 +62,7 @@ func NewPromptManager(folder string, cfg *config.Config) *PromptManager {
 	return &PromptManager{
 		prompts:          make(map[string]mcp.Prompt),
 		promptContent:    make(map[string]string),
+		promptMessages:   make(map[string][]PromptMessage),
 		folder:           folder,
 		config:           cfg,
 		directivesFolder: directivesFolder,
@@ -65,6 +71,15 @@ func NewPromptManager(folder string, cfg *config.Config) *PromptManager {
 
 // LoadPrompts loads all .txt files from the configured prompts folder
 func (pm *PromptManager) LoadPrompts() error {
+	if err := pm.loadPrompts(); err != nil {
+		return err
+	}
+	pm.loadedAt = time.Now()
+	return nil
+}
+
+// loadPrompts loads the directives, then each prompt file
+func (pm *PromptManager) loadPrompts() error {
 	// First, load directives
 	if err := pm.loadDirectives(); err != nil {
 		return fmt.Errorf("failed to load directives: %w", err)
@@ -120,8 +135,26 @@ func (pm *PromptManager) loadPromptFile(filePath string) error {
 	fileName := filepath.Base(filePath)
 	promptName := strings.TrimSuffix(fileName, filepath.Ext(fileName))
 
-	// Parse the content to extract description and prompt text
-	description, promptText := parsePromptContent(string(content))
+	// Parse the content to extract template messages, description and prompt text
+	messages, templateDescription, body, err := parsePromptTemplate(string(content))
+	if err != nil {
+		return fmt.Errorf("invalid front matter: %w", err)
+	}
+	description, promptText := parsePromptContent(body)
+	if len(messages) > 0 {
+		// Text after the front matter is the last user message of a template
+		if promptText != "" {
+			messages = append(messages, PromptMessage{Role: RoleUser, Content: promptText})
+		}
+		pm.promptMessages[promptName] = messages
+		promptText = templateText(messages)
+		if !strings.HasPrefix(strings.TrimSpace(body), "#") {
+			description, _ = parsePromptContent(messages[0].Content)
+		}
+	}
+	if templateDescription != "" {
+		description = templateDescription
+	}
 
 	// Store the original prompt content without substitution for potential argument-based substitution later
 	pm.promptContent[promptName] = promptText
@@ -258,11 +291,17 @@ func (pm *PromptManager) GetPromptContent(name string) (string, error) {
 	return content, nil
 }
 
+// LoadedAt returns when prompts last loaded successfully, or the zero time if they never did
+func (pm *PromptManager) LoadedAt() time.Time {
+	return pm.loadedAt
+}
+
 // ReloadPrompts reloads all prompts and directives from their respective folders
 func (pm *PromptManager) ReloadPrompts() error {
 	// Clear existing prompts and directives
 	pm.prompts = make(map[string]mcp.Prompt)
 	pm.promptContent = make(map[string]string)
+	pm.promptMessages = make(map[string][]PromptMessage)
 	pm.directives = ""
 
 	// Reload all prompts (which will also reload directives)
@@ -340,7 +379,7 @@ func (pm *PromptManager) GetPromptContentWithSubstitution(name string) (string,
 	}
 
 	// Compose with directives
-	return pm.composePromptWithDirectives(substituted), nil
+	return pm.composePromptWithDirectives(name, substituted), nil
 }
 
 // GetPromptContentWithArguments returns the content of a specific prompt with variable substitution, argument overrides, and directives
@@ -350,10 +389,20 @@ func (pm *PromptManager) GetPromptContentWithArguments(name string, args map[str
 		return "", fmt.Errorf("prompt '%s' not found", name)
 	}
 
-	// Start with original content
+	// Apply argument overrides first, then default config substitutions for any remaining placeholders
+	substituted, err := pm.substituteVariables(applyPromptArguments(content, args))
+	if err != nil {
+		return "", err
+	}
+
+	// Compose with directives
+	return pm.composePromptWithDirectives(name, substituted), nil
+}
+
+// applyPromptArguments replaces the placeholders a prompt argument overrides, in both formats
+func applyPromptArguments(content string, args map[string]interface{}) string {
 	result := content
 
-	// Apply argument overrides first (support both formats)
 	if environmentID, ok := args["environment_id"].(string); ok && environmentID != "" {
 		// Replace both environment variable format and parameter format
 		result = strings.ReplaceAll(result, "{CONFLUENT_ENV_ID}", environmentID)
@@ -380,17 +429,11 @@ func (pm *PromptManager) GetPromptContentWithArguments(name string, args map[str
 		result = strings.ReplaceAll(result, "{org}", orgID)
 	}
 
-	// Then apply default config substitutions for any remaining placeholders
-	substituted, err := pm.substituteVariables(result)
-	if err != nil {
-		return "", err
-	}
-
-	// Compose with directives
-	return pm.composePromptWithDirectives(substituted), nil
+	return result
 }
 
-// loadDirectives loads all .txt files from the directives folder and combines them
+// loadDirectives loads all .txt files from the directives folder and combines them, leaving out
+// those whose front matter requires features the configuration does not enable
 func (pm *PromptManager) loadDirectives() error {
 	// Check if directives are enabled
 	if pm.config != nil && !pm.config.EnableDirectives {
@@ -424,8 +467,17 @@ func (pm *PromptManager) loadDirectives() error {
 			return fmt.Errorf("failed to read directive file %s: %w", file, err)
 		}
 
+		requires, body, err := parseDirectiveFrontMatter(string(content))
+		if err != nil {
+			return fmt.Errorf("invalid front matter in directive file %s: %w", file, err)
+		}
+		if missing := pm.missingFeatures(requires); len(missing) > 0 {
+			fmt.Fprintf(os.Stderr, "Skipping directive %s: requires %s\n", filepath.Base(file), strings.Join(missing, ", "))
+			continue
+		}
+
 		// Trim whitespace and add to collection
-		directive := strings.TrimSpace(string(content))
+		directive := strings.TrimSpace(body)
 		if directive != "" {
 			allDirectives = append(allDirectives, directive)
 		}
@@ -437,17 +489,143 @@ func (pm *PromptManager) loadDirectives() error {
 	return nil
 }
 
+// directiveFrontMatter is the optional header of a directive file, between two --- lines:
+//
+//	---
+//	requires: flink, schema_registry
+//	---
+type directiveFrontMatter struct {
+	Requires interface{} `yaml:"requires"` // Features the directive needs, as a list or a comma-separated string
+}
+
+// parseDirectiveFrontMatter splits a directive file into the features its front matter requires
+// and its text. Files without front matter require nothing.
+func parseDirectiveFrontMatter(content string) (requires []string, body string, err error) {
+	header, body, found, err := splitFrontMatter(content)
+	if err != nil || !found {
+		return nil, body, err
+	}
+
+	var frontMatter directiveFrontMatter
+	if err := yaml.Unmarshal([]byte(header), &frontMatter); err != nil {
+		return nil, "", err
+	}
+	switch value := frontMatter.Requires.(type) {
+	case nil:
+	case string:
+		requires = strings.Split(value, ",")
+	case []interface{}:
+		for _, item := range value {
+			requires = append(requires, fmt.Sprint(item))
+		}
+	default:
+		return nil, "", fmt.Errorf("requires must be a list or a comma-separated string")
+	}
+	for i := range requires {
+		requires[i] = strings.TrimSpace(requires[i])
+	}
+	return requires, body, nil
+}
+
+// splitFrontMatter separates the YAML header between two leading --- lines from the rest of a
+// prompt or directive file. Content without a header is returned whole as the body.
+func splitFrontMatter(content string) (header, body string, found bool, err error) {
+	trimmed := strings.TrimLeft(content, "\r\n\t ")
+	if !strings.HasPrefix(trimmed, "---") {
+		return "", content, false, nil
+	}
+	lines := strings.Split(trimmed, "\n")
+	if strings.TrimSpace(lines[0]) != "---" {
+		return "", content, false, nil
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return strings.Join(lines[1:i], "\n"), strings.Join(lines[i+1:], "\n"), true, nil
+		}
+	}
+	return "", "", false, fmt.Errorf("front matter is not closed with ---")
+}
+
+// missingFeatures returns the required features the configuration does not enable, see
+// config.ActiveFeatures
+func (pm *PromptManager) missingFeatures(requires []string) []string {
+	if len(requires) == 0 {
+		return nil
+	}
+	active := map[string]bool{}
+	if pm.config != nil {
+		active = pm.config.ActiveFeatures()
+	}
+	var missing []string
+	for _, feature := range requires {
+		if feature != "" && !active[feature] {
+			missing = append(missing, feature)
+		}
+	}
+	return missing
+}
+
 // GetDirectives returns the combined directives content
 func (pm *PromptManager) GetDirectives() string {
 	return pm.directives
 }
 
-// composePromptWithDirectives combines directives with a prompt
-func (pm *PromptManager) composePromptWithDirectives(promptContent string) string {
-	if pm.directives == "" {
-		return promptContent
+// composePromptWithDirectives combines directives with a prompt, applying the MAX_PROMPT_BYTES cap
+func (pm *PromptManager) composePromptWithDirectives(name, promptContent string) string {
+	composed, warning := pm.capPromptSize(name, pm.directives, promptContent)
+	if warning != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	return composed
+}
+
+// Actions for a composed prompt over MAX_PROMPT_BYTES
+const (
+	PromptOverflowWarn     = "warn"     // Return the prompt whole and log a warning
+	PromptOverflowTruncate = "truncate" // Cut the directives, then the prompt, down to the limit
+)
+
+// capPromptSize joins directives and a prompt and checks the result against MAX_PROMPT_BYTES.
+// Over the limit it returns a warning naming the prompt and, when PROMPT_OVERFLOW is truncate,
+// shortens the directives first so the prompt itself is kept whenever it fits on its own.
+func (pm *PromptManager) capPromptSize(name, directives, promptContent string) (string, string) {
+	join := func(directives, promptContent string) string {
+		if directives == "" {
+			return promptContent
+		}
+		return directives + "\n\n" + promptContent
+	}
+	composed := join(directives, promptContent)
+
+	limit := 0
+	truncate := false
+	if pm.config != nil {
+		limit = pm.config.MaxPromptBytes
+		truncate = pm.config.PromptOverflow == PromptOverflowTruncate
+	}
+	if limit <= 0 || len(composed) <= limit {
+		return composed, ""
+	}
+	if !truncate {
+		return composed, fmt.Sprintf("prompt '%s' is %d bytes with directives, over MAX_PROMPT_BYTES (%d)", name, len(composed), limit)
+	}
+
+	warning := fmt.Sprintf("prompt '%s' is %d bytes with directives, truncated to MAX_PROMPT_BYTES (%d)", name, len(composed), limit)
+	if room := limit - len(promptContent) - len("\n\n"); room > 0 {
+		return join(strings.TrimSpace(truncateUTF8(directives, room)), promptContent), warning
+	}
+	return truncateUTF8(promptContent, limit), warning
+}
+
+// truncateUTF8 cuts s to at most limit bytes without splitting a UTF-8 character
+func truncateUTF8(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
 	}
-	return pm.directives + "\n\n" + promptContent
+	return s[:limit]
 }
 
 // SetDirectivesFolder sets the directives folder path (useful for testing)
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestVariableSubstitution(t *testing.T) {
@@ -326,3 +327,221 @@ Cluster: {cluster_id}`
 		t.Error("Should not contain default values when overridden")
 	}
 }
+
+func TestConditionalDirectives(t *testing.T) {
+	directivesDir := t.TempDir()
+	files := map[string]string{
+		"role.txt":    "You are a Confluent Cloud operator.",
+		"flink.txt":   "---\nrequires: flink\n---\nPrefer Flink SQL for stream processing.",
+		"sr.txt":      "---\nrequires: [flink, schema_registry]\n---\nRegister schemas before Flink tables.",
+		"strict.txt":  "---\nrequires: strict_args\n---\nOnly pass documented arguments.",
+		"unknown.txt": "---\nrequires: warp_drive\n---\nEngage.",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(directivesDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	load := func(t *testing.T, cfg *config.Config) string {
+		t.Helper()
+		cfg.EnableDirectives = true
+		pm := NewPromptManager(t.TempDir(), cfg)
+		pm.SetDirectivesFolder(directivesDir)
+		if err := pm.LoadPrompts(); err != nil {
+			t.Fatal(err)
+		}
+		return pm.GetDirectives()
+	}
+
+	t.Run("Directives are included when their features are enabled", func(t *testing.T) {
+		directives := load(t, &config.Config{
+			FlinkRestEndpoint:       "https://flink.example.com",
+			FlinkAPIKey:             "key",
+			FlinkAPISecret:          "secret",
+			SchemaRegistryEndpoint:  "https://sr.example.com",
+			SchemaRegistryAPIKey:    "key",
+			SchemaRegistryAPISecret: "secret",
+			StrictArgs:              true,
+		})
+		for _, want := range []string{"Confluent Cloud operator", "Prefer Flink SQL", "Register schemas", "Only pass documented arguments"} {
+			if !strings.Contains(directives, want) {
+				t.Errorf("Expected directives to contain %q, got %q", want, directives)
+			}
+		}
+		if strings.Contains(directives, "requires:") || strings.Contains(directives, "---") {
+			t.Errorf("Expected the front matter to be stripped, got %q", directives)
+		}
+		if strings.Contains(directives, "Engage") {
+			t.Errorf("Expected a directive requiring an unknown feature to be left out, got %q", directives)
+		}
+	})
+
+	t.Run("Directives are left out when a feature is missing", func(t *testing.T) {
+		directives := load(t, &config.Config{
+			FlinkRestEndpoint: "https://flink.example.com",
+			FlinkAPIKey:       "key",
+			FlinkAPISecret:    "secret",
+		})
+		if !strings.Contains(directives, "Prefer Flink SQL") {
+			t.Errorf("Expected the Flink directive, got %q", directives)
+		}
+		for _, unwanted := range []string{"Register schemas", "Only pass documented arguments"} {
+			if strings.Contains(directives, unwanted) {
+				t.Errorf("Expected directives not to contain %q, got %q", unwanted, directives)
+			}
+		}
+		if !strings.Contains(directives, "Confluent Cloud operator") {
+			t.Errorf("Expected unconditional directives to be kept, got %q", directives)
+		}
+	})
+
+	t.Run("Unclosed front matter is an error", func(t *testing.T) {
+		if _, _, err := parseDirectiveFrontMatter("---\nrequires: flink\nPrefer Flink SQL."); err == nil {
+			t.Error("Expected an error for unclosed front matter")
+		}
+	})
+}
+
+func TestPromptSizeCap(t *testing.T) {
+	directivesDir := t.TempDir()
+	promptsDir := t.TempDir()
+	directive := strings.Repeat("Always validate requests. ", 40)
+	if err := os.WriteFile(filepath.Join(directivesDir, "guardrails.txt"), []byte(directive), 0644); err != nil {
+		t.Fatal(err)
+	}
+	promptContent := "# Sizing\nHelp me size a Kafka cluster."
+	if err := os.WriteFile(filepath.Join(promptsDir, "sizing.txt"), []byte(promptContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	load := func(t *testing.T, overflow string) *PromptManager {
+		t.Helper()
+		pm := NewPromptManager(promptsDir, &config.Config{EnableDirectives: true, MaxPromptBytes: 200, PromptOverflow: overflow})
+		pm.SetDirectivesFolder(directivesDir)
+		if err := pm.LoadPrompts(); err != nil {
+			t.Fatal(err)
+		}
+		return pm
+	}
+
+	t.Run("Warn keeps the prompt whole", func(t *testing.T) {
+		pm := load(t, PromptOverflowWarn)
+		composed, warning := pm.capPromptSize("sizing", pm.GetDirectives(), promptContent)
+		if !strings.Contains(warning, "'sizing'") || !strings.Contains(warning, "MAX_PROMPT_BYTES (200)") {
+			t.Errorf("Expected a warning naming the prompt and the limit, got %q", warning)
+		}
+		if composed != pm.GetDirectives()+"\n\n"+promptContent {
+			t.Errorf("Expected the composed prompt unchanged, got %q", composed)
+		}
+	})
+
+	t.Run("Truncate cuts the directives first", func(t *testing.T) {
+		pm := load(t, PromptOverflowTruncate)
+		content, err := pm.GetPromptContentWithSubstitution("sizing")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(content) > 200 {
+			t.Errorf("Expected at most 200 bytes, got %d", len(content))
+		}
+		if !strings.HasSuffix(content, "Help me size a Kafka cluster.") || !strings.HasPrefix(content, "Always validate requests.") {
+			t.Errorf("Expected truncated directives followed by the whole prompt, got %q", content)
+		}
+		if _, warning := pm.capPromptSize("sizing", pm.GetDirectives(), promptContent); !strings.Contains(warning, "truncated") {
+			t.Errorf("Expected a truncation warning, got %q", warning)
+		}
+	})
+
+	t.Run("A prompt over the limit on its own is cut on a character boundary", func(t *testing.T) {
+		pm := load(t, PromptOverflowTruncate)
+		long := strings.Repeat("é", 150)
+		composed, _ := pm.capPromptSize("long", pm.GetDirectives(), long)
+		if len(composed) > 200 || !utf8.ValidString(composed) || !strings.HasPrefix(long, composed) {
+			t.Errorf("Expected a valid UTF-8 prefix of the prompt within 200 bytes, got %d bytes", len(composed))
+		}
+	})
+
+	t.Run("No limit by default", func(t *testing.T) {
+		pm := NewPromptManager(promptsDir, &config.Config{})
+		if composed, warning := pm.capPromptSize("sizing", directive, promptContent); warning != "" || len(composed) <= 200 {
+			t.Errorf("Expected no cap without MAX_PROMPT_BYTES, got warning %q", warning)
+		}
+	})
+}
+
+func TestPromptTemplateMessages(t *testing.T) {
+	directivesDir := t.TempDir()
+	promptsDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directivesDir, "role.txt"), []byte("You are a Confluent Cloud operator."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"name-topic.txt": `---
+description: Name a topic
+messages:
+  - role: system
+    content: Topic names in {environment} use the domain.entity.event pattern.
+  - role: user
+    content: A topic for orders being placed
+  - role: assistant
+    content: sales.order.placed
+---
+A topic for payments being refunded in {cluster_id}`,
+		"plain.txt": "# Plain\nList the topics in {cluster_id}.",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(promptsDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{EnableDirectives: true, ConfluentEnvID: "env-123", KafkaClusterID: "lkc-123"}
+	pm := NewPromptManager(promptsDir, cfg)
+	pm.SetDirectivesFolder(directivesDir)
+	if err := pm.LoadPrompts(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Template prompts return their messages", func(t *testing.T) {
+		prompt, ok := pm.GetPrompt("name-topic")
+		if !ok || prompt.Description != "Name a topic" {
+			t.Errorf("Expected the front matter description, got %+v", prompt)
+		}
+
+		messages, err := pm.GetPromptMessages("name-topic", map[string]interface{}{"cluster_id": "lkc-override"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []PromptMessage{
+			{Role: RoleSystem, Content: "You are a Confluent Cloud operator.\n\nTopic names in env-123 use the domain.entity.event pattern."},
+			{Role: RoleUser, Content: "A topic for orders being placed"},
+			{Role: RoleAssistant, Content: "sales.order.placed"},
+			{Role: RoleUser, Content: "A topic for payments being refunded in lkc-override"},
+		}
+		if len(messages) != len(expected) {
+			t.Fatalf("Expected %d messages, got %+v", len(expected), messages)
+		}
+		for i, message := range messages {
+			if message != expected[i] {
+				t.Errorf("Message %d: expected %+v, got %+v", i+1, expected[i], message)
+			}
+		}
+	})
+
+	t.Run("Plain prompts are a single user message", func(t *testing.T) {
+		messages, err := pm.GetPromptMessages("plain", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(messages) != 1 || messages[0].Role != RoleUser || !strings.HasSuffix(messages[0].Content, "List the topics in lkc-123.") {
+			t.Errorf("Expected one user message, got %+v", messages)
+		}
+	})
+
+	t.Run("Unknown roles are rejected", func(t *testing.T) {
+		if _, _, _, err := parsePromptTemplate("---\nmessages:\n  - role: tool\n    content: hi\n---\n"); err == nil {
+			t.Error("Expected an error for an unknown role")
+		}
+	})
+}
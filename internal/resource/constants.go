@@ -16,8 +16,11 @@ var (
 	// Common ID field names (in order of preference)
 	CommonIDFields = []string{"id", "name", "topic_name", "cluster_id", "connector_name"}
 
+	// Common display name field names (in order of preference)
+	CommonNameFields = []string{"name", "display_name"}
+
 	// Common description field names (in order of preference)
-	CommonDescriptionFields = []string{"description", "summary", "status"}
+	CommonDescriptionFields = []string{"description", "summary", "doc", "comment", "status"}
 
 	// Common array field names in API responses (in order of preference)
 	CommonArrayFields = []string{"data", "items", "results"}
@@ -45,6 +48,19 @@ var ExcludedResourceTypes = []string{
 	"default-configs", // Default configurations - metadata templates
 }
 
+// Resource types that don't support general listing or that cause discovery loops
+// These require specific entity parameters and are skipped during discovery and search
+var SkipDiscoveryResourceTypes = []string{"tags", "businessmetadatadefs", "tagdefs", "environments", "costs"}
+
+// Search limits
+const (
+	MaxSearchResults  = 50 // Maximum number of matches returned by a search
+	SearchConcurrency = 5  // Maximum number of resource types listed in parallel
+)
+
+// MaxCachedDescriptions bounds the resource descriptions remembered across results
+const MaxCachedDescriptions = 1000
+
 // Generic identifier field patterns for fallback
 var GenericIDFieldPatterns = []string{"id", "name", "_id", "_name"}
 
@@ -57,3 +73,13 @@ func IsExcludedResourceType(resourceType string) bool {
 	}
 	return false
 }
+
+// ShouldSkipDiscovery checks if a resource type should be skipped during discovery and search
+func ShouldSkipDiscovery(resourceType string) bool {
+	for _, skip := range SkipDiscoveryResourceTypes {
+		if resourceType == skip {
+			return true
+		}
+	}
+	return false
+}
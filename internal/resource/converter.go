@@ -1,8 +1,11 @@
 package resource
 
 import (
+	"encoding/json"
 	"fmt"
+	"mcolomerc/mcp-server/internal/tools"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -10,6 +13,18 @@ import (
 
 // ConvertToMCPResources converts API response data to MCP resource objects
 func (m *Manager) ConvertToMCPResources(resourceType string, apiResult interface{}) ([]mcp.Resource, error) {
+	return m.convertToMCPResources(resourceType, apiResult, false)
+}
+
+// convertListToMCPResources converts the result of a list call. Unlike other results, a list
+// result that holds a single array under an unrecognized key is unwrapped to that array.
+func (m *Manager) convertListToMCPResources(resourceType string, apiResult interface{}) ([]mcp.Resource, error) {
+	return m.convertToMCPResources(resourceType, apiResult, true)
+}
+
+// convertToMCPResources converts API response data to MCP resource objects; list tells whether
+// the data comes from a list call
+func (m *Manager) convertToMCPResources(resourceType string, apiResult interface{}, list bool) ([]mcp.Resource, error) {
 	var resources []mcp.Resource
 
 	// Check if this resource type should be excluded from registration
@@ -23,8 +38,11 @@ func (m *Manager) ConvertToMCPResources(resourceType string, apiResult interface
 	var items []interface{}
 
 	if resultMap, ok := apiResult.(map[string]interface{}); ok {
-		// Check for common array field names
-		arrayFields := append(CommonArrayFields, resourceType)
+		// Check the array field declared by the list response schema, then common array field names
+		arrayFields := append(append([]string(nil), CommonArrayFields...), resourceType)
+		if mapping, err := tools.GetEndpointMapping(tools.ActionList, resourceType); err == nil && mapping.ResponseArrayField != "" {
+			arrayFields = append([]string{mapping.ResponseArrayField}, arrayFields...)
+		}
 		for _, field := range arrayFields {
 			if fieldValue, exists := resultMap[field]; exists {
 				if itemsArray, ok := fieldValue.([]interface{}); ok {
@@ -33,6 +51,10 @@ func (m *Manager) ConvertToMCPResources(resourceType string, apiResult interface
 				}
 			}
 		}
+		// Otherwise a list result uses the only array in the object, whatever its key
+		if len(items) == 0 && list {
+			items = soleArrayField(resultMap)
+		}
 		// If no array field found, treat the entire object as a single item
 		if len(items) == 0 {
 			items = []interface{}{apiResult}
@@ -46,8 +68,9 @@ func (m *Manager) ConvertToMCPResources(resourceType string, apiResult interface
 	}
 
 	// Convert each item to an MCP resource
+	fields := declaredIdentityFields(resourceType)
 	for i, item := range items {
-		resource := m.convertItemToMCPResource(resourceType, item, i)
+		resource := m.convertItemToMCPResource(resourceType, item, i, fields)
 		resources = append(resources, resource)
 	}
 
@@ -55,23 +78,69 @@ func (m *Manager) ConvertToMCPResources(resourceType string, apiResult interface
 	return resources, nil
 }
 
-// convertItemToMCPResource converts a single API item to an MCP resource
-func (m *Manager) convertItemToMCPResource(resourceType string, item interface{}, index int) mcp.Resource {
+// identityFields are the item fields holding a resource's identifier and display name
+type identityFields struct {
+	id   string
+	name string
+}
+
+// declaredIdentityFields picks the id and name fields of a resource's items from the properties its
+// list response schema declares. Fields are empty when the spec declares no matching property.
+func declaredIdentityFields(resourceType string) identityFields {
+	mapping, err := tools.GetEndpointMapping(tools.ActionList, resourceType)
+	if err != nil || len(mapping.ResponseItemFields) == 0 {
+		return identityFields{}
+	}
+	declared := make(map[string]bool, len(mapping.ResponseItemFields))
+	for _, field := range mapping.ResponseItemFields {
+		declared[field] = true
+	}
+	first := func(candidates ...string) string {
+		for _, field := range candidates {
+			if declared[field] {
+				return field
+			}
+		}
+		return ""
+	}
+
+	singular := strings.ReplaceAll(tools.SingularResourceName(resourceType), "-", "_")
+	fields := identityFields{
+		id:   first(append([]string{"id", singular + "_id", singular + "_name"}, CommonIDFields...)...),
+		name: first(CommonNameFields...),
+	}
+	if fields.name == "" {
+		fields.name = fields.id
+	}
+	return fields
+}
+
+// convertItemToMCPResource converts a single API item to an MCP resource. Fields declared by the
+// response schema are read first; the common field names are the fallback.
+func (m *Manager) convertItemToMCPResource(resourceType string, item interface{}, index int, fields identityFields) mcp.Resource {
 	// Try to get a meaningful identifier and name for the resource
-	var id, name, description string
+	var id, name string
 
 	// Handle different types of API responses
 	switch v := item.(type) {
-	case string:
-		// For simple string responses (like subjects), use the string as both ID and name
-		id = v
-		name = v
 	case map[string]interface{}:
+		// Priority 0: The fields the response schema declares
+		if fields.id != "" {
+			if strValue, ok := scalarString(v[fields.id]); ok && strValue != "" {
+				id = strValue
+			}
+		}
+		if fields.name != "" {
+			if strValue, ok := scalarString(v[fields.name]); ok && strValue != "" {
+				name = strValue
+			}
+		}
+
 		// For object responses, try to extract ID and name using priority order
 
 		// Priority 1: Look for 'id' field specifically
-		if value, exists := v["id"]; exists {
-			if strValue, ok := value.(string); ok && strValue != "" {
+		if value, exists := v["id"]; exists && id == "" {
+			if strValue, ok := scalarString(value); ok && strValue != "" {
 				id = strValue
 			}
 		}
@@ -79,7 +148,7 @@ func (m *Manager) convertItemToMCPResource(resourceType string, item interface{}
 		// Priority 2: If no 'id' found, look for 'name' field
 		if id == "" {
 			if value, exists := v["name"]; exists {
-				if strValue, ok := value.(string); ok && strValue != "" {
+				if strValue, ok := scalarString(value); ok && strValue != "" {
 					id = strValue
 				}
 			}
@@ -92,7 +161,7 @@ func (m *Manager) convertItemToMCPResource(resourceType string, item interface{}
 					continue // Already checked above
 				}
 				if value, exists := v[field]; exists {
-					if strValue, ok := value.(string); ok && strValue != "" {
+					if strValue, ok := scalarString(value); ok && strValue != "" {
 						id = strValue
 						break
 					}
@@ -101,7 +170,7 @@ func (m *Manager) convertItemToMCPResource(resourceType string, item interface{}
 		}
 
 		// Set name (prefer 'name' field if available, otherwise use ID)
-		if nameValue, exists := v["name"]; exists {
+		if nameValue, exists := v["name"]; exists && name == "" {
 			if strValue, ok := nameValue.(string); ok && strValue != "" {
 				name = strValue
 			}
@@ -109,15 +178,11 @@ func (m *Manager) convertItemToMCPResource(resourceType string, item interface{}
 		if name == "" {
 			name = id
 		}
-
-		// Try to get a description
-		for _, field := range CommonDescriptionFields {
-			if value, exists := v[field]; exists {
-				if strValue, ok := value.(string); ok && strValue != "" {
-					description = strValue
-					break
-				}
-			}
+	default:
+		// For lists of primitives (like subject names or schema IDs), use the value as both ID and name
+		if strValue, ok := scalarString(v); ok {
+			id = strValue
+			name = strValue
 		}
 	}
 
@@ -129,17 +194,51 @@ func (m *Manager) convertItemToMCPResource(resourceType string, item interface{}
 		name = id
 	}
 
-	if description == "" {
-		description = fmt.Sprintf("%s resource: %s", strings.Title(resourceType), name)
-	}
-
 	// Create the URI for this resource
 	uri := fmt.Sprintf("%s%s%s%s", ConfluentURIScheme, resourceType, URIPathSeparator, id)
+	description := m.describeResource(resourceType, uri, name, item)
 
 	return mcp.Resource{
 		URI:         uri,
 		Name:        name,
 		Description: description,
-		MIMEType:    "application/json",
+		MIMEType:    m.mimeType(resourceType),
+	}
+}
+
+// soleArrayField returns the array of an object that holds exactly one non-empty array field,
+// e.g. {"subject_names": ["a", "b"]}, or nil when there is none or several
+func soleArrayField(resultMap map[string]interface{}) []interface{} {
+	var found []interface{}
+	for _, value := range resultMap {
+		if array, ok := value.([]interface{}); ok && len(array) > 0 {
+			if found != nil {
+				return nil
+			}
+			found = array
+		}
+	}
+	return found
+}
+
+// scalarString converts a scalar identifier value to its string form. JSON numbers decode as
+// float64, so integral values are formatted without a decimal point (e.g. schema ID 100001).
+func scalarString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
 	}
+	return "", false
 }
@@ -0,0 +1,291 @@
+package resource
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"reflect"
+	"testing"
+)
+
+func TestConvertToMCPResourcesDescriptions(t *testing.T) {
+	manager := NewManager(&fakeInvoker{})
+
+	resources, err := manager.ConvertToMCPResources("widgets", map[string]interface{}{
+		"data": []interface{}{
+			map[string]interface{}{"id": "w-1", "description": "Billing events widget"},
+			map[string]interface{}{"id": "w-2", "spec": map[string]interface{}{"description": "Nested spec description"}},
+			map[string]interface{}{"id": "w-3", "status": "ACTIVE"},
+			map[string]interface{}{"id": "w-4"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"Billing events widget",
+		"Nested spec description",
+		"ACTIVE",
+		"Widgets resource: w-4",
+	}
+	if len(resources) != len(expected) {
+		t.Fatalf("Expected %d resources, got %d", len(expected), len(resources))
+	}
+	for i, resource := range resources {
+		if resource.Description != expected[i] {
+			t.Errorf("Expected description %q for %s, got %q", expected[i], resource.URI, resource.Description)
+		}
+	}
+
+	t.Run("Cached description is reused for later results", func(t *testing.T) {
+		resources, err := manager.ConvertToMCPResources("widgets", map[string]interface{}{"id": "w-1"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(resources) != 1 || resources[0].Description != "Billing events widget" {
+			t.Errorf("Expected the cached description, got %+v", resources)
+		}
+	})
+}
+
+func TestDescriptionCacheEviction(t *testing.T) {
+	cache := newDescriptionCache(2)
+	cache.set("confluent://widgets/a", "A")
+	cache.set("confluent://widgets/b", "B")
+	cache.set("confluent://widgets/a", "A2") // a is now the most recently set
+	cache.set("confluent://widgets/c", "C")
+
+	if _, ok := cache.get("confluent://widgets/b"); ok {
+		t.Error("Expected the least recently set description to be evicted")
+	}
+	if description, _ := cache.get("confluent://widgets/a"); description != "A2" {
+		t.Errorf("Expected the updated description A2, got %q", description)
+	}
+	if description, _ := cache.get("confluent://widgets/c"); description != "C" {
+		t.Errorf("Expected description C, got %q", description)
+	}
+}
+
+func TestConvertToMCPResourcesNumericIDs(t *testing.T) {
+	manager := NewManager(&fakeInvoker{})
+
+	resources, err := manager.ConvertToMCPResources("schemas", []interface{}{
+		map[string]interface{}{"id": float64(100001), "subject": "orders-value"},
+		map[string]interface{}{"id": 42},
+		map[string]interface{}{"id": 1.5},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"confluent://schemas/100001", "confluent://schemas/42", "confluent://schemas/1.5"}
+	if len(resources) != len(expected) {
+		t.Fatalf("Expected %d resources, got %d", len(expected), len(resources))
+	}
+	for i, resource := range resources {
+		if resource.URI != expected[i] {
+			t.Errorf("Expected URI %q, got %q", expected[i], resource.URI)
+		}
+	}
+	if resources[0].Name != "100001" {
+		t.Errorf("Expected the numeric id to be used as the name, got %q", resources[0].Name)
+	}
+}
+
+func TestConvertToMCPResourcesDeclaredArrayField(t *testing.T) {
+	spec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/iam/v2/widgets": {
+				Get: &openapi.Operation{Responses: map[string]openapi.Response{
+					"200": {Content: map[string]openapi.MediaType{
+						"application/json": {Schema: &openapi.Schema{
+							Type: "object",
+							Properties: map[string]*openapi.Schema{
+								"entries":  {Type: "array", Items: &openapi.Schema{Type: "object"}},
+								"metadata": {Type: "object"},
+							},
+						}},
+					}},
+				}},
+			},
+		},
+	}
+	if _, err := tools.GenerateSemanticTools(spec); err != nil {
+		t.Fatalf("Failed to generate tools: %v", err)
+	}
+
+	manager := NewManager(&fakeInvoker{})
+	resources, err := manager.ConvertToMCPResources("widgets", map[string]interface{}{
+		"entries": []interface{}{
+			map[string]interface{}{"id": "w-1"},
+			map[string]interface{}{"id": "w-2"},
+		},
+		"items":    []interface{}{map[string]interface{}{"id": "not-a-widget"}},
+		"metadata": map[string]interface{}{"next": ""},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(resources) != 2 || resources[0].URI != "confluent://widgets/w-1" || resources[1].URI != "confluent://widgets/w-2" {
+		t.Errorf("Expected the items of the declared entries field, got %+v", resources)
+	}
+}
+
+func TestConvertToMCPResourcesDeclaredIdentityFields(t *testing.T) {
+	spec, err := openapi.ParseOpenAPISpecBytes([]byte(`{
+		"openapi": "3.0.0",
+		"paths": {
+			"/iam/v2/gizmos": {
+				"get": {"responses": {"200": {"content": {"application/json": {
+					"schema": {"$ref": "#/components/schemas/GizmoList"}
+				}}}}}
+			}
+		},
+		"components": {
+			"schemas": {
+				"GizmoList": {
+					"type": "object",
+					"properties": {"data": {"type": "array", "items": {"$ref": "#/components/schemas/Gizmo"}}}
+				},
+				"Gizmo": {
+					"type": "object",
+					"properties": {
+						"gizmo_id": {"type": "string"},
+						"display_name": {"type": "string"},
+						"cluster_id": {"type": "string"}
+					}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	if _, err := tools.GenerateSemanticTools(*spec); err != nil {
+		t.Fatalf("Failed to generate tools: %v", err)
+	}
+
+	mapping, err := tools.GetEndpointMapping(tools.ActionList, "gizmos")
+	if err != nil {
+		t.Fatalf("Expected a list mapping: %v", err)
+	}
+	if want := []string{"cluster_id", "display_name", "gizmo_id"}; !reflect.DeepEqual(mapping.ResponseItemFields, want) {
+		t.Errorf("Expected item fields %v resolved from the $ref, got %v", want, mapping.ResponseItemFields)
+	}
+
+	manager := NewManager(&fakeInvoker{})
+	resources, err := manager.ConvertToMCPResources("gizmos", map[string]interface{}{
+		"data": []interface{}{
+			map[string]interface{}{"cluster_id": "lkc-1", "gizmo_id": "g-1", "display_name": "Orders"},
+			map[string]interface{}{"cluster_id": "lkc-1", "gizmo_id": "g-2"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(resources) != 2 {
+		t.Fatalf("Expected 2 resources, got %+v", resources)
+	}
+	if resources[0].URI != "confluent://gizmos/g-1" || resources[0].Name != "Orders" {
+		t.Errorf("Expected the declared gizmo_id and display_name, got %+v", resources[0])
+	}
+	if resources[1].URI != "confluent://gizmos/g-2" || resources[1].Name != "g-2" {
+		t.Errorf("Expected the id as name without a display_name, got %+v", resources[1])
+	}
+}
+
+func TestConvertToMCPResourcesPrimitiveLists(t *testing.T) {
+	manager := NewManager(&fakeInvoker{})
+
+	tests := []struct {
+		name         string
+		resourceType string
+		list         bool
+		apiResult    interface{}
+		expected     []string
+	}{
+		{
+			name:         "Array of strings",
+			resourceType: "subjects",
+			apiResult:    []interface{}{"subject-a", "subject-b"},
+			expected:     []string{"confluent://subjects/subject-a", "confluent://subjects/subject-b"},
+		},
+		{
+			name:         "Array of numbers",
+			resourceType: "schemas",
+			apiResult:    []interface{}{float64(1), float64(2), float64(3)},
+			expected:     []string{"confluent://schemas/1", "confluent://schemas/2", "confluent://schemas/3"},
+		},
+		{
+			name:         "Array under an unrecognized key in a list result",
+			resourceType: "subjects",
+			list:         true,
+			apiResult:    map[string]interface{}{"subject_names": []interface{}{"subject-a", "subject-b"}, "count": float64(2)},
+			expected:     []string{"confluent://subjects/subject-a", "confluent://subjects/subject-b"},
+		},
+		{
+			name:         "Array under an unrecognized key in another result",
+			resourceType: "clusters",
+			apiResult:    map[string]interface{}{"id": "lkc-1", "endpoints": []interface{}{"a", "b"}},
+			expected:     []string{"confluent://clusters/lkc-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			convert := manager.ConvertToMCPResources
+			if tt.list {
+				convert = manager.convertListToMCPResources
+			}
+			resources, err := convert(tt.resourceType, tt.apiResult)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(resources) != len(tt.expected) {
+				t.Fatalf("Expected %d resources, got %+v", len(tt.expected), resources)
+			}
+			for i, resource := range resources {
+				if resource.URI != tt.expected[i] {
+					t.Errorf("Expected URI %q, got %q", tt.expected[i], resource.URI)
+				}
+				if want := tt.expected[i][len("confluent://"+tt.resourceType+"/"):]; resource.Name != want {
+					t.Errorf("Expected the value %q as the name, got %q", want, resource.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestConfiguredResourceMIMETypes(t *testing.T) {
+	manager := NewManager(&fakeInvoker{})
+	manager.SetMIMETypes(map[string]string{"schema": "application/schema+json"})
+
+	resources, err := manager.ConvertToMCPResources("schemas", []interface{}{
+		map[string]interface{}{"id": float64(100001), "subject": "orders-value"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(resources) != 1 || resources[0].MIMEType != "application/schema+json" {
+		t.Errorf("Expected the schema resource to have the configured MIME type, got %+v", resources)
+	}
+
+	created, err := manager.extractResourceFromCreationResult("schemas", map[string]interface{}{"id": "sch-100002"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if created.MIMEType != "application/schema+json" {
+		t.Errorf("Expected the created schema resource to have the configured MIME type, got %q", created.MIMEType)
+	}
+
+	topics, err := manager.ConvertToMCPResources("topics", []interface{}{map[string]interface{}{"topic_name": "orders"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(topics) != 1 || topics[0].MIMEType != DefaultMIMEType {
+		t.Errorf("Expected other resources to keep %s, got %+v", DefaultMIMEType, topics)
+	}
+}
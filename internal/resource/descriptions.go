@@ -0,0 +1,127 @@
+package resource
+
+import (
+	"container/list"
+	"fmt"
+	"mcolomerc/mcp-server/internal/tools"
+	"strings"
+	"sync"
+)
+
+// descriptionCache remembers the best description seen for each resource URI, so resources
+// registered or read later keep the description a list result carried. Once maxEntries URIs are
+// cached, the least recently set one is evicted.
+type descriptionCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List // Front is most recently set
+}
+
+type descriptionCacheEntry struct {
+	uri         string
+	description string
+}
+
+// newDescriptionCache creates an empty description cache holding up to maxEntries URIs
+func newDescriptionCache(maxEntries int) *descriptionCache {
+	return &descriptionCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// get returns the cached description for a resource URI
+func (c *descriptionCache) get(uri string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[uri]
+	if !ok {
+		return "", false
+	}
+	return element.Value.(*descriptionCacheEntry).description, true
+}
+
+// set caches the description for a resource URI
+func (c *descriptionCache) set(uri, description string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[uri]; ok {
+		element.Value.(*descriptionCacheEntry).description = description
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[uri] = c.order.PushFront(&descriptionCacheEntry{uri: uri, description: description})
+	if c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*descriptionCacheEntry).uri)
+	}
+}
+
+// describeResource picks a resource description: an item-level description field first, then one
+// cached from an earlier result, then the spec's operation summary, and finally a generic label.
+// Item-level descriptions are cached for later lookups.
+func (m *Manager) describeResource(resourceType, uri, name string, item interface{}) string {
+	if description := itemDescription(item); description != "" {
+		m.descriptions.set(uri, description)
+		return description
+	}
+	if description, ok := m.descriptions.get(uri); ok {
+		return description
+	}
+	if summary := operationSummary(resourceType); summary != "" {
+		return fmt.Sprintf("%s resource: %s - %s", strings.Title(resourceType), name, summary)
+	}
+	return fmt.Sprintf("%s resource: %s", strings.Title(resourceType), name)
+}
+
+// itemDescription returns the first non-empty description field of an item, looking at the
+// top level and then at the nested "spec" object Confluent v2 APIs use
+func itemDescription(item interface{}) string {
+	itemMap, ok := item.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	candidates := []map[string]interface{}{itemMap}
+	if spec, ok := itemMap["spec"].(map[string]interface{}); ok {
+		candidates = append(candidates, spec)
+	}
+	for _, candidate := range candidates {
+		for _, field := range CommonDescriptionFields {
+			if value, ok := candidate[field].(string); ok && value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// operationSummary returns the summary of the spec operation that reads a resource type, falling
+// back to the list operation
+func operationSummary(resourceType string) string {
+	spec := tools.GetRegistrySpec()
+	if spec == nil {
+		return ""
+	}
+	for _, action := range []string{tools.ActionGet, tools.ActionList} {
+		mapping, err := tools.GetEndpointMapping(action, resourceType)
+		if err != nil {
+			continue
+		}
+		if operation := spec.FindOperation(mapping.Method, mapping.PathPattern); operation != nil && operation.Summary != "" {
+			return operation.Summary
+		}
+	}
+	return ""
+}
@@ -2,6 +2,7 @@ package resource
 
 import (
 	"fmt"
+	"mcolomerc/mcp-server/internal/tools"
 	"os"
 	"strings"
 
@@ -9,20 +10,21 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// HandleResourceCreation registers a newly created resource with the MCP server
-func (m *Manager) HandleResourceCreation(mcpServer *server.MCPServer, args map[string]interface{}, result interface{}) {
+// HandleResourceCreation registers a newly created resource with the MCP server and returns its
+// URI, or an empty string when the resource could not be identified from the result
+func (m *Manager) HandleResourceCreation(mcpServer *server.MCPServer, args map[string]interface{}, result interface{}) string {
 	// Extract resource type from arguments
 	resourceType, ok := args["resource"].(string)
 	if !ok {
 		fmt.Fprintf(os.Stderr, "Warning: Could not extract resource type from creation arguments\n")
-		return
+		return ""
 	}
 
 	// Try to extract the created resource information from the result
 	resource, err := m.extractResourceFromCreationResult(resourceType, result)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Could not extract resource info from creation result: %v\n", err)
-		return
+		return ""
 	}
 
 	// Register the new resource with the MCP server
@@ -30,6 +32,7 @@ func (m *Manager) HandleResourceCreation(mcpServer *server.MCPServer, args map[s
 	mcpServer.AddResource(resource, handler)
 
 	fmt.Fprintf(os.Stderr, "Auto-registered new resource: %s (%s)\n", resource.Name, resource.URI)
+	return resource.URI
 }
 
 // extractResourceFromCreationResult extracts resource information from a creation API response
@@ -42,7 +45,8 @@ func (m *Manager) extractResourceFromCreationResult(resourceType string, result
 	// Extract resource identifier and name based on resource type
 	var id, name string
 
-	switch resourceType {
+	// Match "topic" and "topics" alike
+	switch tools.PluralResourceName(resourceType) {
 	case "topics":
 		if topicName, exists := resultMap["topic_name"]; exists {
 			if nameStr, ok := topicName.(string); ok {
@@ -95,16 +99,23 @@ func (m *Manager) extractResourceFromCreationResult(resourceType string, result
 		name = id
 	}
 
-	description := fmt.Sprintf("Auto-registered %s resource: %s", strings.Title(resourceType), name)
-
 	// Create the URI for this resource
 	uri := fmt.Sprintf("%s%s%s%s", ConfluentURIScheme, resourceType, URIPathSeparator, id)
 
+	description := itemDescription(resultMap)
+	if description != "" {
+		m.descriptions.set(uri, description)
+	} else if cached, ok := m.descriptions.get(uri); ok {
+		description = cached
+	} else {
+		description = fmt.Sprintf("Auto-registered %s resource: %s", strings.Title(resourceType), name)
+	}
+
 	return mcp.Resource{
 		URI:         uri,
 		Name:        name,
 		Description: description,
-		MIMEType:    "application/json",
+		MIMEType:    m.mimeType(resourceType),
 	}, nil
 }
 
@@ -138,8 +149,13 @@ func (m *Manager) HandleResourceDeletion(args map[string]interface{}) {
 
 // extractResourceIDFromDeletionArgs extracts the resource identifier from deletion arguments
 func (m *Manager) extractResourceIDFromDeletionArgs(resourceType string, args map[string]interface{}) string {
-	// Check resource-specific mappings first
-	if fieldNames, exists := ResourceTypeIDMappings[resourceType]; exists {
+	// Check the configured identifier argument, then resource-specific mappings
+	if idParam := tools.ResourceIDParam(resourceType); idParam != "" {
+		if strValue, ok := args[idParam].(string); ok {
+			return strValue
+		}
+	}
+	if fieldNames, exists := ResourceTypeIDMappings[tools.PluralResourceName(resourceType)]; exists {
 		for _, fieldName := range fieldNames {
 			if value, exists := args[fieldName]; exists {
 				if strValue, ok := value.(string); ok {
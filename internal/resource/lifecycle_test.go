@@ -0,0 +1,41 @@
+package resource
+
+import (
+	"mcolomerc/mcp-server/internal/tools"
+	"testing"
+)
+
+func TestLifecycleAcceptsSingularResourceTypes(t *testing.T) {
+	manager := NewManager(&fakeInvoker{})
+
+	for _, resourceType := range []string{"topics", "topic"} {
+		t.Run(resourceType, func(t *testing.T) {
+			created, err := manager.extractResourceFromCreationResult(resourceType, map[string]interface{}{
+				"topic_name": "orders",
+				"kind":       "KafkaTopic",
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if created.Name != "orders" {
+				t.Errorf("Expected name 'orders', got %q", created.Name)
+			}
+
+			deletedID := manager.extractResourceIDFromDeletionArgs(resourceType, map[string]interface{}{"topicName": "orders"})
+			if deletedID != "orders" {
+				t.Errorf("Expected deleted id 'orders', got %q", deletedID)
+			}
+		})
+	}
+}
+
+func TestLifecycleUsesConfiguredResourceIDParam(t *testing.T) {
+	tools.SetResourceIDParams(map[string]string{"gizmos": "gizmo_ref"})
+	t.Cleanup(func() { tools.SetResourceIDParams(nil) })
+
+	manager := NewManager(&fakeInvoker{})
+	deletedID := manager.extractResourceIDFromDeletionArgs("gizmo", map[string]interface{}{"gizmo_ref": "g-1"})
+	if deletedID != "g-1" {
+		t.Errorf("Expected deleted id 'g-1', got %q", deletedID)
+	}
+}
@@ -14,7 +14,9 @@ import (
 
 // Manager handles resource discovery, registration, and lifecycle management
 type Manager struct {
-	invoker ToolInvoker // Interface for invoking tools
+	invoker      ToolInvoker       // Interface for invoking tools
+	descriptions *descriptionCache // Best known description per resource URI
+	mimeTypes    map[string]string // Plural resource type -> MIME type, see SetMIMETypes
 }
 
 // ToolInvoker interface for invoking tools (allows for dependency injection)
@@ -25,7 +27,8 @@ type ToolInvoker interface {
 // NewManager creates a new resource manager
 func NewManager(invoker ToolInvoker) *Manager {
 	return &Manager{
-		invoker: invoker,
+		invoker:      invoker,
+		descriptions: newDescriptionCache(MaxCachedDescriptions),
 	}
 }
 
@@ -81,22 +84,24 @@ func (m *Manager) CreateResourceReadHandler(resourceType string) func(context.Co
 func (m *Manager) getResourceInstancesOfType(resourceType string) ([]mcp.Resource, error) {
 	// Skip resource discovery for certain resource types that don't support general listing
 	// or that cause discovery loops
-	skipDiscovery := []string{"tags", "businessmetadatadefs", "tagdefs", "environments", "costs"} // Add problematic resources
-	for _, skip := range skipDiscovery {
-		if resourceType == skip {
-			fmt.Fprintf(os.Stderr, "Skipping discovery for %s (requires specific entity parameters)\n", resourceType)
-			// Return a placeholder resource to indicate the resource type is available
-			return []mcp.Resource{
-				{
-					URI:         fmt.Sprintf("confluent://%s/%s-placeholder", resourceType, resourceType),
-					Name:        fmt.Sprintf("%s-placeholder", resourceType),
-					Description: fmt.Sprintf("Placeholder for %s resource type - use tools to interact", resourceType),
-					MIMEType:    "application/json",
-				},
-			}, nil
-		}
-	}
+	if ShouldSkipDiscovery(resourceType) {
+		fmt.Fprintf(os.Stderr, "Skipping discovery for %s (requires specific entity parameters)\n", resourceType)
+		// Return a placeholder resource to indicate the resource type is available
+		return []mcp.Resource{
+			{
+				URI:         fmt.Sprintf("confluent://%s/%s-placeholder", resourceType, resourceType),
+				Name:        fmt.Sprintf("%s-placeholder", resourceType),
+				Description: fmt.Sprintf("Placeholder for %s resource type - use tools to interact", resourceType),
+				MIMEType:    m.mimeType(resourceType),
+			},
+		}, nil
+	}
+
+	return m.listResourcesOfType(resourceType)
+}
 
+// listResourcesOfType invokes the 'list' tool for a resource type and converts the result to MCP resources
+func (m *Manager) listResourcesOfType(resourceType string) ([]mcp.Resource, error) {
 	// Use the 'list' tool to get all instances of this resource type
 	invokeReq := InvokeRequest{
 		Tool: tools.ActionList,
@@ -111,7 +116,7 @@ func (m *Manager) getResourceInstancesOfType(resourceType string) ([]mcp.Resourc
 	}
 
 	// Convert the API response to MCP resources
-	return m.ConvertToMCPResources(resourceType, resp.Result)
+	return m.convertListToMCPResources(resourceType, resp.Result)
 }
 
 // HandleResourceRead handles reading a specific resource
@@ -142,16 +147,28 @@ func (m *Manager) HandleResourceRead(ctx context.Context, request mcp.ReadResour
 	}
 
 	if _, supported := getResources[resourceType]; !supported {
-		return nil, fmt.Errorf("resource type '%s' does not support 'get' action", resourceType)
+		supportedTypes := make([]string, 0, len(getResources))
+		for name := range getResources {
+			supportedTypes = append(supportedTypes, name)
+		}
+		canonical, found := tools.ResolveResourceName(resourceType, supportedTypes)
+		if !found {
+			return nil, fmt.Errorf("resource type '%s' does not support 'get' action", resourceType)
+		}
+		resourceType = canonical
 	}
 
-	// Use the 'get' tool to fetch this specific resource
+	// Use the 'get' tool to fetch this specific resource, passing the identifier under the
+	// argument configured for the type or else the derived one (topics -> topicId)
+	idParam := tools.ResourceIDParam(resourceType)
+	if idParam == "" {
+		idParam = tools.SingularResourceName(resourceType) + "Id"
+	}
 	invokeReq := InvokeRequest{
 		Tool: tools.ActionGet,
 		Arguments: map[string]interface{}{
 			"resource": resourceType,
-			// Add the resource identifier as a parameter
-			strings.TrimSuffix(resourceType, "s") + "Id": resourceID, // topics -> topicId
+			idParam:    resourceID,
 		},
 	}
 
@@ -168,7 +185,7 @@ func (m *Manager) HandleResourceRead(ctx context.Context, request mcp.ReadResour
 
 	return []mcp.ResourceContents{mcp.TextResourceContents{
 		URI:      uri,
-		MIMEType: "application/json",
+		MIMEType: m.mimeType(resourceType),
 		Text:     string(resultJSON),
 	}}, nil
 }
@@ -0,0 +1,25 @@
+package resource
+
+import "mcolomerc/mcp-server/internal/tools"
+
+// DefaultMIMEType is the MIME type of resources whose type has no configured one
+const DefaultMIMEType = "application/json"
+
+// SetMIMETypes sets the MIME type advertised for the resources of each type, e.g.
+// schemas=application/schema+json. Keys may be singular or plural. Must be set before
+// resources are registered.
+func (m *Manager) SetMIMETypes(mimeTypes map[string]string) {
+	normalized := make(map[string]string, len(mimeTypes))
+	for resourceType, mimeType := range mimeTypes {
+		normalized[tools.PluralResourceName(resourceType)] = mimeType
+	}
+	m.mimeTypes = normalized
+}
+
+// mimeType returns the MIME type of the resources of a type
+func (m *Manager) mimeType(resourceType string) string {
+	if mimeType := m.mimeTypes[tools.PluralResourceName(resourceType)]; mimeType != "" {
+		return mimeType
+	}
+	return DefaultMIMEType
+}
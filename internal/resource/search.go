@@ -0,0 +1,107 @@
+package resource
+
+import (
+	"mcolomerc/mcp-server/internal/tools"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SearchMatch describes a single resource instance matching a search query
+type SearchMatch struct {
+	ResourceType string `json:"resource_type"`
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	URI          string `json:"uri"`
+	Description  string `json:"description,omitempty"`
+}
+
+// SearchResult holds the outcome of a search across resource types
+type SearchResult struct {
+	Query     string            `json:"query"`
+	Matches   []SearchMatch     `json:"matches"`
+	Truncated bool              `json:"truncated"`
+	Errors    map[string]string `json:"errors,omitempty"` // Resource type -> list error
+}
+
+// Search lists each resource type concurrently and returns the instances whose id or name
+// contains the query (case-insensitive). When resourceTypes is empty, every listable
+// resource type in the semantic registry is searched.
+func (m *Manager) Search(query string, resourceTypes []string) SearchResult {
+	result := SearchResult{
+		Query:   query,
+		Matches: []SearchMatch{},
+	}
+
+	if len(resourceTypes) == 0 {
+		resourceTypes = searchableResourceTypes()
+	}
+
+	queryLower := strings.ToLower(query)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, SearchConcurrency)
+
+	for _, resourceType := range resourceTypes {
+		wg.Add(1)
+		go func(resourceType string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			resources, err := m.listResourcesOfType(resourceType)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if result.Errors == nil {
+					result.Errors = make(map[string]string)
+				}
+				result.Errors[resourceType] = err.Error()
+				return
+			}
+
+			prefix := ConfluentURIScheme + resourceType + URIPathSeparator
+			for _, res := range resources {
+				id := strings.TrimPrefix(res.URI, prefix)
+				if strings.Contains(strings.ToLower(id), queryLower) || strings.Contains(strings.ToLower(res.Name), queryLower) {
+					result.Matches = append(result.Matches, SearchMatch{
+						ResourceType: resourceType,
+						ID:           id,
+						Name:         res.Name,
+						URI:          res.URI,
+						Description:  res.Description,
+					})
+				}
+			}
+		}(resourceType)
+	}
+	wg.Wait()
+
+	// Sort for consistent ordering before applying the cap
+	sort.Slice(result.Matches, func(i, j int) bool {
+		if result.Matches[i].ResourceType != result.Matches[j].ResourceType {
+			return result.Matches[i].ResourceType < result.Matches[j].ResourceType
+		}
+		return result.Matches[i].ID < result.Matches[j].ID
+	})
+
+	if len(result.Matches) > MaxSearchResults {
+		result.Matches = result.Matches[:MaxSearchResults]
+		result.Truncated = true
+	}
+
+	return result
+}
+
+// searchableResourceTypes returns all resource types that support the 'list' action and can be listed without extra parameters
+func searchableResourceTypes() []string {
+	var resourceTypes []string
+	for _, resourceType := range tools.GetSupportedResources(tools.ActionList) {
+		if !ShouldSkipDiscovery(resourceType) && !IsExcludedResourceType(resourceType) {
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+	}
+	return resourceTypes
+}
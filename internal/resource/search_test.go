@@ -0,0 +1,95 @@
+package resource
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// fakeInvoker returns canned list results per resource type
+type fakeInvoker struct {
+	mu        sync.Mutex
+	responses map[string]InvokeResponse
+	calls     []string
+}
+
+func (f *fakeInvoker) InvokeTool(req InvokeRequest) InvokeResponse {
+	resourceType, _ := req.Arguments["resource"].(string)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, resourceType)
+
+	if resp, ok := f.responses[resourceType]; ok {
+		return resp
+	}
+	return InvokeResponse{Error: fmt.Sprintf("unknown resource %s", resourceType)}
+}
+
+func TestSearchAcrossResourceTypes(t *testing.T) {
+	invoker := &fakeInvoker{
+		responses: map[string]InvokeResponse{
+			"topics": {Result: map[string]interface{}{
+				"data": []interface{}{
+					map[string]interface{}{"topic_name": "orders-v1"},
+					map[string]interface{}{"topic_name": "payments"},
+					map[string]interface{}{"topic_name": "ORDERS-dlq"},
+				},
+			}},
+			"connectors": {Result: []interface{}{"orders-sink", "inventory-source"}},
+		},
+	}
+	manager := NewManager(invoker)
+
+	result := manager.Search("orders", []string{"topics", "connectors"})
+
+	if len(invoker.calls) != 2 {
+		t.Errorf("Expected 2 list calls, got %d: %v", len(invoker.calls), invoker.calls)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("Expected no errors, got %v", result.Errors)
+	}
+	if result.Truncated {
+		t.Error("Expected result not to be truncated")
+	}
+
+	expected := []SearchMatch{
+		{ResourceType: "connectors", ID: "orders-sink", Name: "orders-sink", URI: "confluent://connectors/orders-sink"},
+		{ResourceType: "topics", ID: "ORDERS-dlq", Name: "ORDERS-dlq", URI: "confluent://topics/ORDERS-dlq"},
+		{ResourceType: "topics", ID: "orders-v1", Name: "orders-v1", URI: "confluent://topics/orders-v1"},
+	}
+	if len(result.Matches) != len(expected) {
+		t.Fatalf("Expected %d matches, got %d: %+v", len(expected), len(result.Matches), result.Matches)
+	}
+	for i, match := range result.Matches {
+		if match.ResourceType != expected[i].ResourceType || match.ID != expected[i].ID ||
+			match.Name != expected[i].Name || match.URI != expected[i].URI {
+			t.Errorf("Match %d: expected %+v, got %+v", i, expected[i], match)
+		}
+	}
+}
+
+func TestSearchCapsResultsAndReportsErrors(t *testing.T) {
+	var items []interface{}
+	for i := 0; i < MaxSearchResults+10; i++ {
+		items = append(items, fmt.Sprintf("topic-%03d", i))
+	}
+	invoker := &fakeInvoker{
+		responses: map[string]InvokeResponse{
+			"topics": {Result: items},
+		},
+	}
+	manager := NewManager(invoker)
+
+	result := manager.Search("topic", []string{"topics", "schemas"})
+
+	if len(result.Matches) != MaxSearchResults {
+		t.Errorf("Expected %d matches, got %d", MaxSearchResults, len(result.Matches))
+	}
+	if !result.Truncated {
+		t.Error("Expected result to be truncated")
+	}
+	if _, ok := result.Errors["schemas"]; !ok {
+		t.Errorf("Expected an error for 'schemas', got %v", result.Errors)
+	}
+}
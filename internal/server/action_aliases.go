@@ -0,0 +1,68 @@
+package server
+
+import (
+	"fmt"
+	"mcolomerc/mcp-server/internal/config"
+	"mcolomerc/mcp-server/internal/tools"
+	"os"
+)
+
+// actionAliases renames generated tools for clients, from ACTION_ALIASES. Handlers and the
+// registry keep using the action names; the alias is only the name clients see and call.
+type actionAliases struct {
+	exposed   map[string]string // action -> alias
+	canonical map[string]string // alias -> action
+}
+
+// newActionAliases resolves the configured aliases against the generated tools. Aliases of
+// unknown tools, or that would shadow a generated or reserved tool, are skipped with a warning.
+func newActionAliases(configured map[string]string, generated []tools.Tool, reserved []string) actionAliases {
+	aliases := actionAliases{exposed: make(map[string]string), canonical: make(map[string]string)}
+	names := make(map[string]bool, len(generated))
+	for _, tool := range generated {
+		names[tool.Name] = true
+	}
+	taken := make(map[string]bool, len(reserved))
+	for _, name := range reserved {
+		taken[name] = true
+	}
+
+	for action, alias := range configured {
+		switch {
+		case alias == "" || alias == action:
+			continue
+		case !names[action]:
+			fmt.Fprintf(os.Stderr, "Warning: ACTION_ALIASES renames unknown tool '%s', skipping\n", action)
+			continue
+		case names[alias] || taken[alias] || aliases.canonical[alias] != "":
+			fmt.Fprintf(os.Stderr, "Warning: ACTION_ALIASES alias '%s' for '%s' clashes with another tool, skipping\n", alias, action)
+			continue
+		}
+		aliases.exposed[action] = alias
+		aliases.canonical[alias] = action
+	}
+	return aliases
+}
+
+// ReservedToolNames are the names of the built-in tools and the CUSTOM_TOOLS file, which an
+// alias or a semantic action rule may not take
+func ReservedToolNames(cfg *config.Config) []string {
+	customNames, _ := customToolNames(cfg.CustomTools)
+	return append(append([]string(nil), builtinToolNames...), customNames...)
+}
+
+// exposedToolName returns the name clients see for a generated tool
+func (s *MCPServer) exposedToolName(name string) string {
+	if alias, ok := s.actionAliases.exposed[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// canonicalToolName maps an aliased tool name back to its action; other names are returned as is
+func (s *MCPServer) canonicalToolName(name string) string {
+	if action, ok := s.actionAliases.canonical[name]; ok {
+		return action
+	}
+	return name
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestActionAliases(t *testing.T) {
+	var gotMethod, gotPath string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	cfg.ActionAliases = map[string]string{
+		tools.ActionGet:    "read",
+		tools.ActionDelete: "remove",
+		tools.ActionUpdate: tools.ActionList, // Clashes with the list tool, so it is skipped
+	}
+	s := newTopicsTestServer(t, cfg)
+
+	t.Run("Aliased tools are exposed under their alias", func(t *testing.T) {
+		exposed := s.GetCapabilities().Tools
+		for _, name := range []string{"read", "remove", tools.ActionList, tools.ActionUpdate} {
+			if !slices.Contains(exposed, name) {
+				t.Errorf("Expected tool %s to be exposed, got %v", name, exposed)
+			}
+		}
+		for _, name := range []string{tools.ActionGet, tools.ActionDelete} {
+			if slices.Contains(exposed, name) {
+				t.Errorf("Expected tool %s to be renamed, got %v", name, exposed)
+			}
+		}
+	})
+
+	t.Run("Calling an alias routes to its action", func(t *testing.T) {
+		text := callTool(t, s, "read", map[string]interface{}{"resource": "topics", "topic_name": "orders"})
+		if strings.HasPrefix(text, "Error") {
+			t.Fatalf("Unexpected error: %s", text)
+		}
+		if gotMethod != http.MethodGet || gotPath != "/kafka/v3/clusters/lkc-test/topics/orders" {
+			t.Errorf("Expected GET of the topic, got %s %s", gotMethod, gotPath)
+		}
+
+		resp := s.InvokeTool(InvokeRequest{Tool: "remove", Arguments: map[string]interface{}{"resource": "topics", "topic_name": "orders"}})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		if gotMethod != http.MethodDelete {
+			t.Errorf("Expected remove to DELETE the topic, got %s", gotMethod)
+		}
+	})
+
+	t.Run("Auxiliary tools advertise the exposed names", func(t *testing.T) {
+		response := s.mcpServer.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		result := response.(mcp.JSONRPCResponse).Result.(mcp.ListToolsResult)
+		checked := 0
+		for _, tool := range result.Tools {
+			if tool.Name != OperationSpecToolName && tool.Name != RequiredParamsToolName {
+				continue
+			}
+			checked++
+			enum, _ := tool.InputSchema.Properties["action"].(map[string]any)["enum"].([]string)
+			if !slices.Contains(enum, "read") || slices.Contains(enum, tools.ActionGet) {
+				t.Errorf("Expected %s to advertise read instead of get, got %v", tool.Name, enum)
+			}
+		}
+		if checked != 2 {
+			t.Errorf("Expected the operation spec and required params tools, checked %d", checked)
+		}
+	})
+
+	t.Run("Action names keep working for direct invocations", func(t *testing.T) {
+		resp := s.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: map[string]interface{}{"resource": "topics", "topic_name": "orders"}})
+		if resp.Error != "" || gotMethod != http.MethodGet {
+			t.Errorf("Expected get to still GET the topic, got %s (error %q)", gotMethod, resp.Error)
+		}
+	})
+}
+
+func TestActionAliasesReservedNames(t *testing.T) {
+	generated := []tools.Tool{{Name: tools.ActionList}, {Name: tools.ActionGet}, {Name: tools.ActionCreate}}
+	aliases := newActionAliases(map[string]string{
+		tools.ActionList:   BatchToolName,
+		tools.ActionGet:    "greet",
+		tools.ActionCreate: "make",
+	}, generated, append([]string{"greet"}, builtinToolNames...))
+
+	if len(aliases.exposed) != 1 || aliases.exposed[tools.ActionCreate] != "make" {
+		t.Errorf("Expected only create to be aliased, got %v", aliases.exposed)
+	}
+}
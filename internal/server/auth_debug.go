@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// AuthDebugInfo is how a call to an endpoint would be authenticated and where it would be sent.
+// Credentials are named by their environment variables, never shown.
+type AuthDebugInfo struct {
+	Method                string `json:"method"`
+	Path                  string `json:"path"`
+	SecurityType          string `json:"security_type"`
+	CredentialService     string `json:"credential_service"`
+	CredentialKeyVar      string `json:"credential_key_var"`
+	CredentialSecretVar   string `json:"credential_secret_var"`
+	CredentialsConfigured bool   `json:"credentials_configured"`
+	UsesClientToken       bool   `json:"uses_client_token,omitempty"` // USE_CLIENT_TOKEN replaces the credentials with the client's bearer token
+	BaseURL               string `json:"base_url"`
+}
+
+// ResolveAuth resolves the security type, credentials and base URL of an endpoint, given either
+// a method and path or an action and resource, the way ExecuteAPICall would for a call to it
+func (s *MCPServer) ResolveAuth(method, path, action, resource string) (*AuthDebugInfo, error) {
+	spec := s.spec
+	if action != "" || resource != "" {
+		if action == "" || resource == "" {
+			return nil, fmt.Errorf("'action' and 'resource' must be given together")
+		}
+		mapping, mappingSpec, err := s.lookupOperationMapping(action, resource)
+		if err != nil {
+			return nil, err
+		}
+		method, path, spec = mapping.Method, mapping.PathPattern, mappingSpec
+	} else if method == "" || path == "" {
+		return nil, fmt.Errorf("either 'method' and 'path' or 'action' and 'resource' are required")
+	} else if s.telemetrySpec != nil && spec.FindOperation(method, path) == nil && s.telemetrySpec.FindOperation(method, path) != nil {
+		spec = s.telemetrySpec
+	}
+	method = strings.ToUpper(method)
+	path = stripSpecBasePath(s.config.SpecBasePath, path)
+
+	securityType := DetermineSecurityTypeFromSpec(spec, method, path)
+	source := credentialSourceFor(securityType, path)
+	apiKey, apiSecret := getAPICredentials(s.config, securityType, path)
+
+	baseURL := getBaseURL(s.config, path, nil)
+	if s.config.UseSpecServers {
+		serverURL, err := resolveSpecServerURL(s.config, spec, path, nil)
+		if err != nil {
+			return nil, err
+		}
+		if serverURL != "" {
+			baseURL = serverURL
+		}
+	}
+
+	return &AuthDebugInfo{
+		Method:                method,
+		Path:                  path,
+		SecurityType:          securityType,
+		CredentialService:     source.Service,
+		CredentialKeyVar:      source.KeyVar,
+		CredentialSecretVar:   source.SecretVar,
+		CredentialsConfigured: apiKey != "" && apiSecret != "",
+		UsesClientToken:       s.config.UseClientToken,
+		BaseURL:               baseURL,
+	}, nil
+}
+
+// addAuthDebugTool adds the admin tool that shows how calls to an endpoint are authenticated
+func (s *MCPServer) addAuthDebugTool(mcpServer *server.MCPServer) {
+	authDebugSchema := mcp.ToolInputSchema{
+		Type: "object",
+		Properties: map[string]any{
+			"method": map[string]any{
+				"type":        "string",
+				"description": "HTTP method of the endpoint (e.g. GET), together with 'path'",
+			},
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Spec path of the endpoint (e.g. /kafka/v3/clusters/{cluster_id}/topics)",
+			},
+			"action": map[string]any{
+				"type":        "string",
+				"description": "Semantic action (e.g. list), together with 'resource', instead of method and path",
+			},
+			"resource": map[string]any{
+				"type":        "string",
+				"description": "Resource of the action (e.g. topics)",
+			},
+		},
+	}
+
+	authDebugTool := mcp.Tool{
+		Name:        AuthDebugToolName,
+		Description: "Show the security type, the credential environment variables and the base URL a call to an endpoint would use. Credential values are never shown",
+		InputSchema: authDebugSchema,
+	}
+
+	mcpServer.AddTool(authDebugTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: Invalid arguments format",
+					},
+				},
+			}, nil
+		}
+
+		method, _ := args["method"].(string)
+		path, _ := args["path"].(string)
+		action, _ := args["action"].(string)
+		resource, _ := args["resource"].(string)
+		info, err := s.ResolveAuth(method, path, action, resource)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: " + err.Error(),
+					},
+				},
+			}, nil
+		}
+
+		resultJSON, err := marshalToolResult(info, s.config.PrettyJSON)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Failed to format result",
+					},
+				},
+			}, nil
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: string(resultJSON),
+				},
+			},
+		}, nil
+	})
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAuthDebugTool(t *testing.T) {
+	cfg := newTestConfig(t, "https://kafka.example")
+	cfg.EnableAdminTools = true
+	s := newTopicsTestServer(t, cfg)
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		wantType    string
+		wantKeyVar  string
+		wantBaseURL string
+	}{
+		{
+			name:        "Kafka endpoint by action and resource",
+			args:        map[string]interface{}{"action": "list", "resource": "topics"},
+			wantType:    SecurityTypeResourceAPIKey,
+			wantKeyVar:  "KAFKA_API_KEY",
+			wantBaseURL: "https://kafka.example",
+		},
+		{
+			name:        "Cloud endpoint by method and path",
+			args:        map[string]interface{}{"method": "get", "path": "/org/v2/environments"},
+			wantType:    SecurityTypeCloudAPIKey,
+			wantKeyVar:  "CONFLUENT_CLOUD_API_KEY",
+			wantBaseURL: BaseURLConfluentCloud,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := callTool(t, s, AuthDebugToolName, tt.args)
+			var info AuthDebugInfo
+			if err := json.Unmarshal([]byte(text), &info); err != nil {
+				t.Fatalf("Failed to decode %q: %v", text, err)
+			}
+			if info.SecurityType != tt.wantType || info.CredentialKeyVar != tt.wantKeyVar || info.BaseURL != tt.wantBaseURL {
+				t.Errorf("Expected %s with %s at %s, got %+v", tt.wantType, tt.wantKeyVar, tt.wantBaseURL, info)
+			}
+			if !info.CredentialsConfigured {
+				t.Error("Expected the test credentials to be reported as configured")
+			}
+			if strings.Contains(text, "test-kafka-secret") || strings.Contains(text, "test-cloud-secret") {
+				t.Errorf("Expected no credential values in the result, got %s", text)
+			}
+		})
+	}
+
+	t.Run("Incomplete arguments are rejected", func(t *testing.T) {
+		if text := callTool(t, s, AuthDebugToolName, map[string]interface{}{"action": "list"}); !strings.HasPrefix(text, "Error: ") {
+			t.Errorf("Expected an error, got %s", text)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"fmt"
+	"mcolomerc/mcp-server/internal/config"
+	"mcolomerc/mcp-server/internal/tools"
+	"strings"
+)
+
+// credentialSource names the service whose credentials authenticate a call and the environment
+// variables they are configured with
+type credentialSource struct {
+	Service   string
+	KeyVar    string
+	SecretVar string
+}
+
+// cloudCredentialSource is used by Cloud API calls and anything not served by a resource key
+var cloudCredentialSource = credentialSource{"Confluent Cloud", "CONFLUENT_CLOUD_API_KEY", "CONFLUENT_CLOUD_API_SECRET"}
+
+// resourceCredentialSources are the credentials of resource-api-key calls per service
+var resourceCredentialSources = map[string]credentialSource{
+	tools.ServiceKafka:          {"Kafka", "KAFKA_API_KEY", "KAFKA_API_SECRET"},
+	tools.ServiceFlink:          {"Flink", "FLINK_API_KEY", "FLINK_API_SECRET"},
+	tools.ServiceSchemaRegistry: {"Schema Registry", "SCHEMA_REGISTRY_API_KEY", "SCHEMA_REGISTRY_API_SECRET"},
+	tools.ServiceTableflow:      {"Tableflow", "TABLEFLOW_API_KEY", "TABLEFLOW_API_SECRET"},
+}
+
+// credentialSourceFor returns the credentials a call with the given security type and path uses,
+// mirroring getAPICredentials
+func credentialSourceFor(securityType, path string) credentialSource {
+	if securityType != SecurityTypeResourceAPIKey {
+		return cloudCredentialSource
+	}
+	if source, ok := resourceCredentialSources[tools.ServiceForPath(path)]; ok {
+		return source
+	}
+	return cloudCredentialSource
+}
+
+// authenticationError explains a 401 response: a rejected client token needs the MCP client to
+// re-authenticate, rejected API keys need the credentials of the named service checked
+func authenticationError(securityType, path, profile string, bearer bool, responseBody []byte) error {
+	body := strings.TrimSpace(string(responseBody))
+	if bearer {
+		return fmt.Errorf("authentication failed (401): the bearer token was rejected, re-authenticate the MCP client: %s", body)
+	}
+
+	source := credentialSourceFor(securityType, path)
+	credentials := fmt.Sprintf("%s and %s", source.KeyVar, source.SecretVar)
+	if profile != "" && profile != config.DefaultProfileName {
+		credentials += fmt.Sprintf(" of credential profile '%s'", profile)
+	}
+	return fmt.Errorf("authentication failed (401) for %s: check the API key and secret (%s): %s", source.Service, credentials, body)
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"mcolomerc/mcp-server/internal/logger"
+	"mcolomerc/mcp-server/internal/tools"
+	"sync"
+)
+
+// BatchRequest runs one semantic tool over a list of argument sets
+type BatchRequest struct {
+	Tool        string                   `json:"tool"`
+	Resource    string                   `json:"resource"`
+	Items       []map[string]interface{} `json:"items"`
+	Concurrency int                      `json:"concurrency,omitempty"` // 1 (default) runs items sequentially
+	SessionID   string                   `json:"session_id,omitempty"`
+	ClientToken string                   `json:"-"`
+	Context     context.Context          `json:"-"` // Parent context of the batch call, carrying its trace span
+}
+
+// BatchItemResult holds the outcome of a single batch item
+type BatchItemResult struct {
+	Index    int         `json:"index"`
+	Result   interface{} `json:"result,omitempty"`
+	Error    string      `json:"error,omitempty"`
+	Warnings []string    `json:"warnings,omitempty"`
+	ETag     string      `json:"etag,omitempty"`
+}
+
+// BatchResult holds the per-item outcomes of a batch call, in item order
+type BatchResult struct {
+	Tool      string            `json:"tool"`
+	Resource  string            `json:"resource"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Items     []BatchItemResult `json:"items"`
+}
+
+// InvokeBatch validates a batch request and executes its items. The batch is checked by loop
+// detection once as a single logical call; individual items only go through injection checks.
+// Failed items do not stop the batch.
+func (s *MCPServer) InvokeBatch(req BatchRequest) (BatchResult, error) {
+	req.Tool = s.canonicalToolName(req.Tool)
+	if req.Tool == BatchToolName || !tools.IsSemanticAction(req.Tool) {
+		return BatchResult{}, fmt.Errorf("tool '%s' cannot be used in a batch: must be a semantic tool", req.Tool)
+	}
+	if req.Resource == "" {
+		return BatchResult{}, fmt.Errorf("'resource' is required")
+	}
+	if len(req.Items) == 0 {
+		return BatchResult{}, fmt.Errorf("'items' must contain at least one argument set")
+	}
+	if len(req.Items) > BatchMaxItems {
+		return BatchResult{}, fmt.Errorf("too many items: %d (max %d)", len(req.Items), BatchMaxItems)
+	}
+
+	concurrency := req.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > BatchMaxConcurrency {
+		concurrency = BatchMaxConcurrency
+	}
+
+	if s.guardrails != nil {
+		guardrailsResult := s.guardrails.ValidateToolInput(BatchToolName, map[string]interface{}{
+			"tool":     req.Tool,
+			"resource": req.Resource,
+			"items":    req.Items,
+		})
+		if guardrailsResult.Blocked {
+			logger.Debug("Batch call blocked by guardrails: %s", guardrailsResult.BlockingReason)
+			return BatchResult{}, fmt.Errorf("%s", guardrailsResult.BlockingReason)
+		}
+	}
+
+	result := BatchResult{
+		Tool:     req.Tool,
+		Resource: req.Resource,
+		Items:    make([]BatchItemResult, len(req.Items)),
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+
+	for i, item := range req.Items {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(index int, item map[string]interface{}) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			// Copy the item so the caller's arguments are not modified during invocation
+			args := make(map[string]interface{}, len(item)+1)
+			for k, v := range item {
+				args[k] = v
+			}
+			args["resource"] = req.Resource
+
+			resp := s.invokeTool(InvokeRequest{Tool: req.Tool, Arguments: args, SessionID: req.SessionID, ClientToken: req.ClientToken, Context: req.Context}, true)
+			result.Items[index] = BatchItemResult{
+				Index:    index,
+				Result:   resp.Result,
+				Error:    resp.Error,
+				Warnings: resp.Warnings,
+				ETag:     resp.ETag,
+			}
+		}(i, item)
+	}
+	wg.Wait()
+
+	for _, item := range result.Items {
+		if item.Error != "" {
+			result.Failed++
+		} else {
+			result.Succeeded++
+		}
+	}
+
+	logger.Debug("Batch %s %s finished: %d succeeded, %d failed", req.Tool, req.Resource, result.Succeeded, result.Failed)
+	return result, nil
+}
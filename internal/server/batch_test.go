@@ -0,0 +1,96 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestInvokeBatchPartialFailures(t *testing.T) {
+	var mu sync.Mutex
+	deleted := map[string]int{}
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if strings.HasPrefix(name, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code":404,"message":"topic not found"}`))
+			return
+		}
+		mu.Lock()
+		deleted[name]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer apiServer.Close()
+
+	server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+
+	t.Run("Failed items are reported without stopping the batch", func(t *testing.T) {
+		for _, concurrency := range []int{1, 3} {
+			result, err := server.InvokeBatch(BatchRequest{
+				Tool:        tools.ActionDelete,
+				Resource:    "topics",
+				Concurrency: concurrency,
+				Items: []map[string]interface{}{
+					{"topic_name": "orders"},
+					{"topic_name": "missing-1"},
+					{"topic_name": "payments"},
+					{"topic_name": "missing-2"},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Succeeded != 2 || result.Failed != 2 {
+				t.Errorf("Concurrency %d: expected 2 succeeded and 2 failed, got %d and %d", concurrency, result.Succeeded, result.Failed)
+			}
+			for i, item := range result.Items {
+				if item.Index != i {
+					t.Errorf("Expected item %d to keep its position, got index %d", i, item.Index)
+				}
+				expectFailure := i%2 == 1
+				if expectFailure && !strings.Contains(item.Error, "404") {
+					t.Errorf("Expected item %d to fail with 404, got %q", i, item.Error)
+				}
+				if !expectFailure && item.Error != "" {
+					t.Errorf("Expected item %d to succeed, got %q", i, item.Error)
+				}
+			}
+			server.guardrails.ClearAllCooldowns()
+		}
+	})
+
+	t.Run("Identical items do not trip loop detection", func(t *testing.T) {
+		var items []map[string]interface{}
+		for i := 0; i < 6; i++ {
+			items = append(items, map[string]interface{}{"topic_name": "repeated"})
+		}
+		result, err := server.InvokeBatch(BatchRequest{Tool: tools.ActionDelete, Resource: "topics", Items: items})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Failed != 0 {
+			t.Errorf("Expected no failed items, got %d: %+v", result.Failed, result.Items)
+		}
+		if deleted["repeated"] != 6 {
+			t.Errorf("Expected 6 API calls, got %d", deleted["repeated"])
+		}
+	})
+
+	t.Run("Invalid batch requests are rejected", func(t *testing.T) {
+		invalid := []BatchRequest{
+			{Tool: BatchToolName, Resource: "topics", Items: []map[string]interface{}{{}}},
+			{Tool: "unknown", Resource: "topics", Items: []map[string]interface{}{{}}},
+			{Tool: tools.ActionDelete, Resource: "topics"},
+			{Tool: tools.ActionDelete, Items: []map[string]interface{}{{}}},
+		}
+		for _, req := range invalid {
+			if _, err := server.InvokeBatch(req); err == nil {
+				t.Errorf("Expected error for batch request %+v", req)
+			}
+		}
+	})
+}
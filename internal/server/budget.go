@@ -0,0 +1,43 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"mcolomerc/mcp-server/internal/config"
+	"time"
+)
+
+// errInvocationBudgetExceeded ends the requests of an invocation that used up its budget
+var errInvocationBudgetExceeded = errors.New("invocation budget exceeded")
+
+// invocationDeadline returns when the INVOCATION_BUDGET_SECONDS of an invocation starting now
+// end, or the zero time when invocations have no budget
+func (s *MCPServer) invocationDeadline() time.Time {
+	if s.config.InvocationBudgetSec <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(s.config.InvocationBudgetSec) * time.Second)
+}
+
+// budgetExceededError reports an invocation that ran out of budget
+func budgetExceededError(cfg *config.Config) error {
+	return fmt.Errorf("%w: %ds (INVOCATION_BUDGET_SECONDS) used up", errInvocationBudgetExceeded, cfg.InvocationBudgetSec)
+}
+
+// budgetExhausted reports whether the budget ending at deadline is used up; a zero deadline never is
+func budgetExhausted(deadline time.Time) bool {
+	return !deadline.IsZero() && !time.Now().Before(deadline)
+}
+
+// budgetedTimeout returns the timeout of the next request of an invocation: the per-request
+// timeout, shortened to what is left of the invocation budget
+func budgetedTimeout(cfg *config.Config, timeout time.Duration, deadline time.Time) (time.Duration, error) {
+	if deadline.IsZero() {
+		return timeout, nil
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return 0, budgetExceededError(cfg)
+	}
+	return min(timeout, remaining), nil
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/guardrails"
+	"sort"
+)
+
+// Capabilities describes what the server supports, derived from its configuration, so clients
+// and orchestrators can adapt their behavior
+type Capabilities struct {
+	Services   map[string]bool              `json:"services"` // Service -> endpoint and credentials configured
+	Tools      []string                     `json:"tools"`    // Generated semantic tools
+	Guardrails *guardrails.GuardrailsStatus `json:"guardrails,omitempty"`
+	Retries    RetryCapabilities            `json:"retries"`
+	Pagination PaginationCapabilities       `json:"pagination"`
+	Limits     LimitCapabilities            `json:"limits"`
+	Features   map[string]bool              `json:"features"` // Optional features -> enabled
+}
+
+// RetryCapabilities describes the retry policy of idempotent calls
+type RetryCapabilities struct {
+	Enabled    bool     `json:"enabled"`
+	MaxRetries int      `json:"max_retries"`
+	BackoffMs  int      `json:"backoff_ms"`
+	Actions    []string `json:"actions,omitempty"`
+}
+
+// PaginationCapabilities describes the list pagination modes
+type PaginationCapabilities struct {
+	ContinuationTokens      bool `json:"continuation_tokens"`
+	ContinuationTokenTTLSec int  `json:"continuation_token_ttl_seconds"`
+	AllPages                bool `json:"all_pages"`
+	StreamPages             bool `json:"stream_pages"`
+	MaxAutoPages            int  `json:"max_auto_pages"`
+}
+
+// LimitCapabilities lists the limits calls are subject to; zero means unlimited
+type LimitCapabilities struct {
+	MaxConcurrentInvocations  int `json:"max_concurrent_invocations"`
+	InvocationQueueTimeoutSec int `json:"invocation_queue_timeout_seconds"`
+	HTTPTimeoutSec            int `json:"http_timeout_seconds"`
+	BatchMaxItems             int `json:"batch_max_items"`
+	BatchMaxConcurrency       int `json:"batch_max_concurrency"`
+}
+
+// GetCapabilities summarizes the enabled services, features and limits
+func (s *MCPServer) GetCapabilities() Capabilities {
+	cfg := s.config
+
+	toolNames := make([]string, 0, len(s.tools))
+	for _, tool := range s.tools {
+		toolNames = append(toolNames, s.exposedToolName(tool.Name))
+	}
+	sort.Strings(toolNames)
+
+	maxAutoPages := cfg.MaxAutoPages
+	if maxAutoPages <= 0 {
+		maxAutoPages = DefaultMaxAutoPages
+	}
+	continuationTTL := cfg.ContinuationTokenTTLSec
+	if continuationTTL <= 0 {
+		continuationTTL = DefaultContinuationTTLSeconds
+	}
+
+	capabilities := Capabilities{
+		Services: cfg.ConfiguredServices(),
+		Tools:    toolNames,
+		Retries: RetryCapabilities{
+			Enabled:    cfg.MaxRetries > 0,
+			MaxRetries: cfg.MaxRetries,
+			BackoffMs:  cfg.RetryBackoffMs,
+		},
+		Pagination: PaginationCapabilities{
+			ContinuationTokens:      true,
+			ContinuationTokenTTLSec: continuationTTL,
+			AllPages:                true,
+			StreamPages:             true,
+			MaxAutoPages:            maxAutoPages,
+		},
+		Limits: LimitCapabilities{
+			MaxConcurrentInvocations:  cfg.MaxConcurrentInvocations,
+			InvocationQueueTimeoutSec: cfg.InvocationQueueTimeoutSec,
+			HTTPTimeoutSec:            cfg.HTTPTimeoutSec,
+			BatchMaxItems:             BatchMaxItems,
+			BatchMaxConcurrency:       BatchMaxConcurrency,
+		},
+		Features: cfg.EnabledFeatures(),
+	}
+	capabilities.Services["telemetry"] = s.telemetrySpec != nil && len(s.telemetrySpec.Paths) > 0
+
+	if capabilities.Retries.Enabled {
+		capabilities.Retries.Actions = cfg.RetryableActions
+		if len(capabilities.Retries.Actions) == 0 {
+			capabilities.Retries.Actions = DefaultRetryableActions
+		}
+	}
+	if s.guardrails != nil {
+		status := s.guardrails.Status()
+		capabilities.Guardrails = &status
+	}
+
+	return capabilities
+}
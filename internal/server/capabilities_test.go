@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCapabilitiesTool(t *testing.T) {
+	t.Setenv("LOOP_DETECTION_MAX_CONSECUTIVE", "7")
+
+	cfg := newTestConfig(t, "http://localhost")
+	cfg.FlinkAPIKey = ""
+	cfg.MaxRetries = 2
+	cfg.RetryBackoffMs = 250
+	cfg.StrictArgs = true
+	cfg.MaxConcurrentInvocations = 4
+	s := newTopicsTestServer(t, cfg)
+
+	var capabilities Capabilities
+	if err := json.Unmarshal([]byte(callTool(t, s, CapabilitiesToolName, nil)), &capabilities); err != nil {
+		t.Fatalf("Failed to decode capabilities: %v", err)
+	}
+
+	if !capabilities.Services["kafka"] || !capabilities.Services["schema_registry"] {
+		t.Errorf("Expected configured services to be enabled, got %v", capabilities.Services)
+	}
+	if capabilities.Services["flink"] || capabilities.Services["tableflow"] {
+		t.Errorf("Expected services without credentials to be disabled, got %v", capabilities.Services)
+	}
+
+	if capabilities.Guardrails == nil || !capabilities.Guardrails.LoopDetection || capabilities.Guardrails.MaxConsecutiveCalls != 7 {
+		t.Errorf("Expected loop detection with 7 consecutive calls, got %+v", capabilities.Guardrails)
+	}
+	if capabilities.Guardrails != nil && capabilities.Guardrails.LLMDetection {
+		t.Error("Expected LLM detection to be off")
+	}
+
+	if !capabilities.Retries.Enabled || capabilities.Retries.MaxRetries != 2 || capabilities.Retries.BackoffMs != 250 {
+		t.Errorf("Expected retries from config, got %+v", capabilities.Retries)
+	}
+	if !reflect.DeepEqual(capabilities.Retries.Actions, DefaultRetryableActions) {
+		t.Errorf("Expected default retryable actions, got %v", capabilities.Retries.Actions)
+	}
+
+	if !capabilities.Features["strict_args"] || capabilities.Features["result_chaining"] {
+		t.Errorf("Expected feature flags from config, got %v", capabilities.Features)
+	}
+	if capabilities.Limits.MaxConcurrentInvocations != 4 || capabilities.Limits.BatchMaxItems != BatchMaxItems {
+		t.Errorf("Unexpected limits: %+v", capabilities.Limits)
+	}
+	if capabilities.Pagination.MaxAutoPages != DefaultMaxAutoPages {
+		t.Errorf("Expected the default page cap, got %d", capabilities.Pagination.MaxAutoPages)
+	}
+	if len(capabilities.Tools) == 0 {
+		t.Error("Expected the generated tools to be listed")
+	}
+}
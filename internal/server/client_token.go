@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// clientTokenKey is the context key the MCP client's bearer token is stored under
+type clientTokenKey struct{}
+
+// withClientToken returns a context carrying the MCP client's own API token
+func withClientToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, clientTokenKey{}, token)
+}
+
+// clientTokenFromContext returns the MCP client's API token, or "" when none was sent
+func clientTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(clientTokenKey{}).(string)
+	return token
+}
+
+// clientTokenHTTPContext copies the bearer token of an incoming HTTP request into the context the
+// tool handlers run with. It only takes effect when USE_CLIENT_TOKEN is set.
+func clientTokenHTTPContext(ctx context.Context, r *http.Request) context.Context {
+	authorization := r.Header.Get(HeaderAuth)
+	if len(authorization) <= len(AuthBearerPrefix) || !strings.EqualFold(authorization[:len(AuthBearerPrefix)], AuthBearerPrefix) {
+		return ctx
+	}
+	return withClientToken(ctx, strings.TrimSpace(authorization[len(AuthBearerPrefix):]))
+}
+
+// ClientTokenRefresher returns a new token for an MCP client whose token the API rejected with a
+// 401, e.g. through an OAuth refresh, or "" when it cannot refresh it
+type ClientTokenRefresher func(ctx context.Context, token string) (string, error)
+
+// SetClientTokenRefresher makes calls made with a client token (USE_CLIENT_TOKEN) refresh a
+// rejected token and repeat the call once. It must be called before Start.
+func (s *MCPServer) SetClientTokenRefresher(refresher ClientTokenRefresher) {
+	s.refreshToken = refresher
+}
+
+// clientTokenRefresh returns the RefreshBearerToken option of calls made with a client token, or
+// nil without a token or refresher
+func (s *MCPServer) clientTokenRefresh(clientToken string) func(ctx context.Context) (string, error) {
+	if clientToken == "" || s.refreshToken == nil {
+		return nil
+	}
+	return func(ctx context.Context) (string, error) {
+		return s.refreshToken(ctx, clientToken)
+	}
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestClientTokenHTTPContext(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		expectToken   string
+	}{
+		{name: "Bearer token", authorization: "Bearer user-token", expectToken: "user-token"},
+		{name: "Case-insensitive scheme", authorization: "bearer user-token", expectToken: "user-token"},
+		{name: "Basic credentials are ignored", authorization: "Basic dXNlcjpwYXNz", expectToken: ""},
+		{name: "No header", authorization: "", expectToken: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.authorization != "" {
+				r.Header.Set(HeaderAuth, tt.authorization)
+			}
+			if token := clientTokenFromContext(clientTokenHTTPContext(context.Background(), r)); token != tt.expectToken {
+				t.Errorf("Expected token %q, got %q", tt.expectToken, token)
+			}
+		})
+	}
+}
+
+func TestToolHandlerUsesClientToken(t *testing.T) {
+	var receivedAuth string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedAuth = r.Header.Get(HeaderAuth)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	tests := []struct {
+		name           string
+		useClientToken bool
+		token          string
+		expectPrefix   string
+	}{
+		{name: "Client token replaces configured credentials", useClientToken: true, token: "user-token", expectPrefix: "Bearer user-token"},
+		{name: "Configured credentials without a client token", useClientToken: true, expectPrefix: AuthBasicPrefix},
+		{name: "Client token ignored unless USE_CLIENT_TOKEN is set", useClientToken: false, token: "user-token", expectPrefix: AuthBasicPrefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(t, apiServer.URL)
+			cfg.UseClientToken = tt.useClientToken
+			server := newTopicsTestServer(t, cfg)
+
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = withClientToken(ctx, tt.token)
+			}
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]interface{}{"resource": "topics", "topic_name": "orders"}
+
+			if _, err := server.createToolHandler(tools.ActionGet)(ctx, request); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(receivedAuth, tt.expectPrefix) {
+				t.Errorf("Expected Authorization %q..., got %q", tt.expectPrefix, receivedAuth)
+			}
+		})
+	}
+}
+
+func TestExecuteAPICallRefreshesBearerTokenOn401(t *testing.T) {
+	var receivedAuth []string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedAuth = append(receivedAuth, r.Header.Get(HeaderAuth))
+		if r.Header.Get(HeaderAuth) != AuthBearerPrefix+"fresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error_code":401,"message":"token expired"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	path := "/kafka/v3/clusters/lkc-1/topics/orders"
+
+	t.Run("Expired token is refreshed once and the call repeated", func(t *testing.T) {
+		receivedAuth = nil
+		refreshes := 0
+		opts := APICallOptions{
+			BearerToken: "expired-token",
+			RefreshBearerToken: func(ctx context.Context) (string, error) {
+				refreshes++
+				return "fresh-token", nil
+			},
+		}
+		result, err := ExecuteAPICallWithOptions(cfg, nil, "GET", path, nil, nil, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result["topic_name"] != "orders" {
+			t.Errorf("Expected the retried result, got %v", result)
+		}
+		if refreshes != 1 || len(receivedAuth) != 2 || receivedAuth[1] != AuthBearerPrefix+"fresh-token" {
+			t.Errorf("Expected one refresh and a retry with the fresh token, got %d refreshes and %v", refreshes, receivedAuth)
+		}
+	})
+
+	t.Run("Rejected refreshed token asks the client to re-authenticate", func(t *testing.T) {
+		receivedAuth = nil
+		opts := APICallOptions{
+			BearerToken:        "expired-token",
+			RefreshBearerToken: func(ctx context.Context) (string, error) { return "still-expired", nil },
+		}
+		_, err := ExecuteAPICallWithOptions(cfg, nil, "GET", path, nil, nil, opts)
+		if err == nil || !strings.Contains(err.Error(), "re-authenticate the MCP client") {
+			t.Errorf("Expected re-authentication guidance, got %v", err)
+		}
+		if len(receivedAuth) != 2 {
+			t.Errorf("Expected a single retry, got %d requests", len(receivedAuth))
+		}
+	})
+}
+
+func TestToolHandlerRefreshesClientToken(t *testing.T) {
+	var receivedAuth []string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedAuth = append(receivedAuth, r.Header.Get(HeaderAuth))
+		if r.Header.Get(HeaderAuth) != AuthBearerPrefix+"fresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error_code":401,"message":"token expired"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	cfg.UseClientToken = true
+	server := newTopicsTestServer(t, cfg)
+	var refreshed string
+	server.SetClientTokenRefresher(func(ctx context.Context, token string) (string, error) {
+		refreshed = token
+		return "fresh-token", nil
+	})
+
+	resp := server.InvokeTool(InvokeRequest{
+		Tool:        tools.ActionGet,
+		Arguments:   map[string]interface{}{"resource": "topics", "topic_name": "orders"},
+		ClientToken: "expired-token",
+	})
+	if resp.Error != "" {
+		t.Fatalf("Unexpected error: %s", resp.Error)
+	}
+	if refreshed != "expired-token" || len(receivedAuth) != 2 || receivedAuth[1] != AuthBearerPrefix+"fresh-token" {
+		t.Errorf("Expected the rejected client token to be refreshed and the call repeated, got refresh of %q and %v", refreshed, receivedAuth)
+	}
+}
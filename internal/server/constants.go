@@ -68,6 +68,61 @@ const (
 	// Configuration parameters - used in request body transformation
 	ParamConfigs = "configs" // Array of configuration objects
 	ParamConfig  = "config"  // Single configuration object
+
+	// Per-call base URL override - consumed by the server, never sent to the API
+	ParamBaseURLOverride = "base_url_override"
+
+	// Per-call output formatting - consumed by the tool handler
+	ParamPretty     = "pretty"
+	ParamUnwrapData = "unwrap_data"
+
+	// Optimistic concurrency - sent as the If-Match header on updates
+	ParamIfMatch = "if_match"
+	ParamETag    = "etag"
+
+	// Idempotency key - sent as the Idempotency-Key header, which also makes creates retryable
+	ParamIdempotencyKey = "idempotency_key"
+
+	// Credential profile selection - consumed by the server, never sent to the API
+	ParamProfile = "profile"
+
+	// Explicit JSON patch operations for PATCH endpoints taking application/json-patch+json
+	ParamPatchOperations = "operations"
+
+	// Page-at-a-time and all-pages listing - consumed by the server on list calls
+	ParamPaginate          = "paginate"
+	ParamContinuationToken = "continuation_token"
+	ParamAllPages          = "all_pages"
+	ParamStreamPages       = "stream_pages"
+
+	// Query parameter carrying the telemetry next_page_token
+	ParamTelemetryPageToken = "page_token"
+
+	// Result field telling whether a paginated list has more pages
+	ResultFieldHasMore = "has_more"
+
+	// Field-level validation errors of a rejected call, sent next to the error message
+	ResultFieldFieldErrors = "field_errors"
+
+	// Result fields of an all_pages list that stopped at a failing page
+	ResultFieldPartial = "partial"
+	ResultFieldError   = "error"
+
+	// Result fields of an all_pages list whose pages were streamed to the client
+	ResultFieldStreamedPages = "streamed_pages"
+	ResultFieldStreamedItems = "streamed_items"
+
+	// Connector parameters
+	ParamName          = "name"
+	ParamConnectorName = "connector_name"
+
+	// Generic identifier argument copied to a resource's configured identifier
+	ParamID = "id"
+)
+
+// Resource Names - resources that need special request body handling
+const (
+	ResourceConnectors = "connectors"
 )
 
 // Property Types - used for schema validation and transformation
@@ -81,12 +136,85 @@ const (
 	BaseURLConfluentTelemetry = "https://api.telemetry.confluent.cloud"
 )
 
+// DefaultBaseURLOverrideHosts are the hosts a base_url_override may target when
+// BASE_URL_OVERRIDE_ALLOWED_HOSTS is not set; subdomains are allowed as well
+var DefaultBaseURLOverrideHosts = []string{"confluent.cloud"}
+
+// JSON output indentation used when pretty-printing is enabled
+const PrettyJSONIndent = "  "
+
 // HTTP Configuration
 const (
-	HTTPTimeoutSeconds = 30
-	ContentTypeJSON    = "application/json"
-	HeaderContentType  = "Content-Type"
-	HeaderAccept       = "Accept"
-	HeaderAuth         = "Authorization"
-	AuthBasicPrefix    = "Basic "
+	HTTPTimeoutSeconds       = 30 // Default per-request timeout
+	ContentTypeJSON          = "application/json"
+	HeaderContentType        = "Content-Type"
+	HeaderAccept             = "Accept"
+	HeaderAuth               = "Authorization"
+	HeaderIfMatch            = "If-Match"
+	HeaderETag               = "ETag"
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIfNoneMatch        = "If-None-Match"
+	HeaderLastModified       = "Last-Modified"
+	HeaderIfModifiedSince    = "If-Modified-Since"
+	HeaderContentDisposition = "Content-Disposition"
+	AuthBasicPrefix          = "Basic "
+	AuthBearerPrefix         = "Bearer "
+)
+
+// HTTP connection pool defaults, used when HTTP_MAX_IDLE_CONNS_PER_HOST or HTTP_IDLE_CONN_TIMEOUT are not set
+const (
+	DefaultMaxIdleConnsPerHost    = 10
+	DefaultIdleConnTimeoutSeconds = 90
+)
+
+// DefaultResponseCacheMaxEntries bounds the GET response cache when RESPONSE_CACHE_MAX_ENTRIES is not set
+const DefaultResponseCacheMaxEntries = 100
+
+// DefaultRetryableActions are the semantic actions whose requests can be repeated safely when
+// RETRYABLE_ACTIONS is not set. Creates are left out: a retried POST may create a duplicate.
+var DefaultRetryableActions = []string{"get", "list", "update", "delete"}
+
+// Batch Invocation
+const (
+	BatchToolName       = "batch"
+	BatchMaxItems       = 50 // Maximum number of items in a single batch call
+	BatchMaxConcurrency = 5  // Upper bound for the concurrency argument
+)
+
+// OperationSpecToolName is the tool that returns the raw OpenAPI operation behind a semantic tool
+const OperationSpecToolName = "get_operation_spec"
+
+// RequiredParamsToolName is the tool that lists every required argument of an action and resource
+const RequiredParamsToolName = "required_params"
+
+// CapabilitiesToolName is the tool that summarizes the enabled services, features and limits
+const CapabilitiesToolName = "capabilities"
+
+// ListResourcesToolName is the tool that returns the resources of each action and the actions of each resource
+const ListResourcesToolName = "list_resources"
+
+// TestGuardrailsToolName is the admin tool that runs the guardrails on sample input
+const TestGuardrailsToolName = "test_guardrails"
+
+// AuthDebugToolName is the admin tool that shows how calls to an endpoint are authenticated
+const AuthDebugToolName = "auth_debug"
+
+// ToolsExportFileName is the download name of the /tools/export document
+const ToolsExportFileName = "tools-export.json"
+
+// ToolGroupCustom is the /tools group of custom tools, which have no service
+const ToolGroupCustom = "custom"
+
+// Tracing span attributes of tool invocations
+const (
+	SpanAttrTool     = "mcp.tool.name"
+	SpanAttrAction   = "mcp.action"
+	SpanAttrResource = "mcp.resource"
+)
+
+// Continuation Tokens
+const (
+	DefaultContinuationTTLSeconds = 300  // Lifetime of a continuation token when CONTINUATION_TOKEN_TTL is not set
+	MaxContinuationTokens         = 1000 // Upper bound on unexpired tokens held in memory
+	DefaultMaxAutoPages           = 20   // Pages an all_pages list follows when MAX_AUTO_PAGES is not set
 )
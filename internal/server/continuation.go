@@ -0,0 +1,249 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mcolomerc/mcp-server/internal/types"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Cursor-based listing: a list call with "paginate": true returns one upstream page plus a
+// continuation_token. Passing the token back to list fetches the next page. The server keeps
+// the upstream next-page link behind the token, so clients never handle raw cursors.
+
+// continuationEntry is the upstream position a continuation token stands for
+type continuationEntry struct {
+	sessionID string
+	resource  string
+	nextPath  string                 // Upstream path of the next page
+	nextQuery map[string]interface{} // Upstream query parameters of the next page, including its cursor
+	expires   time.Time
+}
+
+// continuationStore holds short-lived continuation tokens
+type continuationStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]continuationEntry
+	now     func() time.Time
+}
+
+func newContinuationStore(ttl time.Duration) *continuationStore {
+	if ttl <= 0 {
+		ttl = DefaultContinuationTTLSeconds * time.Second
+	}
+	return &continuationStore{
+		ttl:     ttl,
+		entries: make(map[string]continuationEntry),
+		now:     time.Now,
+	}
+}
+
+// parseNextPageLink splits an upstream next-page link into its path and query parameters
+func parseNextPageLink(nextLink string) (string, map[string]interface{}, error) {
+	next, err := url.Parse(nextLink)
+	if err != nil || next.Path == "" {
+		return "", nil, fmt.Errorf("invalid next page link '%s'", nextLink)
+	}
+	query := make(map[string]interface{})
+	for key, values := range next.Query() {
+		if len(values) == 1 {
+			query[key] = values[0]
+		} else {
+			query[key] = values
+		}
+	}
+	return next.Path, query, nil
+}
+
+// put stores the next page link of a list result and returns the token that refers to it
+func (c *continuationStore) put(sessionID, resource, nextLink string) (string, error) {
+	nextPath, query, err := parseNextPageLink(nextLink)
+	if err != nil {
+		return "", err
+	}
+
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("failed to generate continuation token: %v", err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) >= MaxContinuationTokens {
+		return "", fmt.Errorf("too many open continuation tokens (max %d)", MaxContinuationTokens)
+	}
+
+	c.entries[token] = continuationEntry{
+		sessionID: sessionID,
+		resource:  resource,
+		nextPath:  nextPath,
+		nextQuery: query,
+		expires:   now.Add(c.ttl),
+	}
+	return token, nil
+}
+
+// take returns and removes the entry for a token; tokens are single use
+func (c *continuationStore) take(sessionID, resource, token string) (continuationEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[token]
+	if !exists || entry.sessionID != sessionID {
+		return continuationEntry{}, fmt.Errorf("unknown or already used continuation token")
+	}
+	delete(c.entries, token)
+
+	if c.now().After(entry.expires) {
+		return continuationEntry{}, fmt.Errorf("continuation token expired; list again to start over")
+	}
+	if entry.resource != resource {
+		return continuationEntry{}, fmt.Errorf("continuation token belongs to a listing of '%s', not '%s'", entry.resource, resource)
+	}
+	return entry, nil
+}
+
+// nextPageLink returns the upstream link to the next page of a list result, if there is one
+func nextPageLink(result map[string]interface{}) string {
+	metadata, ok := result["metadata"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	next, _ := metadata["next"].(string)
+	return next
+}
+
+// addContinuationToken replaces the upstream next-page link of a list result with a
+// continuation token, and sets has_more
+func (s *MCPServer) addContinuationToken(sessionID, resource string, result map[string]interface{}) error {
+	next := nextPageLink(result)
+	result[ResultFieldHasMore] = next != ""
+	if next == "" {
+		return nil
+	}
+
+	token, err := s.continuations.put(sessionID, resource, next)
+	if err != nil {
+		return err
+	}
+	result[ParamContinuationToken] = token
+	return nil
+}
+
+// listNextPage fetches the page a continuation token refers to
+func (s *MCPServer) listNextPage(req InvokeRequest, resource, token string, opts APICallOptions) InvokeResponse {
+	entry, err := s.continuations.take(req.SessionID, resource, token)
+	if err != nil {
+		return InvokeResponse{Error: err.Error()}
+	}
+
+	result, err := ExecuteAPICallWithOptions(s.config, s.spec, "GET", entry.nextPath, entry.nextQuery, nil, opts)
+	if err != nil {
+		return InvokeResponse{Error: err.Error()}
+	}
+	if err := s.addContinuationToken(req.SessionID, resource, result); err != nil {
+		return InvokeResponse{Result: result, Warnings: []string{err.Error()}}
+	}
+	return InvokeResponse{Result: result}
+}
+
+// collectAllPages follows the next-page links of a list result, up to MAX_AUTO_PAGES pages, and
+// appends the data of each page to the result. When a page fails, the pages fetched so far are
+// kept and the result is marked partial with the error. Returns warnings for the caller.
+//
+// With a page writer the data of each page, the first included, goes to the writer as soon as it
+// is fetched instead of being buffered; the result then only counts the streamed pages and items.
+func (s *MCPServer) collectAllPages(result map[string]interface{}, opts APICallOptions, writer types.PageWriter) []string {
+	maxPages := s.config.MaxAutoPages
+	if maxPages <= 0 {
+		maxPages = DefaultMaxAutoPages
+	}
+
+	data, _ := result["data"].([]interface{})
+	pages := 1
+	items := len(data)
+	next := nextPageLink(result)
+	var warnings []string
+	if writer != nil {
+		if err := writer(pages, data); err != nil {
+			return s.stopStreaming(result, 0, 0, err)
+		}
+		data = []interface{}{}
+	}
+	for next != "" {
+		if pages >= maxPages {
+			warnings = append(warnings, fmt.Sprintf("Stopped after %d pages (MAX_AUTO_PAGES); metadata.next links the remaining pages", pages))
+			break
+		}
+
+		if budgetExhausted(opts.Deadline) {
+			err := budgetExceededError(s.config)
+			result[ResultFieldPartial] = true
+			result[ResultFieldError] = err.Error()
+			warnings = append(warnings, fmt.Sprintf("Returning %d page(s); %v, metadata.next links the remaining pages", pages, err))
+			break
+		}
+
+		page, err := s.fetchNextPage(next, opts)
+		if err != nil {
+			result[ResultFieldPartial] = true
+			result[ResultFieldError] = err.Error()
+			warnings = append(warnings, fmt.Sprintf("Returning %d page(s); page %d failed: %v", pages, pages+1, err))
+			break
+		}
+		pageData, _ := page["data"].([]interface{})
+		if writer != nil {
+			if err := writer(pages+1, pageData); err != nil {
+				warnings = append(warnings, s.stopStreaming(result, pages, items, err)...)
+				break
+			}
+		} else {
+			data = append(data, pageData...)
+		}
+		items += len(pageData)
+		next = nextPageLink(page)
+		pages++
+	}
+
+	result["data"] = data
+	if writer != nil {
+		result[ResultFieldStreamedPages] = pages
+		result[ResultFieldStreamedItems] = items
+	}
+	if metadata, ok := result["metadata"].(map[string]interface{}); ok {
+		metadata["next"] = next
+	}
+	return warnings
+}
+
+// stopStreaming marks a streamed list partial after its page writer failed, e.g. because the
+// client went away, and counts the pages and items it did receive
+func (s *MCPServer) stopStreaming(result map[string]interface{}, pages, items int, err error) []string {
+	result["data"] = []interface{}{}
+	result[ResultFieldStreamedPages] = pages
+	result[ResultFieldStreamedItems] = items
+	result[ResultFieldPartial] = true
+	result[ResultFieldError] = err.Error()
+	return []string{fmt.Sprintf("Streamed %d page(s); page %d could not be sent: %v", pages, pages+1, err)}
+}
+
+// fetchNextPage fetches the page an upstream next-page link refers to
+func (s *MCPServer) fetchNextPage(nextLink string, opts APICallOptions) (map[string]interface{}, error) {
+	nextPath, query, err := parseNextPageLink(nextLink)
+	if err != nil {
+		return nil, err
+	}
+	return ExecuteAPICallWithOptions(s.config, s.spec, "GET", nextPath, query, nil, opts)
+}
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"fmt"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListContinuationTokens(t *testing.T) {
+	var apiServer *httptest.Server
+	apiServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page_token") {
+		case "":
+			fmt.Fprintf(w, `{"data":[{"topic_name":"a"}],"metadata":{"next":"%s/kafka/v3/clusters/lkc-test/topics?page_token=p2"}}`, apiServer.URL)
+		case "p2":
+			w.Write([]byte(`{"data":[{"topic_name":"b"}],"metadata":{"next":""}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer apiServer.Close()
+
+	server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+	list := func(args map[string]interface{}) InvokeResponse {
+		args["resource"] = "topics"
+		return server.InvokeTool(InvokeRequest{Tool: tools.ActionList, Arguments: args, SessionID: "session-1"})
+	}
+
+	first := list(map[string]interface{}{"paginate": true})
+	if first.Error != "" {
+		t.Fatalf("Unexpected error: %s", first.Error)
+	}
+	firstResult := first.Result.(map[string]interface{})
+	token, _ := firstResult[ParamContinuationToken].(string)
+	if token == "" || firstResult[ResultFieldHasMore] != true {
+		t.Fatalf("Expected a continuation token and has_more, got %v", firstResult)
+	}
+
+	second := list(map[string]interface{}{"continuation_token": token})
+	if second.Error != "" {
+		t.Fatalf("Unexpected error: %s", second.Error)
+	}
+	secondResult := second.Result.(map[string]interface{})
+	data, _ := secondResult["data"].([]interface{})
+	if len(data) != 1 || data[0].(map[string]interface{})["topic_name"] != "b" {
+		t.Errorf("Expected the second page, got %v", secondResult)
+	}
+	if _, exists := secondResult[ParamContinuationToken]; exists || secondResult[ResultFieldHasMore] != false {
+		t.Errorf("Expected the last page to have no token and has_more=false, got %v", secondResult)
+	}
+
+	t.Run("Tokens are single use", func(t *testing.T) {
+		resp := list(map[string]interface{}{"continuation_token": token})
+		if !strings.Contains(resp.Error, "unknown or already used") {
+			t.Errorf("Expected a used-token error, got %q", resp.Error)
+		}
+	})
+
+	t.Run("Tokens are bound to the session", func(t *testing.T) {
+		first := list(map[string]interface{}{"paginate": true})
+		token := first.Result.(map[string]interface{})[ParamContinuationToken].(string)
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionList, SessionID: "session-2", Arguments: map[string]interface{}{
+			"resource":           "topics",
+			"continuation_token": token,
+		}})
+		if resp.Error == "" {
+			t.Error("Expected another session's token to be rejected")
+		}
+	})
+
+	t.Run("Lists without paginate are unchanged", func(t *testing.T) {
+		resp := list(map[string]interface{}{})
+		if _, exists := resp.Result.(map[string]interface{})[ParamContinuationToken]; exists {
+			t.Error("Expected no continuation token without paginate")
+		}
+	})
+}
+
+func TestContinuationStoreExpiry(t *testing.T) {
+	store := newContinuationStore(time.Minute)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	token, err := store.put("session-1", "topics", "https://example.confluent.cloud/kafka/v3/clusters/lkc-1/topics?page_token=p2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := store.take("session-1", "topics", token); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("Expected an expiry error, got %v", err)
+	}
+}
+
+func TestListAllPages(t *testing.T) {
+	var apiServer *httptest.Server
+	apiServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page_token") {
+		case "":
+			fmt.Fprintf(w, `{"data":[{"topic_name":"a"}],"metadata":{"next":"%s/kafka/v3/clusters/lkc-test/topics?page_token=p2"}}`, apiServer.URL)
+		case "p2":
+			fmt.Fprintf(w, `{"data":[{"topic_name":"b"}],"metadata":{"next":"%s/kafka/v3/clusters/lkc-test/topics?page_token=p3"}}`, apiServer.URL)
+		case "p3":
+			w.Write([]byte(`{"data":[{"topic_name":"c"}],"metadata":{"next":""}}`))
+		case "broken":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error_code":400,"message":"bad page token"}`))
+		}
+	}))
+	defer apiServer.Close()
+
+	topicNames := func(result map[string]interface{}) []string {
+		var names []string
+		data, _ := result["data"].([]interface{})
+		for _, item := range data {
+			names = append(names, item.(map[string]interface{})["topic_name"].(string))
+		}
+		return names
+	}
+
+	t.Run("All pages are collected", func(t *testing.T) {
+		server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionList, Arguments: map[string]interface{}{"resource": "topics", "all_pages": true}})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		result := resp.Result.(map[string]interface{})
+		if names := strings.Join(topicNames(result), ","); names != "a,b,c" {
+			t.Errorf("Expected topics a,b,c, got %s", names)
+		}
+		if _, exists := result[ResultFieldPartial]; exists {
+			t.Errorf("Expected a complete result, got %v", result)
+		}
+	})
+
+	t.Run("A failing page returns the pages fetched so far", func(t *testing.T) {
+		var brokenServer *httptest.Server
+		brokenServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page_token") == "" {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"data":[{"topic_name":"a"}],"metadata":{"next":"%s/kafka/v3/clusters/lkc-test/topics?page_token=broken"}}`, brokenServer.URL)
+				return
+			}
+			apiServer.Config.Handler.ServeHTTP(w, r)
+		}))
+		defer brokenServer.Close()
+
+		server := newTopicsTestServer(t, newTestConfig(t, brokenServer.URL))
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionList, Arguments: map[string]interface{}{"resource": "topics", "all_pages": true}})
+		if resp.Error != "" {
+			t.Fatalf("Expected partial data rather than an error, got %s", resp.Error)
+		}
+		result := resp.Result.(map[string]interface{})
+		if names := strings.Join(topicNames(result), ","); names != "a" {
+			t.Errorf("Expected the first page, got %s", names)
+		}
+		if result[ResultFieldPartial] != true {
+			t.Errorf("Expected partial=true, got %v", result[ResultFieldPartial])
+		}
+		if errText, _ := result[ResultFieldError].(string); !strings.Contains(errText, "bad page token") {
+			t.Errorf("Expected the page error in the result, got %q", errText)
+		}
+		if len(resp.Warnings) == 0 {
+			t.Error("Expected a warning about the partial result")
+		}
+	})
+
+	t.Run("Page cap stops early", func(t *testing.T) {
+		cfg := newTestConfig(t, apiServer.URL)
+		cfg.MaxAutoPages = 2
+		server := newTopicsTestServer(t, cfg)
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionList, Arguments: map[string]interface{}{"resource": "topics", "all_pages": true}})
+		result := resp.Result.(map[string]interface{})
+		if names := strings.Join(topicNames(result), ","); names != "a,b" {
+			t.Errorf("Expected topics a,b, got %s", names)
+		}
+		next, _ := result["metadata"].(map[string]interface{})["next"].(string)
+		if !strings.Contains(next, "page_token=p3") || len(resp.Warnings) == 0 {
+			t.Errorf("Expected the remaining pages to be linked with a warning, got next=%q warnings=%v", next, resp.Warnings)
+		}
+	})
+}
+
+func TestListAllPagesInvocationBudget(t *testing.T) {
+	var apiServer *httptest.Server
+	pages := 0
+	apiServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		time.Sleep(400 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":[{"topic_name":"t%d"}],"metadata":{"next":"%s/kafka/v3/clusters/lkc-test/topics?page_token=p%d"}}`, pages, apiServer.URL, pages+1)
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	cfg.InvocationBudgetSec = 1
+	server := newTopicsTestServer(t, cfg)
+
+	start := time.Now()
+	resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionList, Arguments: map[string]interface{}{"resource": "topics", "all_pages": true}})
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the budget to stop pagination after about 1s, took %v", elapsed)
+	}
+	if resp.Error != "" {
+		t.Fatalf("Expected the pages fetched within the budget rather than an error, got %s", resp.Error)
+	}
+
+	result := resp.Result.(map[string]interface{})
+	data, _ := result["data"].([]interface{})
+	if len(data) == 0 || len(data) >= DefaultMaxAutoPages {
+		t.Errorf("Expected some but not all pages within the budget, got %d", len(data))
+	}
+	if result[ResultFieldPartial] != true {
+		t.Errorf("Expected partial=true, got %v", result[ResultFieldPartial])
+	}
+	if errText, _ := result[ResultFieldError].(string); !strings.Contains(errText, "INVOCATION_BUDGET_SECONDS") {
+		t.Errorf("Expected the budget error in the result, got %q", errText)
+	}
+	if next, _ := result["metadata"].(map[string]interface{})["next"].(string); next == "" {
+		t.Error("Expected metadata.next to link the remaining pages")
+	}
+}
@@ -0,0 +1,22 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/monitoring"
+	"mcolomerc/mcp-server/internal/tools"
+)
+
+// coverageMetrics reports how much of the spec the semantic registry and generated tools cover
+func (s *MCPServer) coverageMetrics() monitoring.CoverageMetrics {
+	coverage := tools.GetRegistryCoverage()
+	metrics := monitoring.CoverageMetrics{
+		SpecPaths:          coverage.SpecPaths,
+		MappedPaths:        coverage.MappedPaths,
+		Tools:              len(s.tools),
+		ResourcesPerAction: make(map[string]int),
+		SkippedPaths:       coverage.SkippedPaths,
+	}
+	for _, action := range tools.GetSupportedActions() {
+		metrics.ResourcesPerAction[action] = len(tools.GetSupportedResources(action))
+	}
+	return metrics
+}
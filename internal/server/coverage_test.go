@@ -0,0 +1,70 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/monitoring"
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCoverageMetrics(t *testing.T) {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+	spec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Get: &openapi.Operation{Summary: "List topics"},
+			},
+			"/kafka/v3/clusters/{cluster_id}/topics/{topic_name}": {
+				Get:    &openapi.Operation{Summary: "Get topic"},
+				Delete: &openapi.Operation{Summary: "Delete topic"},
+			},
+			"/v1/health": {
+				Get: &openapi.Operation{Summary: "Health check"},
+			},
+		},
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+	server := NewCompositeServer(newTestConfig(t, "http://localhost"), spec, &openapi.OpenAPISpec{}, semanticTools)
+	monitor := monitoring.NewMonitor(time.Minute)
+	server.SetMonitor(monitor)
+
+	coverage := monitor.GetCurrentMetrics().Coverage
+	if coverage == nil {
+		t.Fatalf("Expected coverage metrics once a monitor is set")
+	}
+	if coverage.SpecPaths != 3 || coverage.MappedPaths != 2 {
+		t.Errorf("Expected 2 of 3 spec paths mapped, got %d of %d", coverage.MappedPaths, coverage.SpecPaths)
+	}
+	if coverage.Tools != len(semanticTools) {
+		t.Errorf("Expected %d tools, got %d", len(semanticTools), coverage.Tools)
+	}
+	wantResources := map[string]int{tools.ActionList: 1, tools.ActionGet: 1, tools.ActionDelete: 1}
+	if !reflect.DeepEqual(coverage.ResourcesPerAction, wantResources) {
+		t.Errorf("Expected resources per action %v, got %v", wantResources, coverage.ResourcesPerAction)
+	}
+	if reason := coverage.SkippedPaths["/v1/health"]; reason != tools.SkipReasonNoResource {
+		t.Errorf("Expected /v1/health skipped as %s, got %v", tools.SkipReasonNoResource, coverage.SkippedPaths)
+	}
+
+	recorder := httptest.NewRecorder()
+	monitoring.NewHTTPHandler(monitor).PrometheusHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/prometheus", nil))
+	for _, line := range []string{
+		"mcp_registry_spec_paths 3",
+		"mcp_registry_mapped_paths 2",
+		`mcp_registry_action_resources{action="list"} 1`,
+		`mcp_registry_skipped_paths{reason="no_resource"} 1`,
+	} {
+		if !strings.Contains(recorder.Body.String(), line+"\n") {
+			t.Errorf("Expected Prometheus output to contain %q, got:\n%s", line, recorder.Body.String())
+		}
+	}
+}
@@ -1,7 +1,14 @@
 package server
 
 import (
+	"encoding/base64"
 	"mcolomerc/mcp-server/internal/config"
+	"mcolomerc/mcp-server/internal/openapi"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -232,7 +239,7 @@ func TestGetBaseURL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getBaseURL(cfg, tt.path)
+			result := getBaseURL(cfg, tt.path, nil)
 			if result != tt.expectedURL {
 				t.Errorf("Expected URL %q, got %q", tt.expectedURL, result)
 			}
@@ -240,6 +247,50 @@ func TestGetBaseURL(t *testing.T) {
 	}
 }
 
+func TestGetBaseURLKafkaClusters(t *testing.T) {
+	cfg := &config.Config{
+		KafkaRestEndpoint: "https://kafka.test.com",
+		KafkaClusters: map[string]string{
+			"lkc-east": "https://east.kafka.test.com",
+			"lkc-west": "https://west.kafka.test.com",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		parameters  map[string]interface{}
+		expectedURL string
+	}{
+		{"Listed cluster uses its endpoint", map[string]interface{}{"cluster_id": "lkc-east"}, "https://east.kafka.test.com"},
+		{"Other listed cluster uses its endpoint", map[string]interface{}{"cluster_id": "lkc-west"}, "https://west.kafka.test.com"},
+		{"kafka_cluster_id selects the cluster too", map[string]interface{}{"kafka_cluster_id": "lkc-west"}, "https://west.kafka.test.com"},
+		{"Unlisted cluster falls back to KAFKA_REST_ENDPOINT", map[string]interface{}{"cluster_id": "lkc-other"}, "https://kafka.test.com"},
+		{"No cluster argument falls back to KAFKA_REST_ENDPOINT", nil, "https://kafka.test.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := getBaseURL(cfg, "/kafka/v3/clusters/lkc-x/topics", tt.parameters); result != tt.expectedURL {
+				t.Errorf("Expected URL %q, got %q", tt.expectedURL, result)
+			}
+		})
+	}
+}
+
+func TestGetBaseURLKafkaClusterFromPath(t *testing.T) {
+	cfg := &config.Config{
+		KafkaRestEndpoint: "https://kafka.test.com",
+		KafkaClusters:     map[string]string{"lkc-east": "https://east.kafka.test.com"},
+	}
+
+	if result := getBaseURL(cfg, "/kafka/v3/clusters/lkc-east/topics", nil); result != "https://east.kafka.test.com" {
+		t.Errorf("Expected the cluster in the path to select its endpoint, got %q", result)
+	}
+	if result := getBaseURL(cfg, "/kafka/v3/clusters/lkc-east/topics", map[string]interface{}{"cluster_id": "lkc-other"}); result != "https://kafka.test.com" {
+		t.Errorf("Expected the cluster_id argument to win over the path, got %q", result)
+	}
+}
+
 func TestGetBaseURLCaseInsensitive(t *testing.T) {
 	cfg := &config.Config{
 		KafkaRestEndpoint: "https://kafka.test.com",
@@ -270,7 +321,7 @@ func TestGetBaseURLCaseInsensitive(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getBaseURL(cfg, tt.path)
+			result := getBaseURL(cfg, tt.path, nil)
 			if result != tt.expectedURL {
 				t.Errorf("Expected URL %q, got %q", tt.expectedURL, result)
 			}
@@ -315,7 +366,7 @@ func TestGetBaseURLEmptyEndpoints(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getBaseURL(cfg, tt.path)
+			result := getBaseURL(cfg, tt.path, nil)
 			if result != tt.expectedURL {
 				t.Errorf("Expected URL %q, got %q", tt.expectedURL, result)
 			}
@@ -576,3 +627,191 @@ func TestResolveDefaultParamParameterPriority(t *testing.T) {
 		})
 	}
 }
+
+func TestCredentialProfiles(t *testing.T) {
+	profilesFile := filepath.Join(t.TempDir(), "profiles.json")
+	profilesJSON := `{
+		"org-b": {"confluent_cloud_api_key": "org-b-cloud-key", "confluent_cloud_api_secret": "org-b-cloud-secret", "kafka_api_key": "org-b-kafka-key", "kafka_api_secret": "org-b-kafka-secret"},
+		"org-c": {"kafka_api_key": "org-c-kafka-key", "kafka_api_secret": "org-c-kafka-secret"}
+	}`
+	if err := os.WriteFile(profilesFile, []byte(profilesJSON), 0o600); err != nil {
+		t.Fatalf("Failed to write profiles file: %v", err)
+	}
+	profiles, err := config.LoadCredentialProfiles(profilesFile)
+	if err != nil {
+		t.Fatalf("Failed to load profiles: %v", err)
+	}
+
+	cfg := &config.Config{
+		ConfluentCloudAPIKey:    "cloud-key",
+		ConfluentCloudAPISecret: "cloud-secret",
+		KafkaAPIKey:             "kafka-key",
+		KafkaAPISecret:          "kafka-secret",
+		CredentialProfiles:      profiles,
+	}
+	endpoint := "/kafka/v3/clusters/lkc-abc123/topics"
+
+	tests := []struct {
+		name           string
+		profile        string
+		securityType   string
+		expectedKey    string
+		expectedSecret string
+	}{
+		{"No profile uses the environment credentials", "", SecurityTypeResourceAPIKey, "kafka-key", "kafka-secret"},
+		{"Default profile uses the environment credentials", config.DefaultProfileName, SecurityTypeResourceAPIKey, "kafka-key", "kafka-secret"},
+		{"Profile credentials replace the environment ones", "org-b", SecurityTypeResourceAPIKey, "org-b-kafka-key", "org-b-kafka-secret"},
+		{"Profile cloud credentials", "org-b", SecurityTypeCloudAPIKey, "org-b-cloud-key", "org-b-cloud-secret"},
+		{"Fields missing from a profile fall back", "org-c", SecurityTypeCloudAPIKey, "cloud-key", "cloud-secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profiled, err := cfg.WithProfile(tt.profile)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			key, secret := getAPICredentials(profiled, tt.securityType, endpoint)
+			if key != tt.expectedKey || secret != tt.expectedSecret {
+				t.Errorf("Expected %q/%q, got %q/%q", tt.expectedKey, tt.expectedSecret, key, secret)
+			}
+		})
+	}
+
+	t.Run("Unknown profile is an error", func(t *testing.T) {
+		if _, err := cfg.WithProfile("org-z"); err == nil {
+			t.Error("Expected an error for an unknown profile")
+		}
+	})
+
+	t.Run("Per-call profile argument selects the credentials sent", func(t *testing.T) {
+		var receivedAuth string
+		apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedAuth = r.Header.Get(HeaderAuth)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"topic_name":"orders"}`))
+		}))
+		defer apiServer.Close()
+
+		testCfg := newTestConfig(t, apiServer.URL)
+		testCfg.CredentialProfiles = profiles
+		server := newTopicsTestServer(t, testCfg)
+
+		for _, profile := range []string{"", "org-b"} {
+			args := map[string]interface{}{"resource": "topics", "topic_name": "orders"}
+			expectedKey := testCfg.KafkaAPIKey + ":" + testCfg.KafkaAPISecret
+			if profile != "" {
+				args["profile"] = profile
+				expectedKey = "org-b-kafka-key:org-b-kafka-secret"
+			}
+
+			resp := server.InvokeTool(InvokeRequest{Tool: "get", Arguments: args})
+			if resp.Error != "" {
+				t.Fatalf("Unexpected error: %s", resp.Error)
+			}
+			expectedAuth := AuthBasicPrefix + base64.StdEncoding.EncodeToString([]byte(expectedKey))
+			if receivedAuth != expectedAuth {
+				t.Errorf("Profile %q: expected Authorization %q, got %q", profile, expectedAuth, receivedAuth)
+			}
+		}
+	})
+}
+
+func TestEnvironmentProfiles(t *testing.T) {
+	var receivedAuth string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedAuth = r.Header.Get(HeaderAuth)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	cfg.CredentialProfiles = map[string]config.CredentialProfile{
+		"dev":  {KafkaAPIKey: "dev-kafka-key", KafkaAPISecret: "dev-kafka-secret"},
+		"prod": {KafkaAPIKey: "prod-kafka-key", KafkaAPISecret: "prod-kafka-secret"},
+	}
+	cfg.EnvironmentProfiles = map[string]string{"env-dev": "dev", "env-prod": "prod"}
+	server := newTopicsTestServer(t, cfg)
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		expectedKey string
+	}{
+		{"First environment uses its profile", map[string]interface{}{"environment_id": "env-dev"}, "dev-kafka-key:dev-kafka-secret"},
+		{"Second environment uses its profile", map[string]interface{}{"environment_id": "env-prod"}, "prod-kafka-key:prod-kafka-secret"},
+		{"Unmapped environment falls back to the global credentials", map[string]interface{}{"environment_id": "env-other"}, cfg.KafkaAPIKey + ":" + cfg.KafkaAPISecret},
+		{"No environment falls back to the global credentials", map[string]interface{}{}, cfg.KafkaAPIKey + ":" + cfg.KafkaAPISecret},
+		{"Explicit profile wins over the environment", map[string]interface{}{"environment_id": "env-dev", "profile": "prod"}, "prod-kafka-key:prod-kafka-secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]interface{}{"resource": "topics", "topic_name": "orders"}
+			for key, value := range tt.args {
+				args[key] = value
+			}
+			resp := server.InvokeTool(InvokeRequest{Tool: "get", Arguments: args})
+			if resp.Error != "" {
+				t.Fatalf("Unexpected error: %s", resp.Error)
+			}
+			expectedAuth := AuthBasicPrefix + base64.StdEncoding.EncodeToString([]byte(tt.expectedKey))
+			if receivedAuth != expectedAuth {
+				t.Errorf("Expected Authorization %q, got %q", expectedAuth, receivedAuth)
+			}
+		})
+	}
+}
+
+func TestExecuteAPICallUnauthorizedNamesService(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error_code":401,"message":"Unauthorized"}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	spec := &openapi.OpenAPISpec{
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Get: &openapi.Operation{Security: []map[string][]string{{SecurityTypeResourceAPIKey: {}}}},
+			},
+			"/subjects": {
+				Get: &openapi.Operation{Security: []map[string][]string{{SecurityTypeResourceAPIKey: {}}}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		spec   *openapi.OpenAPISpec
+		path   string
+		expect string
+	}{
+		{
+			name:   "Kafka resource key",
+			spec:   spec,
+			path:   "/kafka/v3/clusters/lkc-1/topics",
+			expect: "authentication failed (401) for Kafka: check the API key and secret (KAFKA_API_KEY and KAFKA_API_SECRET)",
+		},
+		{
+			name:   "Schema Registry resource key",
+			spec:   spec,
+			path:   "/subjects",
+			expect: "authentication failed (401) for Schema Registry: check the API key and secret (SCHEMA_REGISTRY_API_KEY and SCHEMA_REGISTRY_API_SECRET)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ExecuteAPICall(cfg, tt.spec, "GET", tt.path, nil, nil)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.expect) {
+				t.Errorf("Expected error starting with %q, got %v", tt.expect, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), "Unauthorized") {
+				t.Errorf("Expected the API response in the error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"mcolomerc/mcp-server/internal/config"
+	"mcolomerc/mcp-server/internal/logger"
+	"mcolomerc/mcp-server/internal/tools"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// CustomToolHandler handles a call to a custom tool and returns the result sent to the client
+type CustomToolHandler func(ctx context.Context, args map[string]interface{}) (interface{}, error)
+
+// builtinToolNames are the tools registered by the server itself, which custom tools cannot replace
+var builtinToolNames = []string{
+	"prompts",
+	"get_prompt",
+	"search",
+	BatchToolName,
+	OperationSpecToolName,
+	RequiredParamsToolName,
+	CapabilitiesToolName,
+	ListResourcesToolName,
+	TestGuardrailsToolName,
+	AuthDebugToolName,
+}
+
+// RegisterCustomTool registers a handcrafted tool next to the generated ones. It must be called
+// before Start. Calls go through the input guardrails before the handler runs.
+func (s *MCPServer) RegisterCustomTool(tool tools.Tool, handler CustomToolHandler) error {
+	if tool.Name == "" {
+		return fmt.Errorf("custom tool needs a name")
+	}
+	if handler == nil {
+		return fmt.Errorf("custom tool '%s' needs a handler", tool.Name)
+	}
+	if s.isToolRegistered(tool.Name) {
+		return fmt.Errorf("tool '%s' is already registered", tool.Name)
+	}
+	if s.droppedTools[tool.Name] {
+		return fmt.Errorf("tool '%s' is beyond MAX_TOOLS", tool.Name)
+	}
+
+	if s.customTools == nil {
+		s.customTools = make(map[string]tools.Tool)
+	}
+	s.customTools[tool.Name] = tool
+	s.mcpServer.AddTool(convertToMCPTool(tool), s.createCustomToolHandler(tool.Name, handler))
+	return nil
+}
+
+// isToolRegistered reports whether a generated, built-in or custom tool already uses name
+func (s *MCPServer) isToolRegistered(name string) bool {
+	for _, tool := range s.tools {
+		if tool.Name == name || s.exposedToolName(tool.Name) == name {
+			return true
+		}
+	}
+	for _, builtin := range builtinToolNames {
+		if builtin == name {
+			return true
+		}
+	}
+	_, exists := s.customTools[name]
+	return exists
+}
+
+// createCustomToolHandler wraps a CustomToolHandler into an MCP tool handler
+func (s *MCPServer) createCustomToolHandler(toolName string, handler CustomToolHandler) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			if request.Params.Arguments != nil {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						mcp.TextContent{
+							Type: "text",
+							Text: "Error: Invalid arguments format",
+						},
+					},
+				}, nil
+			}
+			args = map[string]interface{}{}
+		}
+
+		if s.guardrails != nil {
+			guardrailsResult := s.guardrails.ValidateToolInput(toolName, args)
+			if guardrailsResult.Blocked {
+				logger.Debug("Custom tool call blocked by guardrails: %s", guardrailsResult.BlockingReason)
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						mcp.TextContent{
+							Type: "text",
+							Text: "Error: " + guardrailsResult.BlockingReason,
+						},
+					},
+				}, nil
+			}
+		}
+
+		result, err := handler(ctx, args)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: " + err.Error(),
+					},
+				},
+			}, nil
+		}
+
+		resultJSON, err := marshalToolResult(result, s.config.PrettyJSON)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Failed to format result",
+					},
+				},
+			}, nil
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: string(resultJSON),
+				},
+			},
+		}, nil
+	}
+}
+
+// customToolNames returns the tool names of the CUSTOM_TOOLS definitions, in order, normalized
+// like generated tool names, and the definitions renamed because their names collided
+func customToolNames(defs []config.CustomToolDefinition) (names []string, clashes []string) {
+	names = make([]string, len(defs))
+	for i, def := range defs {
+		names[i] = def.Name
+	}
+	return tools.NormalizeToolNames(names)
+}
+
+// customToolFromDefinition builds the tool advertised under name for a CUSTOM_TOOLS definition
+func customToolFromDefinition(name string, def config.CustomToolDefinition) tools.Tool {
+	properties := def.Parameters
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+	parameters := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(def.Required) > 0 {
+		parameters["required"] = def.Required
+	}
+	description := def.Description
+	if description == "" {
+		description = fmt.Sprintf("Custom tool calling %s %s", def.Method, def.Path)
+	}
+	return tools.Tool{
+		Name:        name,
+		Description: description,
+		Endpoint:    def.Path,
+		Parameters:  parameters,
+	}
+}
+
+// endpointToolHandler calls the endpoint of a CUSTOM_TOOLS definition. Arguments override the
+// definition defaults; path parameters fill the path, and the other arguments become the request
+// body of POST, PUT and PATCH calls or query parameters otherwise. Calls authenticate, and count
+// against the invocation limits, like generated tools.
+func (s *MCPServer) endpointToolHandler(def config.CustomToolDefinition) CustomToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		if s.invocations != nil {
+			if err := s.invocations.acquire(); err != nil {
+				return nil, err
+			}
+			defer s.invocations.release()
+		}
+		deadline := s.invocationDeadline()
+
+		params := make(map[string]interface{}, len(def.Defaults)+len(args))
+		for key, value := range def.Defaults {
+			params[key] = value
+		}
+		for key, value := range args {
+			params[key] = value
+		}
+
+		// A profile argument selects the credential set; without one, the environment may
+		profile := extractConsumedArgument(params, ParamProfile)
+		if profile == "" {
+			profile = s.config.ProfileForEnvironment(environmentIDArgument(params))
+		}
+		clientToken := ""
+		if s.config.UseClientToken {
+			clientToken = clientTokenFromContext(ctx)
+		}
+
+		if missing := s.resolvePathParameters(def.Path, params); len(missing) > 0 {
+			return nil, fmt.Errorf("missing path parameters for '%s': %s", def.Name, strings.Join(missing, ", "))
+		}
+		apiPath := tools.BuildAPIPath(def.Path, params)
+
+		// Path parameters are already in the path, so they are neither sent as query nor body
+		unbound := make(map[string]interface{}, len(params))
+		for key, value := range params {
+			if !strings.Contains(def.Path, "{"+key+"}") {
+				unbound[key] = value
+			}
+		}
+
+		var requestBody interface{}
+		switch def.Method {
+		case tools.HTTPMethodPost, tools.HTTPMethodPut, tools.HTTPMethodPatch:
+			requestBody = unbound
+		}
+
+		opts := s.invocationCallOptions(ctx, clientToken, profile, deadline)
+		return ExecuteAPICallWithOptions(s.config, s.spec, def.Method, apiPath, unbound, requestBody, opts)
+	}
+}
+
+// registerConfiguredCustomTools registers the tools of the CUSTOM_TOOLS file
+func (s *MCPServer) registerConfiguredCustomTools() {
+	names, clashes := customToolNames(s.config.CustomTools)
+	if len(clashes) > 0 {
+		logger.Info("CUSTOM_TOOLS names collided after normalization and were suffixed: %s\n", strings.Join(clashes, ", "))
+	}
+	for i, def := range s.config.CustomTools {
+		if err := s.RegisterCustomTool(customToolFromDefinition(names[i], def), s.endpointToolHandler(def)); err != nil {
+			logger.Error("Skipping custom tool '%s': %v\n", names[i], err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"mcolomerc/mcp-server/internal/config"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// callTool sends a tools/call request through the MCP server and returns the first text content
+func callTool(t *testing.T, s *MCPServer, name string, args map[string]interface{}) string {
+	t.Helper()
+
+	message, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]interface{}{"name": name, "arguments": args},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+
+	response := s.mcpServer.HandleMessage(context.Background(), message)
+	rpcResponse, ok := response.(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("Unexpected response for %s: %#v", name, response)
+	}
+	result, ok := rpcResponse.Result.(mcp.CallToolResult)
+	if !ok || len(result.Content) == 0 {
+		t.Fatalf("Unexpected tool result: %#v", rpcResponse.Result)
+	}
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+func TestRegisterCustomTool(t *testing.T) {
+	s := newTopicsTestServer(t, newTestConfig(t, ""))
+
+	greet := tools.Tool{
+		Name:        "greet",
+		Description: "Greets someone",
+		Parameters: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"who": map[string]interface{}{"type": "string"}},
+		},
+	}
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{"greeting": "hello " + args["who"].(string)}, nil
+	}
+	if err := s.RegisterCustomTool(greet, handler); err != nil {
+		t.Fatalf("RegisterCustomTool returned error: %v", err)
+	}
+
+	text := callTool(t, s, "greet", map[string]interface{}{"who": "kafka"})
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("Failed to decode result %q: %v", text, err)
+	}
+	if result["greeting"] != "hello kafka" {
+		t.Errorf("Expected greeting from custom handler, got %v", result)
+	}
+
+	for _, name := range []string{"greet", tools.ActionList, BatchToolName} {
+		if err := s.RegisterCustomTool(tools.Tool{Name: name}, handler); err == nil {
+			t.Errorf("Expected registering '%s' to fail as a duplicate", name)
+		}
+	}
+}
+
+func TestConfiguredCustomTool(t *testing.T) {
+	var gotMethod, gotPath string
+	var gotBody map[string]interface{}
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	cfg.CustomTools = []config.CustomToolDefinition{{
+		Name:     "create_topic_with_defaults",
+		Method:   "POST",
+		Path:     "/kafka/v3/clusters/{cluster_id}/topics",
+		Required: []string{"topic_name"},
+		Parameters: map[string]interface{}{
+			"topic_name": map[string]interface{}{"type": "string"},
+		},
+		Defaults: map[string]interface{}{"partitions_count": 6, "replication_factor": 3},
+	}}
+	s := newTopicsTestServer(t, cfg)
+
+	text := callTool(t, s, "create_topic_with_defaults", map[string]interface{}{
+		"topic_name":       "orders",
+		"partitions_count": 12,
+	})
+
+	if gotMethod != "POST" || gotPath != "/kafka/v3/clusters/lkc-test/topics" {
+		t.Errorf("Expected POST /kafka/v3/clusters/lkc-test/topics, got %s %s", gotMethod, gotPath)
+	}
+	if gotBody["topic_name"] != "orders" || gotBody["partitions_count"] != float64(12) || gotBody["replication_factor"] != float64(3) {
+		t.Errorf("Expected arguments merged over defaults in the body, got %v", gotBody)
+	}
+	if _, exists := gotBody["cluster_id"]; exists {
+		t.Errorf("Expected path parameters to be left out of the body, got %v", gotBody)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &result); err != nil || result["topic_name"] != "orders" {
+		t.Errorf("Expected the API result, got %q", text)
+	}
+}
+
+func TestConfiguredCustomToolCallOptions(t *testing.T) {
+	var gotAuth string
+	var gotQuery url.Values
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth, gotQuery = r.Header.Get(HeaderAuth), r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer apiServer.Close()
+
+	def := config.CustomToolDefinition{
+		Name:   "list_topic_configs",
+		Method: "GET",
+		Path:   "/kafka/v3/clusters/{cluster_id}/topics/{topic_name}/configs",
+	}
+	cfg := newTestConfig(t, apiServer.URL)
+	cfg.UseClientToken = true
+	s := newTopicsTestServer(t, cfg)
+
+	ctx := withClientToken(context.Background(), "user-token")
+	_, err := s.endpointToolHandler(def)(ctx, map[string]interface{}{"topic_name": "orders", "include_synonyms": true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if gotAuth != AuthBearerPrefix+"user-token" {
+		t.Errorf("Expected the client token to authenticate the call, got %q", gotAuth)
+	}
+	if gotQuery.Has("topic_name") || gotQuery.Has("cluster_id") {
+		t.Errorf("Expected path parameters to be left out of the query, got %v", gotQuery)
+	}
+	if gotQuery.Get("include_synonyms") != "true" {
+		t.Errorf("Expected other arguments in the query, got %v", gotQuery)
+	}
+}
+
+func TestConfiguredCustomToolNameCollisions(t *testing.T) {
+	var gotPath string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer apiServer.Close()
+
+	// Two operations whose tool names normalize to list-topics
+	cfg := newTestConfig(t, apiServer.URL)
+	cfg.CustomTools = []config.CustomToolDefinition{
+		{Name: "List Topics", Method: "GET", Path: "/kafka/v3/clusters/{cluster_id}/topics"},
+		{Name: "list-topics", Method: "GET", Path: "/kafka/v3/clusters/{cluster_id}/consumer-groups"},
+	}
+	s := newTopicsTestServer(t, cfg)
+
+	for name, path := range map[string]string{
+		"list-topics":   "/kafka/v3/clusters/lkc-test/topics",
+		"list-topics-2": "/kafka/v3/clusters/lkc-test/consumer-groups",
+	} {
+		gotPath = ""
+		callTool(t, s, name, map[string]interface{}{})
+		if gotPath != path {
+			t.Errorf("Expected %s to call %s, got %q", name, path, gotPath)
+		}
+	}
+	if reserved := ReservedToolNames(cfg); !slices.Contains(reserved, "list-topics") || !slices.Contains(reserved, "list-topics-2") {
+		t.Errorf("Expected the normalized names to be reserved, got %v", reserved)
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"mcolomerc/mcp-server/internal/config"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// secretFieldSuffixes mark configuration fields whose values must never be shown
+var secretFieldSuffixes = []string{"APIKey", "APISecret", "Secret", "Token", "Password"}
+
+// isSecretField reports whether a configuration field holds a secret, judging by its name
+func isSecretField(name string) bool {
+	for _, suffix := range secretFieldSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactedFields returns the exported fields of a config struct by name, with secret strings
+// redacted. Maps of structs, such as credential profiles, are redacted per entry, and the values
+// of nested string maps, such as service headers that may carry gateway tokens, are all redacted.
+func redactedFields(value reflect.Value) map[string]interface{} {
+	fields := make(map[string]interface{}, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldValue := value.Field(i)
+
+		switch {
+		case fieldValue.Kind() == reflect.String && isSecretField(field.Name):
+			fields[field.Name] = maskCredential(fieldValue.String())
+		case fieldValue.Kind() == reflect.Map && fieldValue.Type().Elem().Kind() == reflect.Struct:
+			entries := make(map[string]interface{}, fieldValue.Len())
+			iter := fieldValue.MapRange()
+			for iter.Next() {
+				entries[iter.Key().String()] = redactedFields(iter.Value())
+			}
+			fields[field.Name] = entries
+		case fieldValue.Kind() == reflect.Map && fieldValue.Type().Elem().Kind() == reflect.Map &&
+			fieldValue.Type().Elem().Elem().Kind() == reflect.String:
+			entries := make(map[string]interface{}, fieldValue.Len())
+			iter := fieldValue.MapRange()
+			for iter.Next() {
+				values := make(map[string]string, iter.Value().Len())
+				inner := iter.Value().MapRange()
+				for inner.Next() {
+					values[inner.Key().String()] = maskCredential(inner.Value().String())
+				}
+				entries[iter.Key().String()] = values
+			}
+			fields[field.Name] = entries
+		default:
+			fields[field.Name] = fieldValue.Interface()
+		}
+	}
+	return fields
+}
+
+// redactedConfig returns the effective configuration with every secret replaced by ***
+func redactedConfig(cfg *config.Config) map[string]interface{} {
+	return redactedFields(reflect.ValueOf(*cfg))
+}
+
+// DebugConfigHandler serves the effective configuration with secrets redacted, so operators can
+// check which environment variables were picked up
+func (s *MCPServer) DebugConfigHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set(HeaderContentType, ContentTypeJSON)
+	jsonData, err := json.MarshalIndent(redactedConfig(s.config), "", PrettyJSONIndent)
+	if err != nil {
+		http.Error(w, "Failed to marshal config", http.StatusInternalServerError)
+		return
+	}
+	w.Write(jsonData)
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/json"
+	"mcolomerc/mcp-server/internal/config"
+	"mcolomerc/mcp-server/internal/openapi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugConfigHandler(t *testing.T) {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+
+	cfg := newTestConfig(t, "https://pkc-123.us-east-1.aws.confluent.cloud")
+	cfg.ConfluentCloudAPISecret = "cloud-secret-value"
+	cfg.LLMDetectionAPIKey = "llm-key-value"
+	cfg.FlinkAPIKey = ""
+	cfg.CredentialProfiles = map[string]config.CredentialProfile{
+		"staging": {ConfluentCloudAPIKey: "staging-key", ConfluentCloudAPISecret: "staging-secret"},
+	}
+	cfg.ServiceHeaders = map[string]map[string]string{
+		"kafka": {"X-Gateway-Token": "gateway-token-value"},
+	}
+	s := NewCompositeServer(cfg, &openapi.OpenAPISpec{}, &openapi.OpenAPISpec{}, nil)
+
+	recorder := httptest.NewRecorder()
+	s.DebugConfigHandler(recorder, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+
+	for _, secret := range []string{"cloud-secret-value", "test-sr-key", "llm-key-value", "staging-secret", "gateway-token-value"} {
+		if strings.Contains(recorder.Body.String(), secret) {
+			t.Errorf("Expected %q not to appear in the response", secret)
+		}
+	}
+
+	var effective map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &effective); err != nil {
+		t.Fatalf("Response is not valid JSON: %v", err)
+	}
+
+	for _, field := range []string{"ConfluentCloudAPISecret", "SchemaRegistryAPIKey", "LLMDetectionAPIKey"} {
+		if effective[field] != "***" {
+			t.Errorf("Expected %s to be redacted, got %v", field, effective[field])
+		}
+	}
+	if effective["FlinkAPIKey"] != "" {
+		t.Errorf("Expected an unset secret to stay empty, got %v", effective["FlinkAPIKey"])
+	}
+	if effective["KafkaRestEndpoint"] != "https://pkc-123.us-east-1.aws.confluent.cloud" {
+		t.Errorf("Expected non-secret values to be shown, got %v", effective["KafkaRestEndpoint"])
+	}
+
+	profiles, _ := effective["CredentialProfiles"].(map[string]interface{})
+	staging, _ := profiles["staging"].(map[string]interface{})
+	if staging["ConfluentCloudAPIKey"] != "***" || staging["ConfluentCloudAPISecret"] != "***" {
+		t.Errorf("Expected profile credentials to be redacted, got %v", profiles)
+	}
+
+	serviceHeaders, _ := effective["ServiceHeaders"].(map[string]interface{})
+	kafkaHeaders, _ := serviceHeaders["kafka"].(map[string]interface{})
+	if kafkaHeaders["X-Gateway-Token"] != "***" {
+		t.Errorf("Expected service header values to be redacted with their names shown, got %v", serviceHeaders)
+	}
+
+	recorder = httptest.NewRecorder()
+	s.DebugConfigHandler(recorder, httptest.NewRequest(http.MethodPost, "/debug/config", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 for POST, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/config"
+	"mcolomerc/mcp-server/internal/logger"
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+)
+
+// scopeDefaults maps the environment and organization parameters of cloud and telemetry
+// operations to their configured values
+func scopeDefaults(cfg *config.Config) map[string]string {
+	return map[string]string{
+		ParamEnvironment:    cfg.ConfluentEnvID,
+		ParamEnvironmentID:  cfg.ConfluentEnvID,
+		ParamOrganizationID: cfg.FlinkOrgID,
+		ParamOrgID:          cfg.FlinkOrgID,
+	}
+}
+
+// injectDefaultScope fills the environment and organization parameters a cloud or telemetry
+// operation declares, as a parameter or a top-level request body property, when the call does
+// not set them. Resource API calls are left alone: their defaults come from resolveDefaultParam.
+func injectDefaultScope(cfg *config.Config, spec *openapi.OpenAPISpec, mapping *tools.EndpointMapping, args map[string]interface{}) {
+	if !cfg.InjectDefaultScope || mapping == nil {
+		return
+	}
+	service := mapping.Service
+	if service == "" {
+		service = tools.ServiceForPath(mapping.PathPattern)
+	}
+	if service != tools.ServiceCloud && service != tools.ServiceTelemetry {
+		return
+	}
+
+	declared := make(map[string]bool)
+	if operation := spec.FindOperation(mapping.Method, mapping.PathPattern); operation != nil {
+		for _, param := range operation.Parameters {
+			declared[param.Name] = true
+		}
+	}
+	if schema, ok := mapping.RequestBodySchema["schema"].(*openapi.Schema); ok {
+		for _, name := range getSchemaPropertyNames(schema) {
+			declared[name] = true
+		}
+	}
+
+	nested, _ := args["parameters"].(map[string]interface{})
+	for name, value := range scopeDefaults(cfg) {
+		if !declared[name] || value == "" || scopeArgumentSet(args, nested, name) {
+			continue
+		}
+		args[name] = value
+		if nested != nil {
+			nested[name] = value
+		}
+		logger.Debug("Injected default %s=%s into %s %s\n", name, value, mapping.Method, mapping.PathPattern)
+	}
+}
+
+// scopeArgumentSet reports whether a call sets a parameter, at the top level or under 'parameters'
+func scopeArgumentSet(args, nested map[string]interface{}, name string) bool {
+	for _, source := range []map[string]interface{}{args, nested} {
+		if value, ok := source[name]; ok && value != nil && value != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestInvokeToolInjectsDefaultScope(t *testing.T) {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+	var gotQuery url.Values
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer apiServer.Close()
+
+	spec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/cmk/v2/clusters": {
+				Get: &openapi.Operation{
+					Summary:    "List clusters",
+					Parameters: []openapi.Parameter{{Name: "environment", In: "query"}, {Name: "page_size", In: "query"}},
+				},
+			},
+			"/iam/v2/service-accounts": {
+				Get: &openapi.Operation{Summary: "List service accounts"},
+			},
+		},
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+	list := func(inject bool, arguments map[string]interface{}) {
+		t.Helper()
+		cfg := newTestConfig(t, apiServer.URL)
+		cfg.AllowBaseURLOverride = true
+		cfg.BaseURLOverrideAllowedHosts = []string{"127.0.0.1"}
+		cfg.InjectDefaultScope = inject
+		server := NewCompositeServer(cfg, spec, &openapi.OpenAPISpec{}, semanticTools)
+
+		arguments[ParamBaseURLOverride] = apiServer.URL
+		gotQuery = nil
+		if resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionList, Arguments: arguments}); resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+	}
+
+	t.Run("Declared environment is injected", func(t *testing.T) {
+		list(true, map[string]interface{}{"resource": "clusters"})
+		if got := gotQuery.Get("environment"); got != "env-test" {
+			t.Errorf("Expected environment=env-test, got query %v", gotQuery)
+		}
+	})
+
+	t.Run("An explicit environment wins", func(t *testing.T) {
+		list(true, map[string]interface{}{"resource": "clusters", "environment": "env-other"})
+		if got := gotQuery.Get("environment"); got != "env-other" {
+			t.Errorf("Expected environment=env-other, got query %v", gotQuery)
+		}
+	})
+
+	t.Run("Undeclared environment is not injected", func(t *testing.T) {
+		list(true, map[string]interface{}{"resource": "service-accounts"})
+		if gotQuery.Has("environment") {
+			t.Errorf("Expected no environment, got query %v", gotQuery)
+		}
+	})
+
+	t.Run("Injection can be disabled", func(t *testing.T) {
+		list(false, map[string]interface{}{"resource": "clusters"})
+		if gotQuery.Has("environment") {
+			t.Errorf("Expected no environment, got query %v", gotQuery)
+		}
+	})
+}
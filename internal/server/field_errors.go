@@ -0,0 +1,95 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"mcolomerc/mcp-server/internal/types"
+	"strings"
+)
+
+// fieldErrorsError is a rejected API call together with the field-level errors of its response
+type fieldErrorsError struct {
+	err         error
+	fieldErrors []types.FieldError
+}
+
+func (e *fieldErrorsError) Error() string { return e.err.Error() }
+
+func (e *fieldErrorsError) Unwrap() error { return e.err }
+
+// withFieldErrors attaches the field-level errors of an error response body to err, if it has any
+func withFieldErrors(err error, body []byte) error {
+	fieldErrors := parseFieldErrors(body)
+	if len(fieldErrors) == 0 {
+		return err
+	}
+	return &fieldErrorsError{err: err, fieldErrors: fieldErrors}
+}
+
+// fieldErrorsFromError returns the field-level errors attached to a failed API call, if any
+func fieldErrorsFromError(err error) []types.FieldError {
+	var fe *fieldErrorsError
+	if errors.As(err, &fe) {
+		return fe.fieldErrors
+	}
+	return nil
+}
+
+// apiErrorEnvelope is the standard Confluent error response, e.g.
+// {"errors": [{"code": "invalid_input", "detail": "...", "source": {"pointer": "/spec/display_name"}}]}
+type apiErrorEnvelope struct {
+	Errors []struct {
+		Code   string `json:"code"`
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+		Source struct {
+			Pointer   string `json:"pointer"`
+			Parameter string `json:"parameter"`
+		} `json:"source"`
+	} `json:"errors"`
+}
+
+// parseFieldErrors returns the errors of an error envelope that point at a request body field or
+// a parameter. Errors without a source are not about a field and are left out.
+func parseFieldErrors(body []byte) []types.FieldError {
+	var envelope apiErrorEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil
+	}
+
+	var fieldErrors []types.FieldError
+	for _, apiErr := range envelope.Errors {
+		field := jsonPointerField(apiErr.Source.Pointer)
+		if field == "" {
+			field = apiErr.Source.Parameter
+		}
+		if field == "" {
+			continue
+		}
+		message := apiErr.Detail
+		if message == "" {
+			message = apiErr.Title
+		}
+		fieldErrors = append(fieldErrors, types.FieldError{
+			Field:   field,
+			Pointer: apiErr.Source.Pointer,
+			Code:    apiErr.Code,
+			Message: message,
+		})
+	}
+	return fieldErrors
+}
+
+// jsonPointerField converts a JSON pointer to a dotted field path: /spec/display_name becomes
+// spec.display_name and /data/0/name becomes data.0.name
+func jsonPointerField(pointer string) string {
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return ""
+	}
+	tokens := strings.Split(pointer, "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return strings.Join(tokens, ".")
+}
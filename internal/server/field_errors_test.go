@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"mcolomerc/mcp-server/internal/types"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCreateReturnsFieldErrors(t *testing.T) {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors":[
+			{"status":"400","code":"invalid_input","detail":"partitions_count must be positive","source":{"pointer":"/partitions_count"}},
+			{"status":"400","code":"invalid_input","title":"Invalid config value","source":{"pointer":"/configs/0/value"}},
+			{"status":"400","code":"invalid_input","detail":"Unknown page size","source":{"parameter":"page_size"}},
+			{"status":"400","code":"bad_request","detail":"Request could not be processed"}
+		]}`))
+	}))
+	defer apiServer.Close()
+
+	spec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Post: &openapi.Operation{Summary: "Create topic"},
+			},
+		},
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+	server := NewCompositeServer(newTestConfig(t, apiServer.URL), spec, &openapi.OpenAPISpec{}, semanticTools)
+	arguments := func() map[string]interface{} {
+		return map[string]interface{}{
+			"resource":   "topics",
+			"parameters": map[string]interface{}{"topic_name": "orders", "partitions_count": -1},
+		}
+	}
+
+	expected := []types.FieldError{
+		{Field: "partitions_count", Pointer: "/partitions_count", Code: "invalid_input", Message: "partitions_count must be positive"},
+		{Field: "configs.0.value", Pointer: "/configs/0/value", Code: "invalid_input", Message: "Invalid config value"},
+		{Field: "page_size", Code: "invalid_input", Message: "Unknown page size"},
+	}
+
+	t.Run("Invocation response carries the field errors", func(t *testing.T) {
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionCreate, Arguments: arguments()})
+		if !strings.Contains(resp.Error, "status 400") {
+			t.Fatalf("Expected the API error, got %q", resp.Error)
+		}
+		if !reflect.DeepEqual(resp.FieldErrors, expected) {
+			t.Errorf("Expected field errors %+v, got %+v", expected, resp.FieldErrors)
+		}
+	})
+
+	t.Run("Tool result lists the field errors as JSON", func(t *testing.T) {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = arguments()
+		result, err := server.createToolHandler(tools.ActionCreate)(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Content) < 2 {
+			t.Fatalf("Expected the error and the field errors, got %v", result.Content)
+		}
+		text, _ := result.Content[1].(mcp.TextContent)
+		var decoded struct {
+			FieldErrors []types.FieldError `json:"field_errors"`
+		}
+		if err := json.Unmarshal([]byte(text.Text), &decoded); err != nil {
+			t.Fatalf("Expected JSON field errors, got %q: %v", text.Text, err)
+		}
+		if !reflect.DeepEqual(decoded.FieldErrors, expected) {
+			t.Errorf("Expected field errors %+v, got %+v", expected, decoded.FieldErrors)
+		}
+	})
+
+	t.Run("Bodies without field errors attach none", func(t *testing.T) {
+		for _, body := range []string{`{"error_code":400,"message":"bad request"}`, `not json`, `{"errors":[{"detail":"no source"}]}`} {
+			if fieldErrors := parseFieldErrors([]byte(body)); fieldErrors != nil {
+				t.Errorf("Expected no field errors for %s, got %+v", body, fieldErrors)
+			}
+		}
+	})
+}
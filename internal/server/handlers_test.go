@@ -1,12 +1,15 @@
 package server
 
 import (
+	"context"
 	"mcolomerc/mcp-server/internal/config"
 	"mcolomerc/mcp-server/internal/openapi"
 	"mcolomerc/mcp-server/internal/tools"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func TestPromptsFunctionality(t *testing.T) {
@@ -122,3 +125,46 @@ It has multiple lines.`,
 		}
 	})
 }
+
+func TestPromptHandlerMessages(t *testing.T) {
+	promptsDir := t.TempDir()
+	template := `---
+messages:
+  - role: system
+    content: Answer with a topic name only.
+  - role: user
+    content: A topic for orders being placed
+  - role: assistant
+    content: sales.order.placed
+---
+A topic for payments being refunded in {cluster_id}`
+	if err := os.WriteFile(filepath.Join(promptsDir, "name-topic.txt"), []byte(template), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := newTestConfig(t, "http://test.com")
+	cfg.PromptsFolder = promptsDir
+	cfg.EnableDirectives = false
+	server := NewCompositeServer(cfg, &openapi.OpenAPISpec{}, &openapi.OpenAPISpec{}, []tools.Tool{})
+
+	request := mcp.GetPromptRequest{}
+	request.Params.Name = "name-topic"
+	request.Params.Arguments = map[string]string{"cluster_id": "lkc-override"}
+	result, err := server.createPromptHandler("name-topic")(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	roles := []mcp.Role{mcp.RoleUser, mcp.RoleUser, mcp.RoleAssistant, mcp.RoleUser}
+	if len(result.Messages) != len(roles) {
+		t.Fatalf("Expected %d messages, got %+v", len(roles), result.Messages)
+	}
+	for i, message := range result.Messages {
+		if message.Role != roles[i] {
+			t.Errorf("Message %d: expected role %s, got %s", i+1, roles[i], message.Role)
+		}
+	}
+	if text := result.Messages[3].Content.(mcp.TextContent).Text; text != "A topic for payments being refunded in lkc-override" {
+		t.Errorf("Expected the cluster_id argument to be applied, got %q", text)
+	}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/config"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// The API client is shared by all calls so TCP and TLS connections are kept alive and reused
+// instead of being set up again for every request. Timeouts are applied per request through
+// the request context, so one client serves every timeout setting.
+var (
+	apiClient      *http.Client
+	apiClientMutex sync.RWMutex
+)
+
+// newAPIHTTPClient builds an HTTP client whose transport pools connections per host
+func newAPIHTTPClient(cfg *config.Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	maxIdlePerHost := cfg.HTTPMaxIdleConnsPerHost
+	if maxIdlePerHost <= 0 {
+		maxIdlePerHost = DefaultMaxIdleConnsPerHost
+	}
+	transport.MaxIdleConnsPerHost = maxIdlePerHost
+	if transport.MaxIdleConns < maxIdlePerHost {
+		transport.MaxIdleConns = maxIdlePerHost
+	}
+
+	idleTimeout := cfg.HTTPIdleConnTimeoutSec
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultIdleConnTimeoutSeconds
+	}
+	transport.IdleConnTimeout = time.Duration(idleTimeout) * time.Second
+
+	return &http.Client{Transport: transport}
+}
+
+// setAPIHTTPClient replaces the shared client, closing idle connections of the previous one
+func setAPIHTTPClient(client *http.Client) {
+	apiClientMutex.Lock()
+	previous := apiClient
+	apiClient = client
+	apiClientMutex.Unlock()
+
+	if previous != nil && previous != client {
+		previous.CloseIdleConnections()
+	}
+}
+
+// getAPIHTTPClient returns the shared client, creating it from cfg on first use when no
+// server has been initialized (e.g. direct ExecuteAPICall callers)
+func getAPIHTTPClient(cfg *config.Config) *http.Client {
+	apiClientMutex.RLock()
+	client := apiClient
+	apiClientMutex.RUnlock()
+	if client != nil {
+		return client
+	}
+
+	apiClientMutex.Lock()
+	defer apiClientMutex.Unlock()
+	if apiClient == nil {
+		apiClient = newAPIHTTPClient(cfg)
+	}
+	return apiClient
+}
+
+// apiRequestTimeout returns the timeout applied to each API request
+func apiRequestTimeout(cfg *config.Config) time.Duration {
+	if cfg.HTTPTimeoutSec > 0 {
+		return time.Duration(cfg.HTTPTimeoutSec) * time.Second
+	}
+	return HTTPTimeoutSeconds * time.Second
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newConnectionCountingServer returns a test API server and a counter of the TCP connections it accepted
+func newConnectionCountingServer(t testing.TB) (*httptest.Server, *int32) {
+	var connections int32
+	apiServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	apiServer.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	apiServer.Start()
+	t.Cleanup(apiServer.Close)
+	return apiServer, &connections
+}
+
+func TestExecuteAPICallReusesConnections(t *testing.T) {
+	apiServer, connections := newConnectionCountingServer(t)
+	cfg := newTestConfig(t, apiServer.URL)
+	setAPIHTTPClient(newAPIHTTPClient(cfg))
+
+	for i := 0; i < 5; i++ {
+		if _, err := ExecuteAPICall(cfg, nil, "GET", "/subjects", nil, nil); err != nil {
+			t.Fatalf("Call %d: unexpected error: %v", i, err)
+		}
+	}
+
+	if got := atomic.LoadInt32(connections); got != 1 {
+		t.Errorf("Expected 5 sequential calls to share 1 connection, got %d connections", got)
+	}
+}
+
+func TestExecuteAPICallTimeout(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	cfg.HTTPTimeoutSec = 1
+	setAPIHTTPClient(newAPIHTTPClient(cfg))
+
+	start := time.Now()
+	if _, err := ExecuteAPICall(cfg, nil, "GET", "/subjects", nil, nil); err == nil {
+		t.Error("Expected the call to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 1900*time.Millisecond {
+		t.Errorf("Expected the call to stop after the 1s timeout, took %v", elapsed)
+	}
+}
+
+func BenchmarkExecuteAPICall(b *testing.B) {
+	apiServer, connections := newConnectionCountingServer(b)
+	cfg := newTestConfig(b, apiServer.URL)
+	setAPIHTTPClient(newAPIHTTPClient(cfg))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ExecuteAPICall(cfg, nil, "GET", "/subjects", nil, nil); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	b.ReportMetric(float64(atomic.LoadInt32(connections)), "connections")
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// idArgumentPrefixes maps id arguments to the prefix of the Confluent Cloud ids they take
+var idArgumentPrefixes = map[string]string{
+	ParamEnvironment:    "env-",
+	ParamEnvironmentID:  "env-",
+	ParamClusterID:      "lkc-",
+	ParamKafkaClusterID: "lkc-",
+	ParamComputePoolID:  "lfcp-",
+	ParamPoolID:         "lfcp-",
+}
+
+// invalidIDArguments lists, sorted, the id arguments whose value does not start with the expected
+// prefix, looking at top-level and nested "parameters" arguments. Empty and non-string values are
+// left to the other checks.
+func invalidIDArguments(args map[string]interface{}) []string {
+	var invalid []string
+	check := func(values map[string]interface{}) {
+		for name, prefix := range idArgumentPrefixes {
+			if value, ok := values[name].(string); ok && value != "" && !strings.HasPrefix(value, prefix) {
+				invalid = append(invalid, fmt.Sprintf("%s '%s' must start with '%s'", name, value, prefix))
+			}
+		}
+	}
+	check(args)
+	if nested, ok := args["parameters"].(map[string]interface{}); ok {
+		check(nested)
+	}
+	sort.Strings(invalid)
+	return invalid
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Concurrent identical GETs, common while resources are discovered, wait for the first one to
+// come back instead of each sending its own upstream request. Like the response cache, requests
+// are only shared between calls with the same credentials and headers.
+var inflightGETs = &inflightGroup{calls: make(map[string]*inflightCall)}
+
+// fetchedResponse is a response read from the API, shared read-only between the calls waiting
+// on it
+type fetchedResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// inflightCall is a request other calls can wait on until it completes
+type inflightCall struct {
+	done     chan struct{}
+	response *fetchedResponse
+	err      error
+}
+
+// inflightGroup coalesces calls with the same key while one of them is in flight
+type inflightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+}
+
+// do runs fetch unless a call with the same key is already in flight, in which case it waits
+// for that call and returns its outcome, or the context error if ctx ends first. shared reports
+// whether the outcome came from another call.
+func (g *inflightGroup) do(ctx context.Context, key string, fetch func() (*fetchedResponse, error)) (response *fetchedResponse, err error, shared bool) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.response, call.err, true
+		case <-ctx.Done():
+			return nil, ctx.Err(), true
+		}
+	}
+	call := &inflightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+	call.response, call.err = fetch()
+	return call.response, call.err, false
+}
+
+// inflightKey identifies a GET by URL, credentials and request headers
+func inflightKey(fullURL, credentials string, headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var key strings.Builder
+	key.WriteString(credentials)
+	for _, name := range names {
+		key.WriteString("\n" + name + ": " + headers[name])
+	}
+	return responseCacheKey(fullURL, key.String())
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExecuteAPICallSharesInflightGETs(t *testing.T) {
+	var requests atomic.Int32
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		// Hold the response so the other calls arrive while the first one is in flight
+		time.Sleep(300 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[{"topic_name":"orders"}]}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	const calls = 8
+
+	var wg sync.WaitGroup
+	results := make([]map[string]interface{}, calls)
+	errs := make([]error, calls)
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = ExecuteAPICall(cfg, nil, "GET", "/kafka/v3/clusters/lkc-test/topics", nil, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected one upstream request for %d identical GETs, got %d", calls, got)
+	}
+	for i := range results {
+		if errs[i] != nil {
+			t.Fatalf("Call %d failed: %v", i, errs[i])
+		}
+		if data, _ := results[i]["data"].([]interface{}); len(data) != 1 {
+			t.Errorf("Call %d got %v", i, results[i])
+		}
+	}
+	// Each caller parses its own copy of the shared response
+	results[0]["data"] = nil
+	if results[1]["data"] == nil {
+		t.Error("Expected callers not to share result maps")
+	}
+
+	t.Run("Sequential GETs are sent again", func(t *testing.T) {
+		requests.Store(0)
+		for i := 0; i < 2; i++ {
+			if _, err := ExecuteAPICall(cfg, nil, "GET", "/kafka/v3/clusters/lkc-test/topics", nil, nil); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		if got := requests.Load(); got != 2 {
+			t.Errorf("Expected two upstream requests, got %d", got)
+		}
+	})
+}
+
+func TestInflightJoinerStopsOnContextCancel(t *testing.T) {
+	group := &inflightGroup{calls: make(map[string]*inflightCall)}
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	go group.do(context.Background(), "key", func() (*fetchedResponse, error) {
+		close(started)
+		<-release
+		return &fetchedResponse{statusCode: http.StatusOK}, nil
+	})
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err, shared := group.do(ctx, "key", func() (*fetchedResponse, error) {
+			t.Error("Expected the joiner not to fetch")
+			return nil, nil
+		})
+		if !shared {
+			t.Error("Expected the joiner to share the in-flight call")
+		}
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the joiner to return once its context was canceled")
+	}
+}
@@ -0,0 +1,18 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/monitoring"
+)
+
+// inventoryMetrics reports how many prompts and tools are loaded and when they last loaded
+func (s *MCPServer) inventoryMetrics() monitoring.InventoryMetrics {
+	metrics := monitoring.InventoryMetrics{
+		Tools:        len(s.tools) + len(s.customTools),
+		SpecLoadedAt: s.specLoadedAt,
+	}
+	if s.promptManager != nil {
+		metrics.Prompts = len(s.promptManager.GetPrompts())
+		metrics.PromptsLoadedAt = s.promptManager.LoadedAt()
+	}
+	return metrics
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"fmt"
+	"mcolomerc/mcp-server/internal/monitoring"
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInventoryMetrics(t *testing.T) {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+	promptsDir := t.TempDir()
+	for _, name := range []string{"first.txt", "second.txt"} {
+		if err := os.WriteFile(filepath.Join(promptsDir, name), []byte("# Test\nList the topics."), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	spec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Get: &openapi.Operation{Summary: "List topics"},
+			},
+		},
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+
+	before := time.Now().Unix()
+	cfg := newTestConfig(t, "http://localhost")
+	cfg.PromptsFolder = promptsDir
+	server := NewCompositeServer(cfg, spec, &openapi.OpenAPISpec{}, semanticTools)
+	monitor := monitoring.NewMonitor(time.Minute)
+	server.SetMonitor(monitor)
+
+	inventory := monitor.GetCurrentMetrics().Inventory
+	if inventory == nil {
+		t.Fatalf("Expected inventory metrics once a monitor is set")
+	}
+	if inventory.Prompts != 2 || inventory.Tools != len(semanticTools) {
+		t.Errorf("Expected 2 prompts and %d tools, got %d and %d", len(semanticTools), inventory.Prompts, inventory.Tools)
+	}
+
+	recorder := httptest.NewRecorder()
+	monitoring.NewHTTPHandler(monitor).PrometheusHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics/prometheus", nil))
+	body := recorder.Body.String()
+	for _, line := range []string{
+		"mcp_prompts_loaded 2",
+		fmt.Sprintf("mcp_tools_loaded %d", len(semanticTools)),
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Expected Prometheus output to contain %q, got:\n%s", line, body)
+		}
+	}
+	for _, series := range []string{"mcp_spec_last_load_timestamp_seconds", "mcp_prompts_last_load_timestamp_seconds"} {
+		var loadedAt int64
+		for _, line := range strings.Split(body, "\n") {
+			if strings.HasPrefix(line, series+" ") {
+				fmt.Sscanf(strings.TrimPrefix(line, series+" "), "%d", &loadedAt)
+			}
+		}
+		if loadedAt < before || loadedAt > time.Now().Unix() {
+			t.Errorf("Expected %s to be the load time, got %d", series, loadedAt)
+		}
+	}
+}
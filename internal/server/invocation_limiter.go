@@ -0,0 +1,75 @@
+package server
+
+import (
+	"fmt"
+	"mcolomerc/mcp-server/internal/monitoring"
+	"sync/atomic"
+	"time"
+)
+
+// invocationLimiter bounds how many tool invocations run at once. Callers wait up to
+// timeout for a free slot and fail fast once it expires (immediately when timeout is 0).
+type invocationLimiter struct {
+	slots    chan struct{} // nil when invocations are unlimited
+	timeout  time.Duration
+	inFlight int64
+	rejected int64
+}
+
+// newInvocationLimiter creates a limiter; maxConcurrent <= 0 means unlimited
+func newInvocationLimiter(maxConcurrent int, timeout time.Duration) *invocationLimiter {
+	limiter := &invocationLimiter{timeout: timeout}
+	if maxConcurrent > 0 {
+		limiter.slots = make(chan struct{}, maxConcurrent)
+	}
+	return limiter
+}
+
+// acquire reserves a slot, returning an error when none frees up in time
+func (l *invocationLimiter) acquire() error {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		default:
+			if !l.wait() {
+				atomic.AddInt64(&l.rejected, 1)
+				return fmt.Errorf("too many concurrent tool invocations (max %d); try again shortly", cap(l.slots))
+			}
+		}
+	}
+	atomic.AddInt64(&l.inFlight, 1)
+	return nil
+}
+
+// wait queues for a slot until the timeout expires
+func (l *invocationLimiter) wait() bool {
+	if l.timeout <= 0 {
+		return false
+	}
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// release frees a slot reserved by acquire
+func (l *invocationLimiter) release() {
+	atomic.AddInt64(&l.inFlight, -1)
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// stats reports the current limiter state for metrics
+func (l *invocationLimiter) stats() monitoring.InvocationMetrics {
+	return monitoring.InvocationMetrics{
+		InFlight:      atomic.LoadInt64(&l.inFlight),
+		MaxConcurrent: int64(cap(l.slots)),
+		Rejected:      atomic.LoadInt64(&l.rejected),
+	}
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"fmt"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInvokeToolConcurrencyLimit(t *testing.T) {
+	started := make(chan struct{}, 10)
+	unblock := make(chan struct{})
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-unblock
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	cfg.MaxConcurrentInvocations = 2
+	cfg.InvocationQueueTimeoutSec = 0
+	server := newTopicsTestServer(t, cfg)
+
+	invoke := func(name string) InvokeResponse {
+		return server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: map[string]interface{}{
+			"resource":   "topics",
+			"topic_name": name,
+		}})
+	}
+
+	// Fill both slots with calls that block in the API
+	var wg sync.WaitGroup
+	responses := make([]InvokeResponse, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = invoke(fmt.Sprintf("blocking-%d", i))
+		}(i)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for in-flight invocations")
+		}
+	}
+
+	if stats := server.invocations.stats(); stats.InFlight != 2 || stats.MaxConcurrent != 2 {
+		t.Errorf("Expected 2 in-flight of max 2, got %+v", stats)
+	}
+
+	// A third call must fail fast while the limit is reached
+	resp := invoke("rejected")
+	if !strings.Contains(resp.Error, "too many concurrent tool invocations") {
+		t.Errorf("Expected concurrency limit error, got %q", resp.Error)
+	}
+	if stats := server.invocations.stats(); stats.Rejected != 1 {
+		t.Errorf("Expected 1 rejected invocation, got %d", stats.Rejected)
+	}
+
+	close(unblock)
+	wg.Wait()
+	for i, resp := range responses {
+		if resp.Error != "" {
+			t.Errorf("Blocking call %d failed: %s", i, resp.Error)
+		}
+	}
+
+	if stats := server.invocations.stats(); stats.InFlight != 0 {
+		t.Errorf("Expected no in-flight invocations after completion, got %d", stats.InFlight)
+	}
+	if resp := invoke("after"); resp.Error != "" {
+		t.Errorf("Expected call to succeed once slots are free, got %q", resp.Error)
+	}
+}
+
+func TestInvocationLimiterQueuesUntilTimeout(t *testing.T) {
+	limiter := newInvocationLimiter(1, 200*time.Millisecond)
+	if err := limiter.acquire(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A queued caller gets the slot once it is released within the timeout
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		limiter.release()
+	}()
+	if err := limiter.acquire(); err != nil {
+		t.Fatalf("Expected queued acquire to succeed, got %v", err)
+	}
+
+	// Without a release the queued caller gives up after the timeout
+	if err := limiter.acquire(); err == nil {
+		t.Error("Expected acquire to fail after the queue timeout")
+	}
+	limiter.release()
+
+	unlimited := newInvocationLimiter(0, 0)
+	for i := 0; i < 10; i++ {
+		if err := unlimited.acquire(); err != nil {
+			t.Fatalf("Unlimited limiter rejected call %d: %v", i, err)
+		}
+	}
+	if stats := unlimited.stats(); stats.InFlight != 10 || stats.MaxConcurrent != 0 {
+		t.Errorf("Expected 10 in-flight with no limit, got %+v", stats)
+	}
+}
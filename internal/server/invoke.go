@@ -2,19 +2,27 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mcolomerc/mcp-server/internal/config"
 	"mcolomerc/mcp-server/internal/logger"
+	"mcolomerc/mcp-server/internal/tools"
 	"mcolomerc/mcp-server/internal/types"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
 	"mcolomerc/mcp-server/internal/openapi"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 // Re-export types for convenience
@@ -45,7 +53,7 @@ func getAPICredentials(cfg *config.Config, securityType, endpoint string) (apiKe
 	case SecurityTypeCloudAPIKey:
 		logger.Debug("Using Cloud API credentials for cloud-api-key")
 		if strings.Contains(endpoint, "regions") {
-			logger.Debug("*** REGIONS: Using Cloud API Key=%s, Secret=%s", cfg.ConfluentCloudAPIKey[:8]+"...", cfg.ConfluentCloudAPISecret[:8]+"...")
+			logger.Debug("*** REGIONS: Using Cloud API Key=%s, Secret=%s", maskCredential(cfg.ConfluentCloudAPIKey), maskCredential(cfg.ConfluentCloudAPISecret))
 		}
 		return cfg.ConfluentCloudAPIKey, cfg.ConfluentCloudAPISecret
 	case "api-key":
@@ -95,11 +103,11 @@ func getAPICredentials(cfg *config.Config, securityType, endpoint string) (apiKe
 			// Handle exact matches and prefix matches
 			if strings.Contains(endpointLower, pattern) ||
 				(strings.HasSuffix(pattern, "/") && endpointLower == strings.TrimSuffix(pattern, "/")) {
-				logger.Debug("Pattern '%s' matched! Using credentials: key=%s, secret=%s", pattern, creds.Key[:8]+"...", creds.Secret[:8]+"...")
+				logger.Debug("Pattern '%s' matched! Using credentials: key=%s, secret=%s", pattern, maskCredential(creds.Key), maskCredential(creds.Secret))
 
 				// Special logging for catalog/tagdefs
 				if strings.Contains(endpointLower, "catalog") || strings.Contains(endpointLower, "tagdefs") {
-					logger.Debug("*** CATALOG/TAGDEFS CREDENTIALS: endpoint=%s, pattern=%s, key=%s", endpointLower, pattern, creds.Key[:8]+"...")
+					logger.Debug("*** CATALOG/TAGDEFS CREDENTIALS: endpoint=%s, pattern=%s, key=%s", endpointLower, pattern, maskCredential(creds.Key))
 				}
 
 				return creds.Key, creds.Secret
@@ -115,6 +123,49 @@ func getAPICredentials(cfg *config.Config, securityType, endpoint string) (apiKe
 	return "", ""
 }
 
+// maskCredential hides a key, secret or token in logs and debug output; unset values stay empty
+// so missing credentials remain visible
+func maskCredential(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "***"
+}
+
+// environmentIDArgument returns the environment a call targets, from its environment_id or
+// environment parameter
+func environmentIDArgument(parameters map[string]interface{}) string {
+	for _, name := range []string{ParamEnvironmentID, ParamEnvironment} {
+		if environmentID, ok := parameters[name].(string); ok && environmentID != "" {
+			return environmentID
+		}
+	}
+	return ""
+}
+
+// kafkaClusterIDArgument returns the Kafka cluster a call targets, from its cluster_id or
+// kafka_cluster_id argument
+func kafkaClusterIDArgument(parameters map[string]interface{}) string {
+	for _, name := range []string{ParamClusterID, ParamKafkaClusterID} {
+		if clusterID, ok := parameters[name].(string); ok && clusterID != "" {
+			return clusterID
+		}
+	}
+	return ""
+}
+
+// kafkaClusterIDFromPath returns the Kafka cluster a built path addresses, from the segment after
+// /clusters/, for calls whose arguments no longer carry it
+func kafkaClusterIDFromPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "clusters" && strings.HasPrefix(segments[i+1], "lkc-") {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
 // Helper to resolve default parameter values from Config
 func resolveDefaultParam(cfg *config.Config, paramName, endpoint string) string {
 	paramLower := strings.ToLower(paramName)
@@ -259,8 +310,36 @@ func ResolveRequiredParameters(cfg *config.Config, requiredParams []string, prov
 	return resolved
 }
 
+// APICallOptions holds per-call settings that adjust how an API request is sent
+type APICallOptions struct {
+	ContentType            string            // Content-Type for the request body; defaults to application/json
+	BaseURLOverride        string            // Base URL to use instead of the configured one; requires ALLOW_BASE_URL_OVERRIDE
+	SchemaRegistryEndpoint string            // Schema Registry endpoint to use instead of SCHEMA_REGISTRY_ENDPOINT; requires ALLOW_BASE_URL_OVERRIDE
+	Headers                map[string]string // Additional request headers, e.g. If-Match
+	Query                  map[string]string // Additional query parameters, sent whatever the method, e.g. page_token
+	Retryable              bool              // The call is idempotent and may be retried on transient failures
+	Profile                string            // Credential profile to authenticate with; empty uses the default credentials
+	BearerToken            string            // The MCP client's own token; replaces the configured credentials when set
+	ServerVariables        map[string]string // Per-call values for spec server URL variables; requires USE_SPEC_SERVERS
+	Context                context.Context   // Parent context carrying the trace span; its cancellation is ignored
+	Deadline               time.Time         // End of the invocation budget the call is part of; zero means none
+
+	// ETag, when set, receives the ETag header of a successful response. It is kept out of the
+	// result so the API payload is returned unchanged.
+	ETag *string
+
+	// RefreshBearerToken returns a new bearer token when the API rejects BearerToken with a 401,
+	// e.g. for embedders that obtain tokens through OAuth. The call is repeated once with it.
+	RefreshBearerToken func(ctx context.Context) (string, error)
+}
+
 // Execute API call to Confluent Cloud
 func ExecuteAPICall(cfg *config.Config, spec *openapi.OpenAPISpec, method, path string, parameters map[string]interface{}, requestBody interface{}) (map[string]interface{}, error) {
+	return ExecuteAPICallWithOptions(cfg, spec, method, path, parameters, requestBody, APICallOptions{})
+}
+
+// ExecuteAPICallWithOptions executes an API call to Confluent Cloud with per-call options
+func ExecuteAPICallWithOptions(cfg *config.Config, spec *openapi.OpenAPISpec, method, path string, parameters map[string]interface{}, requestBody interface{}, opts APICallOptions) (map[string]interface{}, error) {
 	logger.Debug("ExecuteAPICall called with method=%s, path=%s, parameters=%v, requestBody=%v\n", method, path, parameters, requestBody)
 
 	// Special logging for tagdefs
@@ -268,17 +347,62 @@ func ExecuteAPICall(cfg *config.Config, spec *openapi.OpenAPISpec, method, path
 		logger.Debug("*** TAGDEFS API CALL: method=%s, path=%s", method, path)
 	}
 
+	// Work with the spec path; the gateway prefix is added back when building the URL
+	path = stripSpecBasePath(cfg.SpecBasePath, path)
+
 	// Determine security type using the OpenAPI spec or fallback to static approach
 	securityType := DetermineSecurityTypeFromSpec(spec, method, path)
 
-	// Get appropriate API credentials
-	apiKey, apiSecret := getAPICredentials(cfg, securityType, path)
-	if apiKey == "" || apiSecret == "" {
+	// Get appropriate API credentials, from the selected profile if any. Without one, an
+	// environment mapped in ENVIRONMENT_PROFILES selects its own profile.
+	profile := opts.Profile
+	if profile == "" {
+		profile = cfg.ProfileForEnvironment(environmentIDArgument(parameters))
+	}
+	credentialsCfg, err := cfg.WithProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, apiSecret := getAPICredentials(credentialsCfg, securityType, path)
+	if opts.BearerToken == "" && (apiKey == "" || apiSecret == "") {
 		return nil, fmt.Errorf("missing API credentials for security type: %s", securityType)
 	}
 
-	// Determine base URL based on path
-	baseURL := getBaseURL(cfg, path)
+	// Determine base URL based on path, unless the call overrides it. A per-call Schema Registry
+	// endpoint replaces the configured one for Schema Registry paths only.
+	baseURLConfig := cfg
+	if opts.SchemaRegistryEndpoint != "" {
+		endpoint, err := validateEndpointOverride(cfg, ParamSchemaRegistryEndpoint, opts.SchemaRegistryEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		scoped := *cfg
+		scoped.SchemaRegistryEndpoint = endpoint
+		baseURLConfig = &scoped
+	}
+	baseURL := getBaseURL(baseURLConfig, path, parameters)
+	if baseURLConfig != cfg && baseURL == baseURLConfig.SchemaRegistryEndpoint {
+		if err := checkSchemaRegistryOverrideCredentials(cfg, profile, baseURL, opts.BearerToken != ""); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.UseSpecServers {
+		serverURL, err := resolveSpecServerURL(cfg, spec, path, opts.ServerVariables)
+		if err != nil {
+			return nil, err
+		}
+		if serverURL != "" {
+			baseURL = serverURL
+		}
+	}
+	if opts.BaseURLOverride != "" {
+		override, err := validateBaseURLOverride(cfg, opts.BaseURLOverride)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debug("Using base URL override %s instead of %s for path %s", override, baseURL, path)
+		baseURL = override
+	}
 	if baseURL == "" {
 		return nil, fmt.Errorf("could not determine base URL for path: %s", path)
 	}
@@ -289,104 +413,208 @@ func ExecuteAPICall(cfg *config.Config, spec *openapi.OpenAPISpec, method, path
 	}
 
 	// Build full URL with query parameters
-	fullURL := baseURL + path
+	fullURL := baseURL + applySpecBasePath(cfg.SpecBasePath, path)
+	queryValues := url.Values{}
 	if len(parameters) > 0 && method == "GET" {
-		queryValues := url.Values{}
 		for key, value := range parameters {
 			// Only add parameters that aren't already in the path
 			if !strings.Contains(path, "{"+key+"}") {
-				queryValues.Add(key, fmt.Sprintf("%v", value))
+				addQueryParameter(queryValues, key, value, spec.FindParameter(method, path, "query", key))
 			}
 		}
-		if len(queryValues) > 0 {
-			fullURL += "?" + queryValues.Encode()
-		}
 	}
+	for key, value := range opts.Query {
+		queryValues.Set(key, value)
+	}
+	if len(queryValues) > 0 {
+		fullURL += "?" + queryValues.Encode()
+	}
+
+	// Trace the request as a child of the invocation span, if any
+	spanCtx, span := startAPICallSpan(opts.Context, method, fullURL)
+	defer span.End()
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: HTTPTimeoutSeconds * time.Second,
+	// Revalidate a cached GET response instead of downloading it again
+	credentials := apiKey + ":" + apiSecret
+	if opts.BearerToken != "" {
+		credentials = AuthBearerPrefix + opts.BearerToken
 	}
+	cache := getResponseCache()
+	cacheKey := ""
+	var cached *cachedResponse
+	if method == "GET" && cache != nil {
+		cacheKey = responseCacheKey(fullURL, credentials)
+		if cached = cache.get(cacheKey); cached != nil {
+			opts.Headers = cached.conditionalHeaders(opts.Headers)
+		}
+	}
+
+	// Reuse the shared client so connections are kept alive across calls
+	client := getAPIHTTPClient(cfg)
+	timeout := apiRequestTimeout(cfg)
 
 	// Prepare request body
-	var bodyReader io.Reader
+	var bodyBytes []byte
 	if requestBody != nil {
-		bodyBytes, err := json.Marshal(requestBody)
+		var err error
+		bodyBytes, err = json.Marshal(requestBody)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %v", err)
 		}
 		logger.Debug("Final JSON request body: %s\n", string(bodyBytes))
 		logger.Debug("Final JSON request body: %s\n", string(bodyBytes))
-		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
-	// Create HTTP request
-	req, err := http.NewRequest(method, fullURL, bodyReader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
+	accept := acceptHeader(spec, method, path)
+	callHeaders := opts.Headers
+	opts.Headers = withServiceHeaders(cfg, path, opts.Headers)
 
-	// Special logging for tagdefs final URL
-	if strings.Contains(path, "tagdefs") {
-		logger.Debug("*** TAGDEFS FINAL REQUEST: %s %s", method, fullURL)
+	// On failure, attach a curl command that repeats the call when asked to or when debugging
+	withRepro := func(err error) error {
+		if !cfg.ReproOnError && cfg.LOG != "DEBUG" {
+			return err
+		}
+		contentType := ContentTypeJSON
+		if opts.ContentType != "" {
+			contentType = opts.ContentType
+		}
+		repro := curlCommand(method, fullURL, contentType, accept, reproHeaders(callHeaders, opts.Headers), bodyBytes, opts.BearerToken != "", credentialSourceFor(securityType, path))
+		return &reproError{err: err, repro: repro}
 	}
 
-	// Set headers
-	req.Header.Set(HeaderContentType, ContentTypeJSON)
-
-	// Special handling for telemetry export endpoints
-	if strings.Contains(path, "/v2/metrics/") && strings.Contains(path, "/export") {
-		// Telemetry export endpoint expects Prometheus/OpenMetrics format, not JSON
-		req.Header.Set(HeaderAccept, "text/plain;version=0.0.4")
-		logger.Debug("Setting Prometheus Accept header for telemetry export endpoint")
-	} else {
-		req.Header.Set(HeaderAccept, ContentTypeJSON)
-	}
+	// Send the request and read the response. Identical GETs in flight at the same time share
+	// one upstream request.
+	fetch := func() (*fetchedResponse, error) {
+		// Execute request, retrying transient failures of idempotent calls
+		maxAttempts := 1
+		if opts.Retryable && cfg.MaxRetries > 0 {
+			maxAttempts += cfg.MaxRetries
+		}
+		var resp *http.Response
+		var cancel context.CancelFunc
+		for attempt := 1; ; attempt++ {
+			// Each attempt gets the full timeout, which also covers reading the response body, but
+			// never more than what is left of the invocation budget
+			attemptTimeout, budgetErr := budgetedTimeout(cfg, timeout, opts.Deadline)
+			if budgetErr != nil {
+				resp, err, cancel = nil, budgetErr, func() {}
+				break
+			}
+			var ctx context.Context
+			ctx, cancel = context.WithTimeout(context.WithoutCancel(spanCtx), attemptTimeout)
+			resp, err = doAPIRequest(ctx, client, method, fullURL, path, accept, bodyBytes, apiKey, apiSecret, opts)
+			if attempt >= maxAttempts || !isTransientFailure(resp, err) {
+				break
+			}
+			if resp != nil {
+				resp.Body.Close()
+			}
+			cancel()
+			delay := retryDelay(cfg, attempt)
+			if !opts.Deadline.IsZero() && time.Until(opts.Deadline) <= delay {
+				resp, err = nil, budgetExceededError(cfg)
+				break
+			}
+			logger.Debug("Retrying %s %s after transient failure (attempt %d of %d, waiting %v)", method, path, attempt+1, maxAttempts, delay)
+			time.Sleep(delay)
+		}
+		// A rejected bearer token is refreshed once and the call repeated with the new token
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && opts.BearerToken != "" && opts.RefreshBearerToken != nil {
+			if token, refreshErr := opts.RefreshBearerToken(spanCtx); refreshErr != nil {
+				logger.Debug("Failed to refresh bearer token after 401 on %s %s: %v", method, path, refreshErr)
+			} else if attemptTimeout, budgetErr := budgetedTimeout(cfg, timeout, opts.Deadline); budgetErr != nil {
+				logger.Debug("Not repeating %s %s with a refreshed token: %v", method, path, budgetErr)
+			} else if token != "" {
+				resp.Body.Close()
+				cancel()
+				opts.BearerToken = token
+				var ctx context.Context
+				ctx, cancel = context.WithTimeout(context.WithoutCancel(spanCtx), attemptTimeout)
+				resp, err = doAPIRequest(ctx, client, method, fullURL, path, accept, bodyBytes, apiKey, apiSecret, opts)
+			}
+		}
+		defer cancel()
+		// A request cut short by the end of the budget reports the budget rather than a bare timeout
+		if err != nil && budgetExhausted(opts.Deadline) && !errors.Is(err, errInvocationBudgetExceeded) {
+			err = fmt.Errorf("%w: %v", budgetExceededError(cfg), err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
 
-	// Set authentication
-	auth := base64.StdEncoding.EncodeToString([]byte(apiKey + ":" + apiSecret))
-	req.Header.Set(HeaderAuth, AuthBasicPrefix+auth)
+		// Read response body
+		responseBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %v", err)
+		}
 
-	// Execute request
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %v", err)
+		fetched := &fetchedResponse{statusCode: resp.StatusCode, header: resp.Header, body: responseBody}
+		if cached != nil && fetched.statusCode == http.StatusNotModified {
+			logger.Debug("Serving cached response for %s %s (not modified)", method, path)
+			fetched = &fetchedResponse{statusCode: cached.statusCode, header: cached.header, body: cached.body}
+		} else if cacheKey != "" && fetched.statusCode == http.StatusOK {
+			cache.store(cacheKey, fetched.statusCode, fetched.header, fetched.body)
+		}
+		return fetched, nil
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	responseBody, err := io.ReadAll(resp.Body)
+	var fetched *fetchedResponse
+	if method == "GET" {
+		var shared bool
+		fetched, err, shared = inflightGETs.do(spanCtx, inflightKey(fullURL, credentials, opts.Headers), fetch)
+		if shared {
+			logger.Debug("Shared the in-flight response of %s %s", method, path)
+		}
+	} else {
+		fetched, err = fetch()
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
+		recordSpanError(span, err)
+		return nil, withRepro(err)
 	}
+	statusCode, header, responseBody := fetched.statusCode, fetched.header, fetched.body
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(statusCode))
 
 	// Check status code
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(responseBody))
+	if statusCode == http.StatusUnauthorized {
+		recordSpanError(span, fmt.Errorf("API request failed with status %d", statusCode))
+		return nil, withRepro(authenticationError(securityType, path, profile, opts.BearerToken != "", responseBody))
+	}
+	if statusCode >= 400 {
+		recordSpanError(span, fmt.Errorf("API request failed with status %d", statusCode))
+		return nil, withRepro(withFieldErrors(fmt.Errorf("API request failed with status %d: %s", statusCode, string(responseBody)), responseBody))
 	}
 
 	// Handle response based on content type
 	var result map[string]interface{}
 	if len(responseBody) > 0 {
 		// Check if this is a telemetry export endpoint response (Prometheus/OpenMetrics format)
-		contentType := resp.Header.Get("Content-Type")
+		contentType := header.Get("Content-Type")
 		if strings.Contains(path, "/v2/metrics/") && strings.Contains(path, "/export") &&
 			(strings.Contains(contentType, "text/plain") || strings.Contains(contentType, "openmetrics-text")) {
 			// Return Prometheus/OpenMetrics response as-is
 			return map[string]interface{}{
 				"metrics_data": string(responseBody),
 				"content_type": contentType,
-				"status_code":  resp.StatusCode,
+				"status_code":  statusCode,
 				"format":       "prometheus",
 			}, nil
 		}
 
+		// A proxy or gateway page in place of the API response is an error, not a result
+		if err := nonJSONResponseError(statusCode, contentType, responseBody); err != nil {
+			recordSpanError(span, err)
+			return nil, withRepro(err)
+		}
+
 		// Try to parse as JSON for regular API responses
 		if err := json.Unmarshal(responseBody, &result); err != nil {
 			// If JSON parsing fails, return raw response
 			return map[string]interface{}{
 				"raw_response": string(responseBody),
-				"status_code":  resp.StatusCode,
+				"status_code":  statusCode,
 			}, nil
 		}
 	}
@@ -395,13 +623,310 @@ func ExecuteAPICall(cfg *config.Config, spec *openapi.OpenAPISpec, method, path
 	if result == nil {
 		result = make(map[string]interface{})
 	}
-	result["status_code"] = resp.StatusCode
+	result["status_code"] = statusCode
+
+	// Hand the ETag to the caller so clients can send it back as If-Match on the next update
+	if opts.ETag != nil {
+		*opts.ETag = header.Get(HeaderETag)
+	}
 
 	return result, nil
 }
 
-// Get base URL based on the API path
-func getBaseURL(cfg *config.Config, path string) string {
+// withServiceHeaders adds the SERVICE_HEADERS of the service serving path to the headers of a
+// call. Headers set for the call itself take precedence.
+func withServiceHeaders(cfg *config.Config, path string, headers map[string]string) map[string]string {
+	serviceHeaders := cfg.ServiceHeaders[tools.ServiceForPath(path)]
+	if len(serviceHeaders) == 0 {
+		return headers
+	}
+
+	merged := make(map[string]string, len(serviceHeaders)+len(headers))
+	for name, value := range serviceHeaders {
+		merged[name] = value
+	}
+	for name, value := range headers {
+		merged[name] = value
+	}
+	return merged
+}
+
+// acceptHeader returns the Accept header of a request: the preferred media type among those the
+// operation declares for its successful responses, or JSON when the spec declares none
+func acceptHeader(spec *openapi.OpenAPISpec, method, path string) string {
+	// Telemetry export endpoint expects Prometheus/OpenMetrics format, not JSON
+	if strings.Contains(path, "/v2/metrics/") && strings.Contains(path, "/export") {
+		logger.Debug("Setting Prometheus Accept header for telemetry export endpoint")
+		return "text/plain;version=0.0.4"
+	}
+	if accept := tools.PreferredResponseContentType(spec.SuccessResponseContentTypes(method, path), path); accept != "" {
+		return accept
+	}
+	return ContentTypeJSON
+}
+
+// doAPIRequest builds and sends a single API request. The request is rebuilt on every attempt
+// so a retried call sends its body again.
+func doAPIRequest(ctx context.Context, client *http.Client, method, fullURL, path, accept string, bodyBytes []byte, apiKey, apiSecret string, opts APICallOptions) (*http.Response, error) {
+	var bodyReader io.Reader
+	if bodyBytes != nil {
+		bodyReader = bytes.NewReader(bodyBytes)
+	}
+
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	// Special logging for tagdefs final URL
+	if strings.Contains(path, "tagdefs") {
+		logger.Debug("*** TAGDEFS FINAL REQUEST: %s %s", method, fullURL)
+	}
+
+	// Set headers
+	contentType := ContentTypeJSON
+	if opts.ContentType != "" {
+		contentType = opts.ContentType
+	}
+	req.Header.Set(HeaderContentType, contentType)
+
+	req.Header.Set(HeaderAccept, accept)
+
+	for name, value := range opts.Headers {
+		req.Header.Set(name, value)
+	}
+
+	// Set authentication, preferring the client's own token over the configured credentials
+	if opts.BearerToken != "" {
+		req.Header.Set(HeaderAuth, AuthBearerPrefix+opts.BearerToken)
+	} else {
+		auth := base64.StdEncoding.EncodeToString([]byte(apiKey + ":" + apiSecret))
+		req.Header.Set(HeaderAuth, AuthBasicPrefix+auth)
+	}
+
+	// Propagate the trace context to the API
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	return resp, nil
+}
+
+// addQueryParameter adds a query value, encoding arrays and objects per the parameter's style and
+// explode settings. Without a definition they use the OpenAPI default (form, explode=true), which
+// repeats the key for each array item and sends each object property as its own parameter.
+func addQueryParameter(queryValues url.Values, key string, value interface{}, param *openapi.Parameter) {
+	var items []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			items = append(items, fmt.Sprintf("%v", item))
+		}
+	case []string:
+		items = v
+	case map[string]interface{}:
+		style, explode := queryParameterStyle(param)
+		addObjectQueryParameter(queryValues, key, v, style, explode)
+		return
+	default:
+		queryValues.Add(key, fmt.Sprintf("%v", value))
+		return
+	}
+
+	style, explode := queryParameterStyle(param)
+	if explode {
+		for _, item := range items {
+			queryValues.Add(key, item)
+		}
+		return
+	}
+	queryValues.Add(key, strings.Join(items, querySeparator(style)))
+}
+
+// queryParameterStyle returns the style and explode settings of a query parameter, applying the
+// OpenAPI defaults: form with explode=true, and explode=false for the other styles. Object schemas
+// with nested objects, which form cannot represent, default to deepObject.
+func queryParameterStyle(param *openapi.Parameter) (style string, explode bool) {
+	style, explode = "form", true
+	if param == nil {
+		return style, explode
+	}
+	if param.Style != "" {
+		style = param.Style
+	} else if hasNestedObject(param.Schema) {
+		style = "deepObject"
+	}
+	if param.Explode != nil {
+		explode = *param.Explode
+	} else if style != "form" && style != "deepObject" {
+		explode = false
+	}
+	return style, explode
+}
+
+// hasNestedObject reports whether an object schema has a property that is itself an object
+func hasNestedObject(schema *openapi.Schema) bool {
+	if schema == nil || schema.Type != "object" {
+		return false
+	}
+	for _, property := range schema.Properties {
+		if property != nil && (property.Type == "object" || len(property.Properties) > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// querySeparator returns the separator of non-exploded values for a style
+func querySeparator(style string) string {
+	switch style {
+	case "spaceDelimited":
+		return " "
+	case "pipeDelimited":
+		return "|"
+	}
+	return ","
+}
+
+// addObjectQueryParameter adds an object value: deepObject sends key[prop]=value (recursing into
+// nested objects), exploded form sends prop=value, and the other styles send the properties and
+// values as one delimited list. Properties are sorted so the URL is stable.
+func addObjectQueryParameter(queryValues url.Values, key string, object map[string]interface{}, style string, explode bool) {
+	properties := make([]string, 0, len(object))
+	for property := range object {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	var pairs []string
+	for _, property := range properties {
+		value := object[property]
+		switch {
+		case style == "deepObject":
+			if nested, ok := value.(map[string]interface{}); ok {
+				addObjectQueryParameter(queryValues, key+"["+property+"]", nested, style, explode)
+				continue
+			}
+			queryValues.Add(key+"["+property+"]", queryScalar(value))
+		case explode:
+			queryValues.Add(property, queryScalar(value))
+		default:
+			pairs = append(pairs, property, queryScalar(value))
+		}
+	}
+	if len(pairs) > 0 {
+		queryValues.Add(key, strings.Join(pairs, querySeparator(style)))
+	}
+}
+
+// queryScalar formats a query value, encoding nested arrays and objects as JSON
+func queryScalar(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		if encoded, err := json.Marshal(value); err == nil {
+			return string(encoded)
+		}
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// validateBaseURLOverride checks that base URL overrides are enabled and that the override
+// targets an allowed host, returning the override without a trailing slash
+func validateBaseURLOverride(cfg *config.Config, override string) (string, error) {
+	return validateEndpointOverride(cfg, ParamBaseURLOverride, override)
+}
+
+// validateEndpointOverride checks a per-call endpoint argument, such as base_url_override or
+// schema_registry_endpoint, against ALLOW_BASE_URL_OVERRIDE and the allowed hosts
+func validateEndpointOverride(cfg *config.Config, param, override string) (string, error) {
+	if !cfg.AllowBaseURLOverride {
+		return "", fmt.Errorf("%s is not allowed: set ALLOW_BASE_URL_OVERRIDE=true to enable it", param)
+	}
+
+	parsed, err := url.Parse(override)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return "", fmt.Errorf("invalid %s '%s': must be an absolute http(s) URL", param, override)
+	}
+
+	allowedHosts := cfg.BaseURLOverrideAllowedHosts
+	if len(allowedHosts) == 0 {
+		allowedHosts = DefaultBaseURLOverrideHosts
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(strings.TrimPrefix(allowed, "*."))
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return strings.TrimSuffix(override, "/"), nil
+		}
+	}
+
+	return "", fmt.Errorf("%s host '%s' is not in the allowed hosts %v", param, host, allowedHosts)
+}
+
+// checkSchemaRegistryOverrideCredentials keeps the configured Schema Registry credentials from
+// being sent to a per-call schema_registry_endpoint: another endpoint needs a credential profile
+// declaring it, or the client's own token
+func checkSchemaRegistryOverrideCredentials(cfg *config.Config, profile, endpoint string, bearer bool) error {
+	if bearer || endpoint == strings.TrimSuffix(cfg.SchemaRegistryEndpoint, "/") || cfg.SchemaRegistryCredentialsFor(profile, endpoint) {
+		return nil
+	}
+	return fmt.Errorf("%s '%s' needs a credential profile with Schema Registry credentials for it (schema_registry_endpoint, schema_registry_api_key and schema_registry_api_secret); the configured Schema Registry credentials are only sent to SCHEMA_REGISTRY_ENDPOINT", ParamSchemaRegistryEndpoint, endpoint)
+}
+
+// normalizeSpecBasePath returns the configured gateway prefix with a leading slash and no
+// trailing slash, or an empty string when no prefix is configured
+func normalizeSpecBasePath(basePath string) string {
+	basePath = strings.Trim(basePath, "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// stripSpecBasePath removes the gateway prefix from a path that already carries it, such as a
+// next link returned through the gateway, so it can be matched against the unprefixed spec paths
+func stripSpecBasePath(basePath, path string) string {
+	basePath = normalizeSpecBasePath(basePath)
+	if basePath == "" {
+		return path
+	}
+	if path == basePath {
+		return "/"
+	}
+	if strings.HasPrefix(path, basePath+"/") {
+		return strings.TrimPrefix(path, basePath)
+	}
+	return path
+}
+
+// applySpecBasePath puts the gateway prefix in front of a spec path for the request URL
+func applySpecBasePath(basePath, path string) string {
+	return normalizeSpecBasePath(basePath) + path
+}
+
+// resolveSpecServerURL resolves the spec server URL for a path, taking template variables from
+// the call first and SPEC_SERVER_VARIABLES second; empty when the spec declares no servers
+func resolveSpecServerURL(cfg *config.Config, spec *openapi.OpenAPISpec, path string, callValues map[string]string) (string, error) {
+	if spec == nil {
+		return "", nil
+	}
+	values := make(map[string]string, len(cfg.SpecServerVariables)+len(callValues))
+	for name, value := range cfg.SpecServerVariables {
+		values[name] = value
+	}
+	for name, value := range callValues {
+		values[name] = value
+	}
+	return spec.ResolveServerURL(path, values)
+}
+
+// Get base URL based on the API path. Kafka calls use the REST endpoint of the cluster in the
+// cluster_id argument, or else in the path, when KAFKA_CLUSTERS lists it.
+func getBaseURL(cfg *config.Config, path string, parameters map[string]interface{}) string {
 	pathLower := strings.ToLower(path)
 
 	// Map path patterns to their corresponding base URLs and config fields
@@ -415,7 +940,13 @@ func getBaseURL(cfg *config.Config, path string) string {
 		},
 		{
 			patterns: []string{"/kafka/", EndpointPatternTopics, EndpointPatternConsumerGroups, EndpointPatternACLs},
-			getURL:   func() string { return cfg.KafkaRestEndpoint },
+			getURL: func() string {
+				clusterID := kafkaClusterIDArgument(parameters)
+				if clusterID == "" {
+					clusterID = kafkaClusterIDFromPath(path)
+				}
+				return cfg.KafkaRestEndpointFor(clusterID)
+			},
 		},
 		{
 			patterns: []string{"/flink/", EndpointPatternComputePools, EndpointPatternStatements},
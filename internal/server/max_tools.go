@@ -0,0 +1,53 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/tools"
+)
+
+// applyMaxTools applies MAX_TOOLS to every tool the server exposes: the generated tools, the
+// built-in tools and the CUSTOM_TOOLS file. Dropped generated tools are removed from s.tools, so
+// the auxiliary tools never advertise them; the returned names are left out of registration.
+func (s *MCPServer) applyMaxTools() map[string]bool {
+	names := make([]string, 0, len(s.tools)+len(builtinToolNames)+len(s.config.CustomTools))
+	for _, tool := range s.tools {
+		names = append(names, s.exposedToolName(tool.Name))
+	}
+	for _, name := range builtinToolNames {
+		if !s.config.EnableAdminTools && (name == TestGuardrailsToolName || name == AuthDebugToolName) {
+			continue
+		}
+		names = append(names, name)
+	}
+	customNames, _ := customToolNames(s.config.CustomTools)
+	names = append(names, customNames...)
+
+	dropped := make(map[string]bool)
+	for _, name := range tools.LimitToolNames(names) {
+		dropped[name] = true
+	}
+	if len(dropped) == 0 {
+		return dropped
+	}
+
+	kept := make([]tools.Tool, 0, len(s.tools))
+	for _, tool := range s.tools {
+		if !dropped[s.exposedToolName(tool.Name)] {
+			kept = append(kept, tool)
+		}
+	}
+	s.tools = kept
+	return dropped
+}
+
+// removeDroppedBuiltins unregisters the built-in tools MAX_TOOLS left out
+func (s *MCPServer) removeDroppedBuiltins() {
+	var names []string
+	for _, name := range builtinToolNames {
+		if s.droppedTools[name] {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		s.mcpServer.DeleteTools(names...)
+	}
+}
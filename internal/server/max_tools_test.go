@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"mcolomerc/mcp-server/internal/tools"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMaxToolsCoversAllExposedTools(t *testing.T) {
+	t.Cleanup(func() { tools.SetMaxTools(0, nil) })
+	tools.SetMaxTools(3, []string{BatchToolName})
+
+	s := newTopicsTestServer(t, newTestConfig(t, ""))
+
+	response := s.mcpServer.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	rpcResponse, ok := response.(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("Unexpected tools/list response: %#v", response)
+	}
+	result, ok := rpcResponse.Result.(mcp.ListToolsResult)
+	if !ok {
+		t.Fatalf("Unexpected tools/list result: %#v", rpcResponse.Result)
+	}
+
+	var listed []string
+	for _, tool := range result.Tools {
+		listed = append(listed, tool.Name)
+	}
+	sort.Strings(listed)
+	if want := []string{BatchToolName, tools.ActionGet, tools.ActionList}; !reflect.DeepEqual(listed, want) {
+		t.Errorf("Expected exposed tools %v, got %v", want, listed)
+	}
+
+	var kept []string
+	for _, tool := range s.GetTools() {
+		kept = append(kept, tool.Name)
+	}
+	sort.Strings(kept)
+	if want := []string{tools.ActionGet, tools.ActionList}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("Expected generated tools %v, got %v", want, kept)
+	}
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// NonJSONSnippetLength is how many characters of a non-JSON response body an error quotes
+const NonJSONSnippetLength = 200
+
+// nonJSONResponseError explains a successful response whose body is neither JSON nor declared as
+// JSON, such as the HTML error or login page of a proxy or gateway answering in place of the API.
+// Bodies declared as JSON that fail to parse are left to the caller, which passes them through raw.
+func nonJSONResponseError(statusCode int, contentType string, body []byte) error {
+	if json.Valid(body) {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if strings.Contains(mediaType, "json") {
+		return nil
+	}
+	if mediaType == "" {
+		mediaType = "no content type"
+	}
+	return fmt.Errorf("API returned a non-JSON response (status %d, %s), likely a gateway, proxy or login page rather than the API: %s",
+		statusCode, mediaType, responseSnippet(body))
+}
+
+// responseSnippet collapses the whitespace of a response body and truncates it for an error message
+func responseSnippet(body []byte) string {
+	snippet := strings.Join(strings.Fields(string(body)), " ")
+	if runes := []rune(snippet); len(runes) > NonJSONSnippetLength {
+		snippet = string(runes[:NonJSONSnippetLength]) + "..."
+	}
+	return snippet
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInvokeToolHTMLResponse(t *testing.T) {
+	page := "<!DOCTYPE html>\n<html>\n  <head><title>Sign in</title></head>\n  <body>\n    <h1>Please sign in to continue</h1>\n" +
+		strings.Repeat("    <p>Your session has expired.</p>\n", 20) + "  </body>\n</html>\n"
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page))
+	}))
+	defer apiServer.Close()
+
+	server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+	resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: map[string]interface{}{"resource": "topics", "topic_name": "orders"}})
+	if resp.Result != nil {
+		t.Fatalf("Expected no result for an HTML page, got %v", resp.Result)
+	}
+	for _, want := range []string{"non-JSON response", "status 200", "text/html", "<!DOCTYPE html> <html> <head><title>Sign in</title></head>"} {
+		if !strings.Contains(resp.Error, want) {
+			t.Errorf("Expected the error to contain %q, got %q", want, resp.Error)
+		}
+	}
+	if !strings.HasSuffix(resp.Error, "...") || len(resp.Error) > len(page) {
+		t.Errorf("Expected the page to be truncated, got %q", resp.Error)
+	}
+}
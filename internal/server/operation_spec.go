@@ -0,0 +1,60 @@
+package server
+
+import (
+	"fmt"
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+)
+
+// OperationSpec is the unprocessed spec fragment an action and resource are mapped to
+type OperationSpec struct {
+	Action       string               `json:"action"`
+	Resource     string               `json:"resource"`
+	Method       string               `json:"method"`
+	Path         string               `json:"path"`
+	SecurityType string               `json:"security_type,omitempty"` // Effective security scheme, including the spec-wide default
+	Operation    *openapi.Operation   `json:"operation"`
+	RequestBody  *openapi.RequestBody `json:"request_body,omitempty"` // Request body with its $ref resolved
+}
+
+// GetOperationSpec looks up the endpoint mapping for an action and resource and returns the
+// OpenAPI operation it was generated from
+func (s *MCPServer) GetOperationSpec(action, resource string) (*OperationSpec, error) {
+	mapping, spec, err := s.lookupOperationMapping(action, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := spec.FindOperation(mapping.Method, mapping.PathPattern)
+	if operation == nil {
+		return nil, fmt.Errorf("operation %s %s not found in the OpenAPI spec", mapping.Method, mapping.PathPattern)
+	}
+
+	return &OperationSpec{
+		Action:       action,
+		Resource:     resource,
+		Method:       mapping.Method,
+		Path:         mapping.PathPattern,
+		SecurityType: spec.GetSecurityTypeForEndpoint(mapping.Method, mapping.PathPattern),
+		Operation:    operation,
+		RequestBody:  spec.ResolveRequestBodyRef(operation.RequestBody),
+	}, nil
+}
+
+// lookupOperationMapping returns the endpoint mapping for an action and resource along with the
+// spec it was generated from, so telemetry resources resolve against the telemetry spec
+func (s *MCPServer) lookupOperationMapping(action, resource string) (*tools.EndpointMapping, *openapi.OpenAPISpec, error) {
+	if action == "get_telemetry" {
+		mapping, err := tools.GetTelemetryEndpointMapping(resource)
+		if err != nil {
+			return nil, nil, err
+		}
+		return mapping, s.telemetrySpec, nil
+	}
+
+	mapping, err := tools.GetEndpointMapping(action, resource)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mapping, s.spec, nil
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"testing"
+)
+
+func TestGetOperationSpec(t *testing.T) {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+
+	topicSchema := &openapi.Schema{
+		Type:       "object",
+		Properties: map[string]*openapi.Schema{"topic_name": {Type: "string"}},
+		Required:   []string{"topic_name"},
+	}
+	spec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Post: &openapi.Operation{
+					OperationID: "createKafkaTopic",
+					Summary:     "Create Topic",
+					Parameters: []openapi.Parameter{
+						{Name: "cluster_id", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}},
+					},
+					RequestBody: &openapi.RequestBody{Ref: "#/components/requestBodies/CreateTopicRequest"},
+					Security:    []map[string][]string{{"resource-api-key": {}}},
+				},
+			},
+		},
+		Components: &openapi.Components{
+			RequestBodies: map[string]openapi.RequestBody{
+				"CreateTopicRequest": {
+					Content: map[string]openapi.MediaType{
+						"application/json": {Schema: topicSchema},
+					},
+				},
+			},
+		},
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+	server := NewCompositeServer(newTestConfig(t, ""), spec, &openapi.OpenAPISpec{}, semanticTools)
+
+	t.Run("Returns the mapped operation", func(t *testing.T) {
+		operationSpec, err := server.GetOperationSpec(tools.ActionCreate, "topics")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if operationSpec.Method != "POST" || operationSpec.Path != "/kafka/v3/clusters/{cluster_id}/topics" {
+			t.Errorf("Expected POST /kafka/v3/clusters/{cluster_id}/topics, got %s %s", operationSpec.Method, operationSpec.Path)
+		}
+		if operationSpec.Operation.OperationID != "createKafkaTopic" || operationSpec.Operation.Summary != "Create Topic" {
+			t.Errorf("Unexpected operation: %+v", operationSpec.Operation)
+		}
+		if len(operationSpec.Operation.Parameters) != 1 || operationSpec.Operation.Parameters[0].Name != "cluster_id" {
+			t.Errorf("Expected the cluster_id parameter, got %+v", operationSpec.Operation.Parameters)
+		}
+		if operationSpec.SecurityType != "resource-api-key" {
+			t.Errorf("Expected security type 'resource-api-key', got %q", operationSpec.SecurityType)
+		}
+		if operationSpec.RequestBody == nil || operationSpec.RequestBody.Content["application/json"].Schema != topicSchema {
+			t.Errorf("Expected the resolved request body, got %+v", operationSpec.RequestBody)
+		}
+	})
+
+	t.Run("Unknown mapping is an error", func(t *testing.T) {
+		if _, err := server.GetOperationSpec(tools.ActionDelete, "topics"); err == nil {
+			t.Error("Expected an error for an action without a mapping")
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"fmt"
+	"mcolomerc/mcp-server/internal/tools"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// JSON patch operations used when flat arguments are turned into a patch document
+const (
+	PatchOpReplace = "replace"
+	PatchOpRemove  = "remove"
+)
+
+// isPatchContentType reports whether a content type needs a PATCH-specific body shape
+func isPatchContentType(contentType string) bool {
+	return contentType == tools.ContentTypeMergePatchJSON || contentType == tools.ContentTypeJSONPatchJSON
+}
+
+// buildPatchRequestBody shapes the body of a PATCH call for its declared content type.
+// A merge patch is the schema-built object, or the patch arguments when the schema declares no
+// properties. A JSON patch takes an explicit 'operations' list, or turns each patch argument
+// into a replace operation (remove for null values).
+func buildPatchRequestBody(contentType string, schemaBody map[string]interface{}, mapping *tools.EndpointMapping, args map[string]interface{}) (interface{}, error) {
+	if contentType == tools.ContentTypeJSONPatchJSON {
+		if operations, ok := args[ParamPatchOperations]; ok {
+			return validatePatchOperations(operations)
+		}
+		fields := patchArguments(mapping, args)
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		operations := make([]map[string]interface{}, 0, len(names))
+		for _, name := range names {
+			pointer := "/" + escapeJSONPointer(name)
+			if fields[name] == nil {
+				operations = append(operations, map[string]interface{}{"op": PatchOpRemove, "path": pointer})
+				continue
+			}
+			operations = append(operations, map[string]interface{}{"op": PatchOpReplace, "path": pointer, "value": fields[name]})
+		}
+		return operations, nil
+	}
+
+	if len(schemaBody) > 0 {
+		return schemaBody, nil
+	}
+	return patchArguments(mapping, args), nil
+}
+
+// patchArguments returns the arguments that are patch fields: everything except path and
+// operation parameters and the server's control arguments. Arguments nested under 'parameters'
+// are used as they are.
+func patchArguments(mapping *tools.EndpointMapping, args map[string]interface{}) map[string]interface{} {
+	if params, ok := args["parameters"].(map[string]interface{}); ok {
+		return params
+	}
+
+	skip := tools.ExtractPathParameters(mapping.PathPattern)
+	skip = append(skip, mapping.RequiredParams...)
+	skip = append(skip, mapping.OptionalParams...)
+	skip = append(skip, strictControlArguments...)
+
+	fields := make(map[string]interface{})
+	for name, value := range args {
+		if !slices.Contains(skip, name) {
+			fields[name] = value
+		}
+	}
+	return fields
+}
+
+// validatePatchOperations checks that an explicit 'operations' argument is a list of JSON patch
+// operations, each with an op and a path
+func validatePatchOperations(value interface{}) ([]interface{}, error) {
+	operations, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'%s' must be a list of JSON patch operations", ParamPatchOperations)
+	}
+	for i, item := range operations {
+		operation, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("'%s' item %d must be an object", ParamPatchOperations, i+1)
+		}
+		if op, _ := operation["op"].(string); op == "" {
+			return nil, fmt.Errorf("'%s' item %d needs an 'op'", ParamPatchOperations, i+1)
+		}
+		if path, _ := operation["path"].(string); !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("'%s' item %d needs a 'path' starting with '/'", ParamPatchOperations, i+1)
+		}
+	}
+	return operations, nil
+}
+
+// escapeJSONPointer escapes a property name for use as a JSON pointer segment (RFC 6901)
+func escapeJSONPointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"encoding/json"
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// newPatchTestServer builds an MCPServer whose topic PATCH operation accepts the given media type
+func newPatchTestServer(t *testing.T, baseURL, contentType string, schema *openapi.Schema) *MCPServer {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+
+	spec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Get: &openapi.Operation{Summary: "List topics"},
+			},
+			"/kafka/v3/clusters/{cluster_id}/topics/{topic_name}": {
+				Get: &openapi.Operation{Summary: "Get topic"},
+				Patch: &openapi.Operation{
+					Summary: "Update topic",
+					RequestBody: &openapi.RequestBody{
+						Content: map[string]openapi.MediaType{contentType: {Schema: schema}},
+					},
+				},
+			},
+		},
+	}
+
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+	return NewCompositeServer(newTestConfig(t, baseURL), spec, &openapi.OpenAPISpec{}, semanticTools)
+}
+
+func TestInvokeToolPatchContentTypes(t *testing.T) {
+	var gotContentType string
+	var gotBody interface{}
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get(HeaderContentType)
+		gotBody = nil
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	t.Run("Merge patch sends a partial object", func(t *testing.T) {
+		schema := &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"partitions_count": {Type: "integer"},
+				"description":      {Type: "string"},
+			},
+		}
+		s := newPatchTestServer(t, apiServer.URL, tools.ContentTypeMergePatchJSON, schema)
+
+		resp := s.InvokeTool(InvokeRequest{Tool: tools.ActionUpdate, Arguments: map[string]interface{}{
+			"resource":         "topics",
+			"topic_name":       "orders",
+			"partitions_count": 12,
+		}})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		if gotContentType != tools.ContentTypeMergePatchJSON {
+			t.Errorf("Expected Content-Type %s, got %s", tools.ContentTypeMergePatchJSON, gotContentType)
+		}
+		if want := map[string]interface{}{"partitions_count": float64(12)}; !reflect.DeepEqual(gotBody, want) {
+			t.Errorf("Expected merge patch body %v, got %v", want, gotBody)
+		}
+	})
+
+	jsonPatchSchema := &openapi.Schema{
+		Type: "array",
+		Items: &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"op":    {Type: "string"},
+				"path":  {Type: "string"},
+				"value": {},
+			},
+		},
+	}
+
+	t.Run("JSON patch turns arguments into operations", func(t *testing.T) {
+		s := newPatchTestServer(t, apiServer.URL, tools.ContentTypeJSONPatchJSON, jsonPatchSchema)
+
+		resp := s.InvokeTool(InvokeRequest{Tool: tools.ActionUpdate, Arguments: map[string]interface{}{
+			"resource":         "topics",
+			"topic_name":       "orders",
+			"partitions_count": 12,
+			"description":      nil,
+		}})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		if gotContentType != tools.ContentTypeJSONPatchJSON {
+			t.Errorf("Expected Content-Type %s, got %s", tools.ContentTypeJSONPatchJSON, gotContentType)
+		}
+		want := []interface{}{
+			map[string]interface{}{"op": "remove", "path": "/description"},
+			map[string]interface{}{"op": "replace", "path": "/partitions_count", "value": float64(12)},
+		}
+		if !reflect.DeepEqual(gotBody, want) {
+			t.Errorf("Expected JSON patch body %v, got %v", want, gotBody)
+		}
+	})
+
+	t.Run("JSON patch passes explicit operations through", func(t *testing.T) {
+		s := newPatchTestServer(t, apiServer.URL, tools.ContentTypeJSONPatchJSON, jsonPatchSchema)
+
+		operations := []interface{}{
+			map[string]interface{}{"op": "add", "path": "/configs/-", "value": "cleanup.policy=compact"},
+		}
+		resp := s.InvokeTool(InvokeRequest{Tool: tools.ActionUpdate, Arguments: map[string]interface{}{
+			"resource":   "topics",
+			"topic_name": "orders",
+			"operations": operations,
+		}})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		if !reflect.DeepEqual(gotBody, operations) {
+			t.Errorf("Expected explicit operations %v, got %v", operations, gotBody)
+		}
+
+		resp = s.InvokeTool(InvokeRequest{Tool: tools.ActionUpdate, Arguments: map[string]interface{}{
+			"resource":   "topics",
+			"topic_name": "orders",
+			"operations": []interface{}{map[string]interface{}{"path": "/configs"}},
+		}})
+		if resp.Error == "" {
+			t.Error("Expected an operation without 'op' to be rejected")
+		}
+	})
+}
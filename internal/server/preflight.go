@@ -0,0 +1,98 @@
+package server
+
+import (
+	"fmt"
+	"mcolomerc/mcp-server/internal/tools"
+	"os"
+	"sort"
+	"strings"
+)
+
+// serviceEndpointVars are the environment variables of the services whose base URL must be
+// configured; calls to them would otherwise go to the Cloud API
+var serviceEndpointVars = map[string]string{
+	tools.ServiceKafka:          "KAFKA_REST_ENDPOINT",
+	tools.ServiceFlink:          "FLINK_REST_ENDPOINT",
+	tools.ServiceSchemaRegistry: "SCHEMA_REGISTRY_ENDPOINT",
+}
+
+// preflightWarnings resolves, once per service and security type used by the generated tools,
+// the credentials and base URL a call would use, and returns a warning for each service whose
+// tools cannot be called as configured
+func (s *MCPServer) preflightWarnings() []string {
+	type serviceCheck struct {
+		securityType string
+		path         string
+		tools        map[string]bool
+	}
+	checks := make(map[string]*serviceCheck) // service|security type -> check
+	for _, tool := range s.tools {
+		spec := s.spec
+		if tool.Name == tools.TelemetryAction {
+			spec = s.telemetrySpec
+		}
+		for _, mapping := range tools.GetActionMappings(tool.Name) {
+			service := mapping.Service
+			if service == "" {
+				service = tools.ServiceForPath(mapping.PathPattern)
+			}
+			securityType := DetermineSecurityTypeFromSpec(spec, mapping.Method, mapping.PathPattern)
+			key := service + "|" + securityType
+			if checks[key] == nil {
+				checks[key] = &serviceCheck{securityType: securityType, path: mapping.PathPattern, tools: make(map[string]bool)}
+			}
+			checks[key].tools[tool.Name] = true
+		}
+	}
+
+	keys := make([]string, 0, len(checks))
+	for key := range checks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var warnings []string
+	for _, key := range keys {
+		check := checks[key]
+		service := strings.SplitN(key, "|", 2)[0]
+		toolNames := make([]string, 0, len(check.tools))
+		for name := range check.tools {
+			toolNames = append(toolNames, name)
+		}
+		sort.Strings(toolNames)
+
+		if !s.config.UseClientToken {
+			if apiKey, apiSecret := getAPICredentials(s.config, check.securityType, check.path); apiKey == "" || apiSecret == "" {
+				source := credentialSourceFor(check.securityType, check.path)
+				warnings = append(warnings, fmt.Sprintf("%s has tools (%s) but no credentials: set %s and %s",
+					source.Service, strings.Join(toolNames, ", "), source.KeyVar, source.SecretVar))
+			}
+		}
+		if endpointVar, ok := serviceEndpointVars[service]; ok && !s.serviceEndpointConfigured(service) {
+			warnings = append(warnings, fmt.Sprintf("%s has tools (%s) but no endpoint: set %s",
+				service, strings.Join(toolNames, ", "), endpointVar))
+		}
+	}
+	return warnings
+}
+
+// serviceEndpointConfigured reports whether calls to a service have a base URL of their own
+func (s *MCPServer) serviceEndpointConfigured(service string) bool {
+	switch service {
+	case tools.ServiceKafka:
+		return s.config.KafkaRestEndpoint != "" || len(s.config.KafkaClusters) > 0
+	case tools.ServiceFlink:
+		return s.config.FlinkRestEndpoint != ""
+	case tools.ServiceSchemaRegistry:
+		return s.config.SchemaRegistryEndpoint != ""
+	}
+	return true
+}
+
+// logPreflightWarnings checks the credentials and endpoints of the services with tools at
+// startup, so a misconfigured service shows up before its first call fails
+func (s *MCPServer) logPreflightWarnings() {
+	for _, warning := range s.preflightWarnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"strings"
+	"testing"
+)
+
+func TestPreflightWarnings(t *testing.T) {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+	spec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Get: &openapi.Operation{Summary: "List topics"},
+			},
+			"/org/v2/environments": {
+				Get: &openapi.Operation{Summary: "List environments"},
+			},
+		},
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+
+	t.Run("Service with tools but no credentials is reported", func(t *testing.T) {
+		cfg := newTestConfig(t, "http://localhost")
+		cfg.KafkaAPIKey = ""
+		cfg.KafkaAPISecret = ""
+		server := NewCompositeServer(cfg, spec, &openapi.OpenAPISpec{}, semanticTools)
+
+		warnings := server.preflightWarnings()
+		if len(warnings) != 1 {
+			t.Fatalf("Expected one warning, got %v", warnings)
+		}
+		for _, want := range []string{"Kafka", "list", "KAFKA_API_KEY", "KAFKA_API_SECRET"} {
+			if !strings.Contains(warnings[0], want) {
+				t.Errorf("Expected the warning to mention %q, got %q", want, warnings[0])
+			}
+		}
+	})
+
+	t.Run("Service with tools but no endpoint is reported", func(t *testing.T) {
+		cfg := newTestConfig(t, "http://localhost")
+		cfg.KafkaRestEndpoint = ""
+		server := NewCompositeServer(cfg, spec, &openapi.OpenAPISpec{}, semanticTools)
+
+		warnings := server.preflightWarnings()
+		if len(warnings) != 1 || !strings.Contains(warnings[0], "KAFKA_REST_ENDPOINT") {
+			t.Errorf("Expected a warning about KAFKA_REST_ENDPOINT, got %v", warnings)
+		}
+	})
+
+	t.Run("Configured services are not reported", func(t *testing.T) {
+		server := NewCompositeServer(newTestConfig(t, "http://localhost"), spec, &openapi.OpenAPISpec{}, semanticTools)
+		if warnings := server.preflightWarnings(); len(warnings) != 0 {
+			t.Errorf("Expected no warnings, got %v", warnings)
+		}
+	})
+
+	t.Run("Client token mode needs no configured credentials", func(t *testing.T) {
+		cfg := newTestConfig(t, "http://localhost")
+		cfg.KafkaAPIKey = ""
+		cfg.UseClientToken = true
+		server := NewCompositeServer(cfg, spec, &openapi.OpenAPISpec{}, semanticTools)
+		if warnings := server.preflightWarnings(); len(warnings) != 0 {
+			t.Errorf("Expected no warnings, got %v", warnings)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// reproError is a failed API call together with the curl command that repeats it
+type reproError struct {
+	err   error
+	repro string
+}
+
+func (e *reproError) Error() string { return e.err.Error() }
+
+func (e *reproError) Unwrap() error { return e.err }
+
+// reproFromError returns the curl command attached to a failed API call, if any
+func reproFromError(err error) string {
+	var re *reproError
+	if errors.As(err, &re) {
+		return re.repro
+	}
+	return ""
+}
+
+// curlCommand builds a curl command equivalent to an API request. Credentials are never included:
+// the command reads them from the environment variables they are configured with.
+func curlCommand(method, fullURL, contentType, accept string, headers map[string]string, body []byte, bearer bool, source credentialSource) string {
+	parts := []string{"curl", "-X", method, shellQuote(fullURL)}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts = append(parts, "-H", shellQuote(HeaderAccept+": "+accept))
+	if len(body) > 0 {
+		parts = append(parts, "-H", shellQuote(HeaderContentType+": "+contentType))
+	}
+	for _, name := range names {
+		parts = append(parts, "-H", shellQuote(name+": "+headers[name]))
+	}
+
+	if bearer {
+		parts = append(parts, "-H", fmt.Sprintf(`"%s: %s$MCP_CLIENT_TOKEN"`, HeaderAuth, AuthBearerPrefix))
+	} else {
+		parts = append(parts, "-u", fmt.Sprintf(`"$%s:$%s"`, source.KeyVar, source.SecretVar))
+	}
+
+	if len(body) > 0 {
+		parts = append(parts, "-d", shellQuote(string(body)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// reproHeaders returns the headers of a curl command. Headers added from SERVICE_HEADERS may carry
+// gateway tokens, so their values are replaced with ***; the call's own headers are kept.
+func reproHeaders(callHeaders, headers map[string]string) map[string]string {
+	repro := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if _, own := callHeaders[name]; !own {
+			value = "***"
+		}
+		repro[name] = value
+	}
+	return repro
+}
+
+// shellQuote quotes a value for a POSIX shell
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
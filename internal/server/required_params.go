@@ -0,0 +1,146 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"strings"
+)
+
+// RequiredParam describes one argument a call cannot be made without
+type RequiredParam struct {
+	Name        string `json:"name"`
+	In          string `json:"in"` // path, query, header or body
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// GetRequiredParams returns every required argument for an action and resource in one list:
+// the mapping's required path/query parameters followed by the request body's required fields
+func (s *MCPServer) GetRequiredParams(action, resource string) ([]RequiredParam, error) {
+	mapping, spec, err := s.lookupOperationMapping(action, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	var params []RequiredParam
+	seen := make(map[string]bool)
+	add := func(param RequiredParam) {
+		if !seen[param.Name] {
+			seen[param.Name] = true
+			params = append(params, param)
+		}
+	}
+
+	operation := spec.FindOperation(mapping.Method, mapping.PathPattern)
+	for _, name := range mapping.RequiredParams {
+		if operation != nil {
+			if param := findOperationParameter(operation, name); param != nil {
+				required := RequiredParam{Name: name, In: param.In, Description: param.Description}
+				if param.Schema != nil {
+					required.Type = param.Schema.Type
+					if required.Description == "" {
+						required.Description = param.Schema.Description
+					}
+				}
+				add(required)
+				continue
+			}
+		}
+		if strings.Contains(mapping.PathPattern, "{"+name+"}") {
+			add(RequiredParam{Name: name, In: "path", Type: "string"})
+		}
+	}
+
+	// Body fields are read from the schema directly; the mapping only lists them for map schemas
+	if mapping.RequestBodySchema != nil {
+		for _, field := range requiredBodyFields(mapping.RequestBodySchema["schema"]) {
+			add(field)
+		}
+	}
+
+	return params, nil
+}
+
+// describeMissingParams returns the location, type and description of each missing argument
+// as GetRequiredParams reports them; arguments it does not list keep only their name
+func (s *MCPServer) describeMissingParams(action, resource string, missing []string) []RequiredParam {
+	known := make(map[string]RequiredParam)
+	if params, err := s.GetRequiredParams(action, resource); err == nil {
+		for _, param := range params {
+			known[param.Name] = param
+		}
+	}
+
+	described := make([]RequiredParam, 0, len(missing))
+	for _, name := range missing {
+		if param, ok := known[name]; ok {
+			described = append(described, param)
+		} else {
+			described = append(described, RequiredParam{Name: name})
+		}
+	}
+	return described
+}
+
+// findOperationParameter returns the operation parameter with the given name, if any
+func findOperationParameter(operation *openapi.Operation, name string) *openapi.Parameter {
+	for i := range operation.Parameters {
+		if operation.Parameters[i].Name == name {
+			return &operation.Parameters[i]
+		}
+	}
+	return nil
+}
+
+// requiredBodyFields lists the required fields of a request body schema, which is either a
+// parsed *openapi.Schema or the raw map decoded from the spec
+func requiredBodyFields(schema interface{}) []RequiredParam {
+	var fields []RequiredParam
+
+	switch s := schema.(type) {
+	case *openapi.Schema:
+		for _, name := range s.Required {
+			field := RequiredParam{Name: name, In: "body"}
+			if property := s.Properties[name]; property != nil {
+				field.Type = property.Type
+				field.Description = property.Description
+			}
+			fields = append(fields, field)
+		}
+	case map[string]interface{}:
+		for _, name := range schemaRequiredNames(s["required"]) {
+			field := RequiredParam{Name: name, In: "body"}
+			switch properties := s["properties"].(type) {
+			case map[string]interface{}:
+				if property, ok := properties[name].(map[string]interface{}); ok {
+					field.Type, _ = property["type"].(string)
+					field.Description, _ = property["description"].(string)
+				}
+			case map[string]*openapi.Schema:
+				if property := properties[name]; property != nil {
+					field.Type = property.Type
+					field.Description = property.Description
+				}
+			}
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// schemaRequiredNames reads a schema's required list from either its decoded or typed form
+func schemaRequiredNames(required interface{}) []string {
+	switch names := required.(type) {
+	case []string:
+		return names
+	case []interface{}:
+		var result []string
+		for _, name := range names {
+			if s, ok := name.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
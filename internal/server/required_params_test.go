@@ -0,0 +1,125 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"reflect"
+	"testing"
+)
+
+func TestGetRequiredParams(t *testing.T) {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+
+	spec, err := openapi.ParseOpenAPISpecBytes([]byte(`{
+		"openapi": "3.0.0",
+		"paths": {
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				"post": {
+					"parameters": [
+						{"name": "cluster_id", "in": "path", "required": true, "schema": {"type": "string"}},
+						{"name": "validate_only", "in": "query", "required": false, "schema": {"type": "boolean"}}
+					],
+					"requestBody": {"$ref": "#/components/requestBodies/CreateTopicRequest"}
+				}
+			}
+		},
+		"components": {
+			"requestBodies": {
+				"CreateTopicRequest": {
+					"content": {
+						"application/json": {
+							"schema": {
+								"type": "object",
+								"required": ["topic_name", "partitions_count"],
+								"properties": {
+									"topic_name": {"type": "string", "description": "Name of the topic"},
+									"partitions_count": {"type": "integer"},
+									"replication_factor": {"type": "integer"}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+	server := NewCompositeServer(newTestConfig(t, ""), spec, &openapi.OpenAPISpec{}, semanticTools)
+
+	t.Run("Merges path parameters and body required fields", func(t *testing.T) {
+		params, err := server.GetRequiredParams(tools.ActionCreate, "topics")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := []RequiredParam{
+			{Name: "cluster_id", In: "path", Type: "string"},
+			{Name: "topic_name", In: "body", Type: "string", Description: "Name of the topic"},
+			{Name: "partitions_count", In: "body", Type: "integer"},
+		}
+		if len(params) != len(expected) {
+			t.Fatalf("Expected %d required params, got %+v", len(expected), params)
+		}
+		for i, param := range params {
+			if param != expected[i] {
+				t.Errorf("Expected param %d to be %+v, got %+v", i, expected[i], param)
+			}
+		}
+	})
+
+	t.Run("Unknown mapping is an error", func(t *testing.T) {
+		if _, err := server.GetRequiredParams(tools.ActionDelete, "topics"); err == nil {
+			t.Error("Expected an error for an action without a mapping")
+		}
+	})
+}
+
+func TestMissingRequiredParamsDescribed(t *testing.T) {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+
+	spec, err := openapi.ParseOpenAPISpecBytes([]byte(`{
+		"openapi": "3.0.0",
+		"paths": {
+			"/kafka/v3/clusters/{cluster_id}/topics/{topic_name}": {
+				"get": {
+					"parameters": [
+						{"name": "cluster_id", "in": "path", "required": true, "schema": {"type": "string"}},
+						{"name": "topic_name", "in": "path", "required": true, "description": "Name of the topic", "schema": {"type": "string"}},
+						{"name": "revision", "in": "query", "required": true, "schema": {"type": "integer", "description": "Topic revision to read"}}
+					]
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+	server := NewCompositeServer(newTestConfig(t, ""), spec, &openapi.OpenAPISpec{}, semanticTools)
+
+	resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: map[string]interface{}{"resource": "topics"}})
+	result, _ := resp.Result.(map[string]interface{})
+	if result["status"] != "missing_required_params" {
+		t.Fatalf("Expected missing_required_params, got %+v", resp)
+	}
+
+	expected := []RequiredParam{
+		{Name: "topic_name", In: "path", Type: "string", Description: "Name of the topic"},
+		{Name: "revision", In: "query", Type: "integer", Description: "Topic revision to read"},
+	}
+	if !reflect.DeepEqual(result["params"], expected) {
+		t.Errorf("Expected described params %+v, got %+v", expected, result["params"])
+	}
+	if !reflect.DeepEqual(result["requiredParams"], []string{"topic_name", "revision"}) {
+		t.Errorf("Expected the missing names to be kept, got %v", result["requiredParams"])
+	}
+}
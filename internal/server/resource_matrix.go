@@ -0,0 +1,26 @@
+package server
+
+import "mcolomerc/mcp-server/internal/tools"
+
+// ResourceMatrix is the capability matrix of the semantic tools: the resources each action
+// supports and, inverted, the actions each resource supports. Lists are sorted.
+type ResourceMatrix struct {
+	Actions   map[string][]string `json:"actions"`   // action -> resources
+	Resources map[string][]string `json:"resources"` // resource -> actions
+}
+
+// GetResourceMatrix builds the capability matrix from the semantic registry
+func (s *MCPServer) GetResourceMatrix() ResourceMatrix {
+	matrix := ResourceMatrix{
+		Actions:   make(map[string][]string),
+		Resources: make(map[string][]string),
+	}
+	for _, action := range tools.GetSupportedActions() {
+		resources := tools.GetSupportedResources(action)
+		matrix.Actions[action] = resources
+		for _, resource := range resources {
+			matrix.Resources[resource] = append(matrix.Resources[resource], action)
+		}
+	}
+	return matrix
+}
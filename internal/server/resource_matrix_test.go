@@ -0,0 +1,31 @@
+package server
+
+import (
+	"encoding/json"
+	"mcolomerc/mcp-server/internal/tools"
+	"reflect"
+	"testing"
+)
+
+func TestListResourcesTool(t *testing.T) {
+	s := newTopicsTestServer(t, newTestConfig(t, "http://localhost"))
+
+	var matrix ResourceMatrix
+	if err := json.Unmarshal([]byte(callTool(t, s, ListResourcesToolName, nil)), &matrix); err != nil {
+		t.Fatalf("Failed to decode resource matrix: %v", err)
+	}
+
+	for _, action := range tools.GetSupportedActions() {
+		if want := tools.GetSupportedResources(action); !reflect.DeepEqual(matrix.Actions[action], want) {
+			t.Errorf("Expected %s resources %v from the registry, got %v", action, want, matrix.Actions[action])
+		}
+	}
+
+	want := []string{tools.ActionDelete, tools.ActionGet, tools.ActionList, tools.ActionUpdate}
+	if !reflect.DeepEqual(matrix.Resources["topics"], want) {
+		t.Errorf("Expected topics to support %v, got %v", want, matrix.Resources["topics"])
+	}
+	if _, ok := matrix.Actions[tools.ActionCreate]; ok {
+		t.Errorf("Expected no create action without a create operation, got %v", matrix.Actions)
+	}
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"mcolomerc/mcp-server/internal/config"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// GET responses that carry an ETag or Last-Modified header are cached per URL and credentials.
+// A repeated GET revalidates the entry with If-None-Match/If-Modified-Since, and a 304 Not
+// Modified is answered with the cached body, which saves transfer time and rate-limit budget.
+// Like the HTTP client, the cache is shared by all calls.
+var (
+	apiResponseCache      *responseCache
+	apiResponseCacheMutex sync.RWMutex
+)
+
+// cachedResponse is a stored GET response and the validators to revalidate it with
+type cachedResponse struct {
+	body         []byte
+	header       http.Header
+	statusCode   int
+	etag         string
+	lastModified string
+	stored       time.Time
+}
+
+// responseCache holds GET responses for conditional requests
+type responseCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*cachedResponse
+	now        func() time.Time
+}
+
+// newResponseCache returns the cache configured by RESPONSE_CACHE_TTL, or nil when it is disabled
+func newResponseCache(cfg *config.Config) *responseCache {
+	if cfg.ResponseCacheTTLSec <= 0 {
+		return nil
+	}
+	maxEntries := cfg.ResponseCacheMaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultResponseCacheMaxEntries
+	}
+	return &responseCache{
+		ttl:        time.Duration(cfg.ResponseCacheTTLSec) * time.Second,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*cachedResponse),
+		now:        time.Now,
+	}
+}
+
+// setResponseCache replaces the shared response cache
+func setResponseCache(cache *responseCache) {
+	apiResponseCacheMutex.Lock()
+	apiResponseCache = cache
+	apiResponseCacheMutex.Unlock()
+}
+
+// getResponseCache returns the shared response cache, or nil when caching is disabled
+func getResponseCache() *responseCache {
+	apiResponseCacheMutex.RLock()
+	defer apiResponseCacheMutex.RUnlock()
+	return apiResponseCache
+}
+
+// responseCacheKey identifies a cached response by URL and credentials, so responses are never
+// shared between profiles or client tokens. Credentials are hashed rather than kept in memory.
+func responseCacheKey(fullURL, credentials string) string {
+	sum := sha256.Sum256([]byte(credentials))
+	return fullURL + " " + hex.EncodeToString(sum[:])
+}
+
+// get returns the live entry for a key, or nil
+func (c *responseCache) get(key string) *cachedResponse {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if c.now().Sub(entry.stored) > c.ttl {
+		delete(c.entries, key)
+		return nil
+	}
+	return entry
+}
+
+// store keeps a successful GET response if it has a validator to revalidate it with
+func (c *responseCache) store(key string, statusCode int, header http.Header, body []byte) {
+	if c == nil {
+		return
+	}
+	etag, lastModified := header.Get(HeaderETag), header.Get(HeaderLastModified)
+	if etag == "" && lastModified == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		c.evictOldest(now)
+	}
+	c.entries[key] = &cachedResponse{
+		body:         body,
+		header:       header.Clone(),
+		statusCode:   statusCode,
+		etag:         etag,
+		lastModified: lastModified,
+		stored:       now,
+	}
+}
+
+// evictOldest drops expired entries, or the oldest entry if none have expired. Callers hold c.mu.
+func (c *responseCache) evictOldest(now time.Time) {
+	oldestKey := ""
+	for key, entry := range c.entries {
+		if now.Sub(entry.stored) > c.ttl {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.stored.Before(c.entries[oldestKey].stored) {
+			oldestKey = key
+		}
+	}
+	if len(c.entries) >= c.maxEntries && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// conditionalHeaders adds the entry's validators to a copy of the request headers
+func (e *cachedResponse) conditionalHeaders(headers map[string]string) map[string]string {
+	conditional := make(map[string]string, len(headers)+2)
+	for name, value := range headers {
+		conditional[name] = value
+	}
+	if e.etag != "" {
+		conditional[HeaderIfNoneMatch] = e.etag
+	}
+	if e.lastModified != "" {
+		conditional[HeaderIfModifiedSince] = e.lastModified
+	}
+	return conditional
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExecuteAPICallResponseCache(t *testing.T) {
+	var ifNoneMatch []string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get(HeaderIfNoneMatch))
+		if r.Header.Get(HeaderIfNoneMatch) == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(HeaderETag, `"v1"`)
+		w.Write([]byte(`{"subject":"orders-value","version":3}`))
+	}))
+	defer apiServer.Close()
+
+	t.Run("Not modified response serves the cached body", func(t *testing.T) {
+		ifNoneMatch = nil
+		cfg := newTestConfig(t, apiServer.URL)
+		cfg.ResponseCacheTTLSec = 60
+		setResponseCache(newResponseCache(cfg))
+		t.Cleanup(func() { setResponseCache(nil) })
+
+		first, err := ExecuteAPICall(cfg, nil, "GET", "/subjects/orders-value/versions/latest", nil, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var etag string
+		second, err := ExecuteAPICallWithOptions(cfg, nil, "GET", "/subjects/orders-value/versions/latest", nil, nil, APICallOptions{ETag: &etag})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(ifNoneMatch) != 2 || ifNoneMatch[0] != "" || ifNoneMatch[1] != `"v1"` {
+			t.Fatalf("Expected the second request to revalidate with the ETag, got %q", ifNoneMatch)
+		}
+		if second["subject"] != "orders-value" || second["version"] != first["version"] {
+			t.Errorf("Expected the cached body, got %v", second)
+		}
+		if second["status_code"] != http.StatusOK || etag != `"v1"` {
+			t.Errorf("Expected the cached status and ETag, got %v and %q", second, etag)
+		}
+	})
+
+	t.Run("Expired entries are not revalidated", func(t *testing.T) {
+		ifNoneMatch = nil
+		cfg := newTestConfig(t, apiServer.URL)
+		cfg.ResponseCacheTTLSec = 60
+		cache := newResponseCache(cfg)
+		setResponseCache(cache)
+		t.Cleanup(func() { setResponseCache(nil) })
+
+		if _, err := ExecuteAPICall(cfg, nil, "GET", "/subjects/orders-value/versions/latest", nil, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		cache.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		if _, err := ExecuteAPICall(cfg, nil, "GET", "/subjects/orders-value/versions/latest", nil, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(ifNoneMatch) != 2 || ifNoneMatch[1] != "" {
+			t.Errorf("Expected a plain request after expiry, got %q", ifNoneMatch)
+		}
+	})
+
+	t.Run("Disabled cache sends no conditional headers", func(t *testing.T) {
+		ifNoneMatch = nil
+		cfg := newTestConfig(t, apiServer.URL)
+		setResponseCache(newResponseCache(cfg))
+
+		for i := 0; i < 2; i++ {
+			if _, err := ExecuteAPICall(cfg, nil, "GET", "/subjects/orders-value/versions/latest", nil, nil); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		if ifNoneMatch[1] != "" {
+			t.Errorf("Expected no If-None-Match without a cache, got %q", ifNoneMatch[1])
+		}
+	})
+}
+
+func TestResponseCacheEviction(t *testing.T) {
+	cache := &responseCache{ttl: time.Minute, maxEntries: 2, entries: map[string]*cachedResponse{}}
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	header := http.Header{}
+	header.Set(HeaderETag, `"v1"`)
+
+	cache.store("a", http.StatusOK, header, []byte(`{}`))
+	now = now.Add(time.Second)
+	cache.store("b", http.StatusOK, header, []byte(`{}`))
+	now = now.Add(time.Second)
+	cache.store("c", http.StatusOK, header, []byte(`{}`))
+
+	if cache.get("a") != nil || cache.get("b") == nil || cache.get("c") == nil {
+		t.Errorf("Expected the oldest entry to be evicted, got %v", cache.entries)
+	}
+
+	cache.store("d", http.StatusOK, http.Header{}, []byte(`{}`))
+	if cache.get("d") != nil {
+		t.Error("Expected responses without validators not to be cached")
+	}
+}
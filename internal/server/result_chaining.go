@@ -0,0 +1,164 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Result chaining lets a tool argument such as "$last.data[0].id" refer to the previous
+// tool result of the same session, so ids don't have to be copied between calls.
+
+// ResultReferencePrefix marks an argument value as a reference into the last tool result
+const ResultReferencePrefix = "$last"
+
+// MaxChainingSessions bounds how many sessions keep a last result in memory
+const MaxChainingSessions = 100
+
+// resultStore keeps the most recent successful tool result per session
+type resultStore struct {
+	mu      sync.Mutex
+	results map[string]interface{}
+	order   []string // Session IDs, oldest first
+}
+
+func newResultStore() *resultStore {
+	return &resultStore{
+		results: make(map[string]interface{}),
+	}
+}
+
+// set records the last result for a session, evicting the oldest session when full
+func (r *resultStore) set(sessionID string, result interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.results[sessionID]; !exists {
+		r.order = append(r.order, sessionID)
+		if len(r.order) > MaxChainingSessions {
+			delete(r.results, r.order[0])
+			r.order = r.order[1:]
+		}
+	}
+	r.results[sessionID] = result
+}
+
+// get returns the last result recorded for a session
+func (r *resultStore) get(sessionID string) (interface{}, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result, ok := r.results[sessionID]
+	return result, ok
+}
+
+// isResultReference checks whether a value is a "$last..." reference
+func isResultReference(value interface{}) bool {
+	str, ok := value.(string)
+	if !ok || !strings.HasPrefix(str, ResultReferencePrefix) {
+		return false
+	}
+	rest := strings.TrimPrefix(str, ResultReferencePrefix)
+	return rest == "" || strings.HasPrefix(rest, ".") || strings.HasPrefix(rest, "[")
+}
+
+// resolveResultReferences replaces "$last..." references in args (including nested maps and
+// arrays) with values from the last result. It returns an error naming the first reference
+// that cannot be resolved.
+func resolveResultReferences(args map[string]interface{}, last interface{}, hasLast bool) error {
+	for key, value := range args {
+		resolved, err := resolveResultReferenceValue(value, last, hasLast)
+		if err != nil {
+			return fmt.Errorf("argument '%s': %v", key, err)
+		}
+		args[key] = resolved
+	}
+	return nil
+}
+
+// resolveResultReferenceValue resolves a single argument value
+func resolveResultReferenceValue(value interface{}, last interface{}, hasLast bool) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if err := resolveResultReferences(v, last, hasLast); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case []interface{}:
+		for i, item := range v {
+			resolved, err := resolveResultReferenceValue(item, last, hasLast)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+		return v, nil
+	}
+
+	if !isResultReference(value) {
+		return value, nil
+	}
+
+	reference := value.(string)
+	if !hasLast {
+		return nil, fmt.Errorf("cannot resolve '%s': no previous tool result in this session", reference)
+	}
+	resolved, err := lookupResultPath(last, strings.TrimPrefix(reference, ResultReferencePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve '%s': %v", reference, err)
+	}
+	return resolved, nil
+}
+
+// lookupResultPath walks a path such as ".data[0].id" through a decoded JSON value
+func lookupResultPath(value interface{}, path string) (interface{}, error) {
+	current := value
+	for path != "" {
+		switch path[0] {
+		case '.':
+			path = path[1:]
+			end := strings.IndexAny(path, ".[")
+			if end == -1 {
+				end = len(path)
+			}
+			field := path[:end]
+			path = path[end:]
+			if field == "" {
+				return nil, fmt.Errorf("empty field name")
+			}
+
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot read field '%s' of %T", field, current)
+			}
+			next, exists := obj[field]
+			if !exists {
+				return nil, fmt.Errorf("field '%s' not found", field)
+			}
+			current = next
+		case '[':
+			end := strings.Index(path, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("missing closing bracket")
+			}
+			index, err := strconv.Atoi(path[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid index '%s'", path[1:end])
+			}
+			path = path[end+1:]
+
+			arr, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot index %T", current)
+			}
+			if index < 0 || index >= len(arr) {
+				return nil, fmt.Errorf("index %d out of range (length %d)", index, len(arr))
+			}
+			current = arr[index]
+		default:
+			return nil, fmt.Errorf("unexpected '%c' in path", path[0])
+		}
+	}
+	return current, nil
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInvokeToolResolvesResultReferences(t *testing.T) {
+	var requestedPaths []string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/topics") {
+			w.Write([]byte(`{"data":[{"topic_name":"orders"},{"topic_name":"payments"}]}`))
+			return
+		}
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	cfg.EnableResultChaining = true
+	server := newTopicsTestServer(t, cfg)
+
+	t.Run("Reference without a previous result is rejected", func(t *testing.T) {
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, SessionID: "other", Arguments: map[string]interface{}{
+			"resource":   "topics",
+			"topic_name": "$last.data[0].topic_name",
+		}})
+		if !strings.Contains(resp.Error, "no previous tool result") {
+			t.Errorf("Expected missing result error, got %q", resp.Error)
+		}
+	})
+
+	listResp := server.InvokeTool(InvokeRequest{Tool: tools.ActionList, SessionID: "session-1", Arguments: map[string]interface{}{
+		"resource": "topics",
+	}})
+	if listResp.Error != "" {
+		t.Fatalf("Unexpected list error: %s", listResp.Error)
+	}
+
+	t.Run("Reference is resolved from the stored result", func(t *testing.T) {
+		requestedPaths = nil
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, SessionID: "session-1", Arguments: map[string]interface{}{
+			"resource":   "topics",
+			"topic_name": "$last.data[1].topic_name",
+		}})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		if len(requestedPaths) != 1 || !strings.HasSuffix(requestedPaths[0], "/topics/payments") {
+			t.Errorf("Expected request for /topics/payments, got %v", requestedPaths)
+		}
+	})
+
+	t.Run("Reference to a field missing from the last result is rejected", func(t *testing.T) {
+		// The previous get stored a single topic, so there is no data array any more
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, SessionID: "session-1", Arguments: map[string]interface{}{
+			"resource":   "topics",
+			"topic_name": "$last.data[5].topic_name",
+		}})
+		if !strings.Contains(resp.Error, "field 'data' not found") {
+			t.Errorf("Expected unresolved reference error, got %q", resp.Error)
+		}
+	})
+}
+
+func TestLookupResultPath(t *testing.T) {
+	result := map[string]interface{}{
+		"data": []interface{}{
+			map[string]interface{}{"id": "lkc-1", "spec": map[string]interface{}{"region": "us-east-1"}},
+		},
+		"status_code": 200,
+	}
+
+	tests := []struct {
+		path        string
+		expected    interface{}
+		expectError bool
+	}{
+		{path: ".data[0].id", expected: "lkc-1"},
+		{path: ".data[0].spec.region", expected: "us-east-1"},
+		{path: ".status_code", expected: 200},
+		{path: ".data[1].id", expectError: true},
+		{path: ".missing", expectError: true},
+		{path: ".data.id", expectError: true},
+		{path: ".data[x]", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, err := lookupResultPath(result, tt.path)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for path %s, got %v", tt.path, value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if value != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"errors"
+	"mcolomerc/mcp-server/internal/config"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// isRetryableCall decides whether a semantic tool call may be repeated after a transient failure.
+// POST and PATCH requests are not idempotent, so they are only retried when the caller supplied an
+// idempotency key; other requests are retried when their action is configured as retryable.
+func isRetryableCall(cfg *config.Config, action, method string, hasIdempotencyKey bool) bool {
+	if hasIdempotencyKey {
+		return true
+	}
+
+	switch strings.ToUpper(method) {
+	case http.MethodPost, http.MethodPatch:
+		return false
+	}
+
+	actions := cfg.RetryableActions
+	if len(actions) == 0 {
+		actions = DefaultRetryableActions
+	}
+	for _, retryable := range actions {
+		if strings.EqualFold(strings.TrimSpace(retryable), action) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransientFailure reports whether a response or error is worth retrying: network errors
+// and gateway/unavailable responses
+func isTransientFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns how long to wait before the retry following the given attempt
+func retryDelay(cfg *config.Config, attempt int) time.Duration {
+	if cfg.RetryBackoffMs <= 0 {
+		return 0
+	}
+	return time.Duration(cfg.RetryBackoffMs*attempt) * time.Millisecond
+}
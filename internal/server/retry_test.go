@@ -0,0 +1,121 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestInvokeToolRetriesOnlyIdempotentCalls(t *testing.T) {
+	var calls int32
+	var receivedIdempotencyKey string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedIdempotencyKey = r.Header.Get(HeaderIdempotencyKey)
+		// Fail the first attempt of every call with a transient error
+		if atomic.AddInt32(&calls, 1)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+	spec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Get:  &openapi.Operation{Summary: "List topics"},
+				Post: &openapi.Operation{Summary: "Create topic"},
+			},
+		},
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+
+	cfg := newTestConfig(t, apiServer.URL)
+	cfg.MaxRetries = 2
+	cfg.RetryBackoffMs = 1
+	server := NewCompositeServer(cfg, spec, &openapi.OpenAPISpec{}, semanticTools)
+
+	t.Run("POST create is not retried", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionCreate, Arguments: map[string]interface{}{
+			"resource":   "topics",
+			"topic_name": "orders",
+		}})
+		if resp.Error == "" {
+			t.Error("Expected the transient failure to be returned")
+		}
+		if got := atomic.LoadInt32(&calls); got != 1 {
+			t.Errorf("Expected 1 request, got %d", got)
+		}
+	})
+
+	t.Run("GET list is retried", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionList, Arguments: map[string]interface{}{
+			"resource": "topics",
+		}})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		if got := atomic.LoadInt32(&calls); got != 2 {
+			t.Errorf("Expected 2 requests, got %d", got)
+		}
+	})
+
+	t.Run("POST create with an idempotency key is retried", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionCreate, Arguments: map[string]interface{}{
+			"resource":        "topics",
+			"topic_name":      "orders",
+			"idempotency_key": "create-orders-1",
+		}})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		if got := atomic.LoadInt32(&calls); got != 2 {
+			t.Errorf("Expected 2 requests, got %d", got)
+		}
+		if receivedIdempotencyKey != "create-orders-1" {
+			t.Errorf("Expected Idempotency-Key %q, got %q", "create-orders-1", receivedIdempotencyKey)
+		}
+	})
+}
+
+func TestIsRetryableCall(t *testing.T) {
+	tests := []struct {
+		name     string
+		actions  []string
+		action   string
+		method   string
+		hasKey   bool
+		expected bool
+	}{
+		{name: "GET list", action: "list", method: "GET", expected: true},
+		{name: "GET get", action: "get", method: "GET", expected: true},
+		{name: "DELETE delete", action: "delete", method: "DELETE", expected: true},
+		{name: "PUT update", action: "update", method: "PUT", expected: true},
+		{name: "PATCH update", action: "update", method: "PATCH", expected: false},
+		{name: "POST create", action: "create", method: "POST", expected: false},
+		{name: "POST create with idempotency key", action: "create", method: "POST", hasKey: true, expected: true},
+		{name: "Configured actions replace the defaults", actions: []string{"get"}, action: "delete", method: "DELETE", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(t, "")
+			cfg.RetryableActions = tt.actions
+			if got := isRetryableCall(cfg, tt.action, tt.method, tt.hasKey); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"fmt"
+	"mcolomerc/mcp-server/internal/tools"
+)
+
+// SelfTestFailure is an action and resource whose mapping or schema does not resolve
+type SelfTestFailure struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	Error    string `json:"error"`
+}
+
+// SelfTestReport is the outcome of SelfTest
+type SelfTestReport struct {
+	Checked  int               `json:"checked"`
+	Failures []SelfTestFailure `json:"failures,omitempty"`
+}
+
+// Passed reports whether every action and resource resolved
+func (r SelfTestReport) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// SelfTest resolves the endpoint mapping, spec operation and request body schema of every action
+// and resource of the generated tools, the lookups operation_spec serves, so spec regressions
+// surface at startup instead of on the first call
+func (s *MCPServer) SelfTest() SelfTestReport {
+	var report SelfTestReport
+	for _, tool := range s.tools {
+		for _, resource := range tools.GetSupportedResources(tool.Name) {
+			report.Checked++
+			if err := s.selfTestResource(tool.Name, resource); err != nil {
+				report.Failures = append(report.Failures, SelfTestFailure{
+					Action:   tool.Name,
+					Resource: resource,
+					Error:    err.Error(),
+				})
+			}
+		}
+	}
+	return report
+}
+
+// selfTestResource resolves the mapping and schema of one action and resource
+func (s *MCPServer) selfTestResource(action, resource string) error {
+	operationSpec, err := s.GetOperationSpec(action, resource)
+	if err != nil {
+		return err
+	}
+	if operationSpec.Operation.RequestBody == nil {
+		return nil
+	}
+	if operationSpec.RequestBody == nil || len(operationSpec.RequestBody.Content) == 0 {
+		return fmt.Errorf("request body of %s %s could not be resolved", operationSpec.Method, operationSpec.Path)
+	}
+
+	schema, err := tools.ResolveResourceSchema(action, resource)
+	if err != nil {
+		return err
+	}
+	if schema == nil {
+		return fmt.Errorf("request body schema of %s %s could not be resolved", operationSpec.Method, operationSpec.Path)
+	}
+	return nil
+}
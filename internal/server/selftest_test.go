@@ -0,0 +1,40 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/tools"
+	"strings"
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	t.Run("Generated mappings pass", func(t *testing.T) {
+		report := newTopicsTestServer(t, newTestConfig(t, "http://localhost")).SelfTest()
+		if !report.Passed() {
+			t.Fatalf("Expected generated mappings to pass, got failures %v", report.Failures)
+		}
+		if report.Checked != 4 {
+			t.Errorf("Expected 4 action/resource pairs to be checked, got %d", report.Checked)
+		}
+	})
+
+	t.Run("Broken mapping is reported", func(t *testing.T) {
+		s := newTopicsTestServer(t, newTestConfig(t, "http://localhost"))
+
+		// Point the get mapping at a path the spec does not have
+		mapping := tools.GlobalSemanticRegistry.Mappings[tools.ActionGet]["topics"]
+		mapping.PathPattern = "/kafka/v3/clusters/{cluster_id}/topic/{topic_name}"
+		tools.GlobalSemanticRegistry.Mappings[tools.ActionGet]["topics"] = mapping
+
+		report := s.SelfTest()
+		if report.Passed() || len(report.Failures) != 1 {
+			t.Fatalf("Expected exactly the broken mapping to fail, got %v", report.Failures)
+		}
+		failure := report.Failures[0]
+		if failure.Action != tools.ActionGet || failure.Resource != "topics" {
+			t.Errorf("Expected get topics to fail, got %s %s", failure.Action, failure.Resource)
+		}
+		if !strings.Contains(failure.Error, "not found") {
+			t.Errorf("Expected a missing operation error, got %q", failure.Error)
+		}
+	})
+}
@@ -13,7 +13,9 @@ import (
 	"mcolomerc/mcp-server/internal/tools"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -30,6 +32,15 @@ type MCPServer struct {
 	resourceManager *resource.Manager               // Resource management
 	monitor         *monitoring.Monitor             // Resource monitoring
 	guardrails      *guardrails.CompositeGuardrails // Input guardrails (injection + loop detection)
+	lastResults     *resultStore                    // Last tool result per session, for result chaining
+	invocations     *invocationLimiter              // Bounds simultaneous tool invocations
+	continuations   *continuationStore              // Continuation tokens of paginated list calls
+	guardrailsTest  *guardrails.CompositeGuardrails // Separate guardrails for test_guardrails, so samples never affect live loop detection
+	customTools     map[string]tools.Tool           // Handcrafted tools registered with RegisterCustomTool
+	actionAliases   actionAliases                   // Client-facing names of generated tools, from ACTION_ALIASES
+	specLoadedAt    time.Time                       // When the server was built from the loaded specs
+	refreshToken    ClientTokenRefresher            // Refreshes client tokens the API rejects, set with SetClientTokenRefresher
+	droppedTools    map[string]bool                 // Tools left out by MAX_TOOLS
 }
 
 // NewCompositeServer creates an MCPServer with provided config, main spec, telemetry spec and semanticTools
@@ -57,6 +68,10 @@ func NewCompositeServer(cfg *config.Config, spec *openapi.OpenAPISpec, telemetry
 		server.WithLogging(),
 	)
 
+	// Share one pooled HTTP client across all API calls
+	setAPIHTTPClient(newAPIHTTPClient(cfg))
+	setResponseCache(newResponseCache(cfg))
+
 	// Create composite guardrails (injection + loop detection)
 	compositeGuardrails := guardrails.NewCompositeGuardrails(cfg)
 
@@ -69,20 +84,56 @@ func NewCompositeServer(cfg *config.Config, spec *openapi.OpenAPISpec, telemetry
 		promptManager: promptManager,
 		mcpServer:     mcpServer,
 		guardrails:    compositeGuardrails,
+		lastResults:   newResultStore(),
+		invocations:   newInvocationLimiter(cfg.MaxConcurrentInvocations, time.Duration(cfg.InvocationQueueTimeoutSec)*time.Second),
+		continuations: newContinuationStore(time.Duration(cfg.ContinuationTokenTTLSec) * time.Second),
+		actionAliases: newActionAliases(cfg.ActionAliases, semanticTools, ReservedToolNames(cfg)),
+		specLoadedAt:  time.Now(),
 	}
 
 	// Create the resource manager
 	compositeServer.resourceManager = resource.NewManager(compositeServer)
+	compositeServer.resourceManager.SetMIMETypes(cfg.ResourceMIMETypes)
+
+	// Apply MAX_TOOLS to all exposed tools before anything is registered
+	compositeServer.droppedTools = compositeServer.applyMaxTools()
 
 	// Register semantic tools with the MCP server
-	for _, tool := range semanticTools {
-		mcpTool := convertToMCPTool(tool)
-		mcpServer.AddTool(mcpTool, compositeServer.createToolHandler(tool.Name))
+	for _, tool := range compositeServer.tools {
+		mcpServer.AddTool(compositeServer.advertisedTool(tool), compositeServer.createToolHandler(tool.Name))
 	}
 
+	// Warn about services that have tools but missing credentials or endpoints
+	compositeServer.logPreflightWarnings()
+
 	// Add special prompt management tools
 	compositeServer.addPromptManagementTools(mcpServer)
 
+	// Add cross-resource search tool
+	compositeServer.addSearchTool(mcpServer)
+
+	// Add batch invocation tool
+	compositeServer.addBatchTool(mcpServer)
+
+	// Add raw operation spec tool for debugging spec mappings
+	compositeServer.addOperationSpecTool(mcpServer)
+	compositeServer.addRequiredParamsTool(mcpServer)
+
+	// Add a summary of enabled services, features and limits
+	compositeServer.addCapabilitiesTool(mcpServer)
+	compositeServer.addListResourcesTool(mcpServer)
+
+	// Add administrative tools only when explicitly enabled
+	if cfg.EnableAdminTools {
+		compositeServer.guardrailsTest = guardrails.NewCompositeGuardrails(cfg)
+		compositeServer.addTestGuardrailsTool(mcpServer)
+		compositeServer.addAuthDebugTool(mcpServer)
+	}
+	compositeServer.removeDroppedBuiltins()
+
+	// Add handcrafted tools from the CUSTOM_TOOLS file
+	compositeServer.registerConfiguredCustomTools()
+
 	// Register prompts with the MCP server
 	loadedPrompts := promptManager.GetPrompts()
 	fmt.Fprintf(os.Stderr, "Registering %d prompts with MCP server\n", len(loadedPrompts))
@@ -109,10 +160,25 @@ func (s *MCPServer) Start(addr string) error {
 
 	// Start HTTP server using the library's StreamableHTTP server
 	fmt.Fprintf(os.Stderr, "Starting StreamableHTTP server on %s\n", addr)
+	return s.newStreamableHTTPServer(addr).Start(addr)
+}
+
+// newStreamableHTTPServer builds the MCP HTTP server, serving MCP on /mcp and, when admin tools
+// are enabled, the admin endpoints next to it
+func (s *MCPServer) newStreamableHTTPServer(addr string) *server.StreamableHTTPServer {
+	mux := http.NewServeMux()
 	httpServer := server.NewStreamableHTTPServer(s.mcpServer,
 		server.WithEndpointPath("/mcp"),
+		server.WithHTTPContextFunc(clientTokenHTTPContext),
+		server.WithStreamableHTTPServer(&http.Server{Addr: addr, Handler: mux}),
 	)
-	return httpServer.Start(addr)
+	mux.Handle("/mcp", httpServer)
+	if s.config.EnableAdminTools {
+		mux.HandleFunc("/debug/config", s.DebugConfigHandler)
+		mux.HandleFunc("/tools", s.ToolsHandler)
+		mux.HandleFunc("/tools/export", s.ToolsExportHandler)
+	}
+	return httpServer
 }
 
 // StartWithMode starts the server in the specified mode
@@ -123,10 +189,7 @@ func (s *MCPServer) StartWithMode(mode string, addr string) error {
 		return server.ServeStdio(s.mcpServer)
 	case "http":
 		fmt.Fprintf(os.Stderr, "Starting StreamableHTTP server only on %s\n", addr)
-		httpServer := server.NewStreamableHTTPServer(s.mcpServer,
-			server.WithEndpointPath("/mcp"),
-		)
-		return httpServer.Start(addr)
+		return s.newStreamableHTTPServer(addr).Start(addr)
 	case "both":
 		return s.Start(addr)
 	default:
@@ -186,6 +249,14 @@ func (s *MCPServer) GetPromptContentWithSubstitution(name string) (string, error
 	return s.promptManager.GetPromptContentWithSubstitution(name)
 }
 
+// GetPromptMessages returns the messages of a specific prompt with argument overrides, variable substitution and directives
+func (s *MCPServer) GetPromptMessages(name string, args map[string]interface{}) ([]prompts.PromptMessage, error) {
+	if s.promptManager == nil {
+		return nil, fmt.Errorf("prompt manager not initialized")
+	}
+	return s.promptManager.GetPromptMessages(name, args)
+}
+
 // ReloadPrompts reloads all prompts from the configured folder
 func (s *MCPServer) ReloadPrompts() error {
 	if s.promptManager == nil {
@@ -226,6 +297,138 @@ func convertToMCPTool(tool tools.Tool) mcp.Tool {
 	}
 }
 
+// advertisedTool converts a generated tool to the MCP tool advertised to clients, including the
+// per-call arguments the server adds to it
+func (s *MCPServer) advertisedTool(tool tools.Tool) mcp.Tool {
+	mcpTool := convertToMCPTool(tool)
+	mcpTool.Name = s.exposedToolName(tool.Name)
+	addPrettyProperty(&mcpTool)
+	if s.config.AllowBaseURLOverride {
+		addBaseURLOverrideProperty(&mcpTool)
+		if slices.Contains(tool.Services, tools.ServiceSchemaRegistry) {
+			addSchemaRegistryEndpointProperty(&mcpTool)
+		}
+	}
+	if tool.Name == tools.ActionList {
+		addPaginationProperties(&mcpTool)
+	}
+	if tool.Name == tools.TelemetryAction {
+		addTelemetryPaginationProperty(&mcpTool)
+	}
+	return mcpTool
+}
+
+// addPrettyProperty advertises the optional pretty argument, which overrides PRETTY_JSON for one call
+func addPrettyProperty(mcpTool *mcp.Tool) {
+	properties := make(map[string]any, len(mcpTool.InputSchema.Properties)+1)
+	for name, property := range mcpTool.InputSchema.Properties {
+		properties[name] = property
+	}
+	properties[ParamPretty] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Indent the JSON result for readability (true) or keep it compact (false), overriding the server default for this call",
+	}
+	mcpTool.InputSchema.Properties = properties
+}
+
+// addTelemetryPaginationProperty adds the all-pages argument to the telemetry tool schema
+func addTelemetryPaginationProperty(mcpTool *mcp.Tool) {
+	properties := make(map[string]any, len(mcpTool.InputSchema.Properties)+1)
+	for name, property := range mcpTool.InputSchema.Properties {
+		properties[name] = property
+	}
+	properties[ParamAllPages] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Follow links.next or meta.pagination.next_page_token and return the data points of all pages in one result. If a page fails, the pages fetched so far are returned with partial set to true and the error",
+	}
+	mcpTool.InputSchema.Properties = properties
+}
+
+// addBaseURLOverrideProperty advertises the optional base_url_override argument on a tool
+func addBaseURLOverrideProperty(mcpTool *mcp.Tool) {
+	properties := make(map[string]any, len(mcpTool.InputSchema.Properties)+1)
+	for name, property := range mcpTool.InputSchema.Properties {
+		properties[name] = property
+	}
+	properties[ParamBaseURLOverride] = map[string]interface{}{
+		"type":        "string",
+		"description": "Optional base URL to send this call to instead of the configured endpoint (e.g. a staging environment). Must target an allowed host",
+	}
+	mcpTool.InputSchema.Properties = properties
+}
+
+// addSchemaRegistryEndpointProperty advertises the optional schema_registry_endpoint argument on a
+// tool that handles Schema Registry resources
+func addSchemaRegistryEndpointProperty(mcpTool *mcp.Tool) {
+	properties := make(map[string]any, len(mcpTool.InputSchema.Properties)+1)
+	for name, property := range mcpTool.InputSchema.Properties {
+		properties[name] = property
+	}
+	properties[ParamSchemaRegistryEndpoint] = map[string]interface{}{
+		"type":        "string",
+		"description": "Optional Schema Registry endpoint to send Schema Registry calls to instead of the configured one, e.g. another tenant's cluster. Must target an allowed host",
+	}
+	mcpTool.InputSchema.Properties = properties
+}
+
+// addPaginationProperties adds the all-pages, streaming, page-at-a-time and unwrapping arguments to the list tool schema
+func addPaginationProperties(mcpTool *mcp.Tool) {
+	properties := make(map[string]any, len(mcpTool.InputSchema.Properties)+5)
+	for name, property := range mcpTool.InputSchema.Properties {
+		properties[name] = property
+	}
+	properties[ParamAllPages] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Follow the next-page links and return the data of all pages in one result. If a page fails, the pages fetched so far are returned with partial set to true and the error",
+	}
+	properties[ParamStreamPages] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Like all_pages, but send the data of each page to the client as a notifications/list_page notification as soon as it is fetched. The result then only counts the streamed pages and items",
+	}
+	properties[ParamPaginate] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Return a single page plus a continuation_token for the next page instead of the default listing",
+	}
+	properties[ParamContinuationToken] = map[string]interface{}{
+		"type":        "string",
+		"description": "Token from a previous paginated list result; fetches the next page of the same resource",
+	}
+	properties[ParamUnwrapData] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Return the data array of the list response directly, with the remaining envelope (metadata) as a second result",
+	}
+	mcpTool.InputSchema.Properties = properties
+}
+
+// marshalToolResult encodes a tool result as compact JSON, or indented JSON when pretty is set
+func marshalToolResult(result interface{}, pretty bool) ([]byte, error) {
+	if pretty {
+		return json.MarshalIndent(result, "", PrettyJSONIndent)
+	}
+	return json.Marshal(result)
+}
+
+// unwrapDataEnvelope splits a {data: [...], ...} list envelope into the data array and the
+// remaining fields. ok is false for results that are not enveloped.
+func unwrapDataEnvelope(result interface{}) (data []interface{}, envelope map[string]interface{}, ok bool) {
+	resultMap, isMap := result.(map[string]interface{})
+	if !isMap {
+		return nil, nil, false
+	}
+	data, ok = resultMap["data"].([]interface{})
+	if !ok {
+		return nil, nil, false
+	}
+
+	envelope = make(map[string]interface{}, len(resultMap)-1)
+	for key, value := range resultMap {
+		if key != "data" {
+			envelope[key] = value
+		}
+	}
+	return data, envelope, true
+}
+
 // createToolHandler creates a tool handler function for the MCP server
 func (s *MCPServer) createToolHandler(toolName string) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -241,26 +444,79 @@ func (s *MCPServer) createToolHandler(toolName string) func(context.Context, mcp
 			}, nil
 		}
 
+		// A per-call 'pretty' argument overrides PRETTY_JSON and is not sent to the API
+		pretty := s.config.PrettyJSON
+		if value, ok := args[ParamPretty].(bool); ok {
+			pretty = value
+		}
+		delete(args, ParamPretty)
+
+		// A per-call 'unwrap_data' argument overrides UNWRAP_DATA for list results
+		unwrap := s.config.UnwrapData && toolName == tools.ActionList
+		if value, ok := args[ParamUnwrapData].(bool); ok {
+			unwrap = value && toolName == tools.ActionList
+		}
+		delete(args, ParamUnwrapData)
+
+		// A per-call 'stream_pages' argument sends the pages of a list to the client as they are fetched
+		streamPages, _ := args[ParamStreamPages].(bool)
+		delete(args, ParamStreamPages)
+
 		invokeReq := InvokeRequest{
 			Tool:      toolName,
 			Arguments: args,
+			Context:   ctx,
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			invokeReq.SessionID = session.SessionID()
+			if streamPages && toolName == tools.ActionList {
+				resource, _ := args["resource"].(string)
+				invokeReq.PageWriter = s.listPageNotifier(ctx, resource)
+			}
+		}
+		if s.config.UseClientToken {
+			invokeReq.ClientToken = clientTokenFromContext(ctx)
 		}
 		resp := s.InvokeTool(invokeReq)
 
 		if resp.Error != "" {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: " + resp.Error,
-					},
+			content := []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: "Error: " + resp.Error,
 				},
-			}, nil
+			}
+			// A loop cooldown follows as JSON so clients can back off without parsing the message
+			if resp.Cooldown != nil {
+				if cooldownJSON, err := marshalToolResult(resp.Cooldown, pretty); err == nil {
+					content = append(content, mcp.TextContent{
+						Type: "text",
+						Text: string(cooldownJSON),
+					})
+				}
+			}
+			// Field errors follow as JSON so clients can fix the rejected fields
+			if len(resp.FieldErrors) > 0 {
+				if fieldErrorsJSON, err := marshalToolResult(map[string]interface{}{ResultFieldFieldErrors: resp.FieldErrors}, pretty); err == nil {
+					content = append(content, mcp.TextContent{
+						Type: "text",
+						Text: string(fieldErrorsJSON),
+					})
+				}
+			}
+			if resp.Repro != "" {
+				content = append(content, mcp.TextContent{
+					Type: "text",
+					Text: "Repro: " + resp.Repro,
+				})
+			}
+			return &mcp.CallToolResult{Content: content}, nil
 		}
 
 		// If this was a successful create operation, register the new resource
+		var resourceURI string
 		if toolName == tools.ActionCreate {
-			s.resourceManager.HandleResourceCreation(s.mcpServer, args, resp.Result)
+			resourceURI = s.resourceManager.HandleResourceCreation(s.mcpServer, args, resp.Result)
 		}
 
 		// If this was a successful delete operation, unregister the resource
@@ -268,52 +524,103 @@ func (s *MCPServer) createToolHandler(toolName string) func(context.Context, mcp
 			s.resourceManager.HandleResourceDeletion(args)
 		}
 
-		resultJSON, err := json.Marshal(resp.Result)
-		if err != nil {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Failed to format result",
+		// Unwrapped list results return the data array first and the rest of the envelope
+		// (metadata, status_code, ...) as a second item
+		var results []interface{}
+		if data, envelope, ok := unwrapDataEnvelope(resp.Result); unwrap && ok {
+			results = []interface{}{data}
+			if len(envelope) > 0 {
+				results = append(results, envelope)
+			}
+		} else {
+			results = []interface{}{resp.Result}
+		}
+
+		// The API result is always the first content item; warnings follow as separate items
+		var content []mcp.Content
+		for _, result := range results {
+			resultJSON, err := marshalToolResult(result, pretty)
+			if err != nil {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						mcp.TextContent{
+							Type: "text",
+							Text: "Failed to format result",
+						},
 					},
-				},
-			}, nil
+				}, nil
+			}
+			content = append(content, mcp.TextContent{
+				Type: "text",
+				Text: string(resultJSON),
+			})
+		}
+		// The URI of a registered resource lets clients read it back right away
+		if resourceURI != "" {
+			content = append(content, mcp.TextContent{
+				Type: "text",
+				Text: "Resource URI: " + resourceURI,
+			})
+		}
+		// The ETag follows so clients can pass it as if_match on the next update
+		if resp.ETag != "" {
+			content = append(content, mcp.TextContent{
+				Type: "text",
+				Text: "ETag: " + resp.ETag,
+			})
+		}
+		for _, warning := range resp.Warnings {
+			content = append(content, mcp.TextContent{
+				Type: "text",
+				Text: "Warning: " + warning,
+			})
 		}
 
 		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: string(resultJSON),
-				},
-			},
+			Content: content,
 		}, nil
 	}
 }
 
-// createPromptHandler creates a prompt handler function for the MCP server
+// createPromptHandler creates a prompt handler function for the MCP server. Request arguments
+// override the configured defaults; template prompts return one message per template message.
 func (s *MCPServer) createPromptHandler(promptName string) func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
 	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
-		content, err := s.GetPromptContentWithSubstitution(promptName)
+		args := make(map[string]interface{}, len(request.Params.Arguments))
+		for name, value := range request.Params.Arguments {
+			args[name] = value
+		}
+		messages, err := s.GetPromptMessages(promptName, args)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get prompt content: %w", err)
 		}
 
-		return &mcp.GetPromptResult{
+		result := &mcp.GetPromptResult{
 			Description: fmt.Sprintf("Prompt: %s", promptName),
-			Messages: []mcp.PromptMessage{
-				{
-					Role: "user",
-					Content: mcp.TextContent{
-						Type: "text",
-						Text: content,
-					},
+			Messages:    make([]mcp.PromptMessage, 0, len(messages)),
+		}
+		for _, message := range messages {
+			result.Messages = append(result.Messages, mcp.PromptMessage{
+				Role: promptMessageRole(message.Role),
+				Content: mcp.TextContent{
+					Type: "text",
+					Text: message.Content,
 				},
-			},
-		}, nil
+			})
+		}
+		return result, nil
 	}
 }
 
+// promptMessageRole maps a template role to MCP, which has no system role: system messages are
+// sent as user messages, as directives are
+func promptMessageRole(role string) mcp.Role {
+	if role == prompts.RoleAssistant {
+		return mcp.RoleAssistant
+	}
+	return mcp.RoleUser
+}
+
 // addPromptManagementTools adds special tools for managing prompts
 func (s *MCPServer) addPromptManagementTools(mcpServer *server.MCPServer) {
 	// Tool to list available prompts
@@ -432,6 +739,221 @@ func (s *MCPServer) addPromptManagementTools(mcpServer *server.MCPServer) {
 	})
 }
 
+// addSearchTool adds a tool for finding resource instances by name across resource types
+func (s *MCPServer) addSearchTool(mcpServer *server.MCPServer) {
+	searchSchema := mcp.ToolInputSchema{
+		Type: "object",
+		Properties: map[string]any{
+			"query": map[string]any{
+				"type":        "string",
+				"description": "Substring to match against resource ids and names (case-insensitive)",
+			},
+			"resource_types": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "Optional resource types to search (e.g. topics, connectors). Defaults to all listable resource types",
+			},
+		},
+		Required: []string{"query"},
+	}
+
+	searchTool := mcp.Tool{
+		Name:        "search",
+		Description: fmt.Sprintf("Search resource instances by id or name substring across resource types (max %d results)", resource.MaxSearchResults),
+		InputSchema: searchSchema,
+	}
+
+	mcpServer.AddTool(searchTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: Invalid arguments format",
+					},
+				},
+			}, nil
+		}
+
+		query, ok := args["query"].(string)
+		if !ok || strings.TrimSpace(query) == "" {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: 'query' parameter is required and must be a non-empty string",
+					},
+				},
+			}, nil
+		}
+
+		var resourceTypes []string
+		if types, ok := args["resource_types"].([]interface{}); ok {
+			for _, t := range types {
+				if typeName, ok := t.(string); ok && typeName != "" {
+					resourceTypes = append(resourceTypes, typeName)
+				}
+			}
+		}
+
+		searchResult := s.resourceManager.Search(strings.TrimSpace(query), resourceTypes)
+
+		resultJSON, err := marshalToolResult(searchResult, s.config.PrettyJSON)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Failed to format result",
+					},
+				},
+			}, nil
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: string(resultJSON),
+				},
+			},
+		}, nil
+	})
+}
+
+// addBatchTool adds a tool for running one semantic tool over a list of argument sets
+func (s *MCPServer) addBatchTool(mcpServer *server.MCPServer) {
+	var actions []string
+	for _, tool := range s.tools {
+		if tools.IsSemanticAction(tool.Name) {
+			actions = append(actions, s.exposedToolName(tool.Name))
+		}
+	}
+
+	batchSchema := mcp.ToolInputSchema{
+		Type: "object",
+		Properties: map[string]any{
+			"tool": map[string]any{
+				"type":        "string",
+				"description": "The semantic tool to run for each item (e.g. create, delete)",
+				"enum":        actions,
+			},
+			"resource": map[string]any{
+				"type":        "string",
+				"description": "The resource type every item applies to",
+			},
+			"items": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "object"},
+				"description": fmt.Sprintf("Arguments for each invocation (max %d)", BatchMaxItems),
+			},
+			"concurrency": map[string]any{
+				"type":        "integer",
+				"description": fmt.Sprintf("How many items to run at once (default 1, sequential; max %d)", BatchMaxConcurrency),
+			},
+		},
+		Required: []string{"tool", "resource", "items"},
+	}
+
+	batchTool := mcp.Tool{
+		Name:        BatchToolName,
+		Description: "Run a semantic tool over a list of argument sets as one logical call, returning a result or error per item",
+		InputSchema: batchSchema,
+	}
+
+	mcpServer.AddTool(batchTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: Invalid arguments format",
+					},
+				},
+			}, nil
+		}
+
+		batchReq := BatchRequest{Context: ctx}
+		batchReq.Tool, _ = args["tool"].(string)
+		batchReq.Resource, _ = args["resource"].(string)
+		if concurrency, ok := args["concurrency"].(float64); ok {
+			batchReq.Concurrency = int(concurrency)
+		}
+		if items, ok := args["items"].([]interface{}); ok {
+			for _, item := range items {
+				itemArgs, ok := item.(map[string]interface{})
+				if !ok {
+					return &mcp.CallToolResult{
+						Content: []mcp.Content{
+							mcp.TextContent{
+								Type: "text",
+								Text: "Error: every entry in 'items' must be an object of arguments",
+							},
+						},
+					}, nil
+				}
+				batchReq.Items = append(batchReq.Items, itemArgs)
+			}
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			batchReq.SessionID = session.SessionID()
+		}
+		if s.config.UseClientToken {
+			batchReq.ClientToken = clientTokenFromContext(ctx)
+		}
+
+		batchResult, err := s.InvokeBatch(batchReq)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: " + err.Error(),
+					},
+				},
+			}, nil
+		}
+
+		// Keep registered resources in sync, as for individual create/delete calls
+		for _, item := range batchResult.Items {
+			if item.Error != "" {
+				continue
+			}
+			itemArgs := batchReq.Items[item.Index]
+			itemArgs["resource"] = batchReq.Resource
+			switch batchReq.Tool {
+			case tools.ActionCreate:
+				s.resourceManager.HandleResourceCreation(s.mcpServer, itemArgs, item.Result)
+			case tools.ActionDelete:
+				s.resourceManager.HandleResourceDeletion(itemArgs)
+			}
+		}
+
+		resultJSON, err := marshalToolResult(batchResult, s.config.PrettyJSON)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Failed to format result",
+					},
+				},
+			}, nil
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: string(resultJSON),
+				},
+			},
+		}, nil
+	})
+}
+
 // RegisterMetricsHandlers registers HTTP handlers for metrics
 func (s *MCPServer) RegisterMetricsHandlers(mux *http.ServeMux) {
 	if s.monitor == nil {
@@ -457,4 +979,351 @@ func (s *MCPServer) RegisterMetricsHandlers(mux *http.ServeMux) {
 // SetMonitor sets the resource monitor for the server
 func (s *MCPServer) SetMonitor(monitor *monitoring.Monitor) {
 	s.monitor = monitor
+	if monitor != nil && s.invocations != nil {
+		monitor.SetInvocationMetricsProvider(s.invocations.stats)
+	}
+	if monitor != nil {
+		monitor.SetCoverageMetricsProvider(s.coverageMetrics)
+		monitor.SetInventoryMetricsProvider(s.inventoryMetrics)
+	}
+}
+
+// addOperationSpecTool adds a tool that returns the raw OpenAPI operation behind an action and resource
+func (s *MCPServer) addOperationSpecTool(mcpServer *server.MCPServer) {
+	var actions []string
+	for _, tool := range s.tools {
+		if tools.IsSemanticAction(tool.Name) || tool.Name == "get_telemetry" {
+			actions = append(actions, s.exposedToolName(tool.Name))
+		}
+	}
+
+	operationSpecSchema := mcp.ToolInputSchema{
+		Type: "object",
+		Properties: map[string]any{
+			"action": map[string]any{
+				"type":        "string",
+				"description": "The semantic tool whose endpoint to inspect (e.g. create, list)",
+				"enum":        actions,
+			},
+			"resource": map[string]any{
+				"type":        "string",
+				"description": "The resource type, e.g. topics",
+			},
+		},
+		Required: []string{"action", "resource"},
+	}
+
+	operationSpecTool := mcp.Tool{
+		Name:        OperationSpecToolName,
+		Description: "Show the raw OpenAPI operation (summary, parameters, request body schema, security) an action and resource are mapped to, for debugging spec issues",
+		InputSchema: operationSpecSchema,
+	}
+
+	mcpServer.AddTool(operationSpecTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: Invalid arguments format",
+					},
+				},
+			}, nil
+		}
+
+		action, _ := args["action"].(string)
+		action = s.canonicalToolName(action)
+		resourceType, _ := args["resource"].(string)
+		if action == "" || resourceType == "" {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: 'action' and 'resource' parameters are required",
+					},
+				},
+			}, nil
+		}
+
+		operationSpec, err := s.GetOperationSpec(action, resourceType)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: " + err.Error(),
+					},
+				},
+			}, nil
+		}
+
+		resultJSON, err := marshalToolResult(operationSpec, s.config.PrettyJSON)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Failed to format result",
+					},
+				},
+			}, nil
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: string(resultJSON),
+				},
+			},
+		}, nil
+	})
+}
+
+// addRequiredParamsTool adds a preflight tool that lists all required arguments of an action and
+// resource, including request body fields, so clients can fill them in before the first call
+func (s *MCPServer) addRequiredParamsTool(mcpServer *server.MCPServer) {
+	var actions []string
+	for _, tool := range s.tools {
+		if tools.IsSemanticAction(tool.Name) || tool.Name == "get_telemetry" {
+			actions = append(actions, s.exposedToolName(tool.Name))
+		}
+	}
+
+	requiredParamsSchema := mcp.ToolInputSchema{
+		Type: "object",
+		Properties: map[string]any{
+			"action": map[string]any{
+				"type":        "string",
+				"description": "The semantic tool to be called (e.g. create, update)",
+				"enum":        actions,
+			},
+			"resource": map[string]any{
+				"type":        "string",
+				"description": "The resource type, e.g. topics",
+			},
+		},
+		Required: []string{"action", "resource"},
+	}
+
+	requiredParamsTool := mcp.Tool{
+		Name:        RequiredParamsToolName,
+		Description: "List every required argument (path, query and request body fields, with types) for an action and resource before calling it",
+		InputSchema: requiredParamsSchema,
+	}
+
+	mcpServer.AddTool(requiredParamsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: Invalid arguments format",
+					},
+				},
+			}, nil
+		}
+
+		action, _ := args["action"].(string)
+		action = s.canonicalToolName(action)
+		resourceType, _ := args["resource"].(string)
+		if action == "" || resourceType == "" {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: 'action' and 'resource' parameters are required",
+					},
+				},
+			}, nil
+		}
+
+		requiredParams, err := s.GetRequiredParams(action, resourceType)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: " + err.Error(),
+					},
+				},
+			}, nil
+		}
+
+		resultJSON, err := marshalToolResult(map[string]interface{}{
+			"action":   action,
+			"resource": resourceType,
+			"required": requiredParams,
+		}, s.config.PrettyJSON)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Failed to format result",
+					},
+				},
+			}, nil
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: string(resultJSON),
+				},
+			},
+		}, nil
+	})
+}
+
+// addCapabilitiesTool adds a tool that summarizes what the server supports
+func (s *MCPServer) addCapabilitiesTool(mcpServer *server.MCPServer) {
+	capabilitiesTool := mcp.Tool{
+		Name:        CapabilitiesToolName,
+		Description: "Summarize the enabled services, guardrails, retries, pagination modes, features and limits of this server",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]any{},
+		},
+	}
+
+	mcpServer.AddTool(capabilitiesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resultJSON, err := marshalToolResult(s.GetCapabilities(), s.config.PrettyJSON)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Failed to format result",
+					},
+				},
+			}, nil
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: string(resultJSON),
+				},
+			},
+		}, nil
+	})
+}
+
+// addListResourcesTool adds a tool that returns the resources of every action and the actions of
+// every resource in one call
+func (s *MCPServer) addListResourcesTool(mcpServer *server.MCPServer) {
+	listResourcesTool := mcp.Tool{
+		Name:        ListResourcesToolName,
+		Description: "List the resources each action supports and the actions each resource supports",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]any{},
+		},
+	}
+
+	mcpServer.AddTool(listResourcesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resultJSON, err := marshalToolResult(s.GetResourceMatrix(), s.config.PrettyJSON)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Failed to format result",
+					},
+				},
+			}, nil
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: string(resultJSON),
+				},
+			},
+		}, nil
+	})
+}
+
+// addTestGuardrailsTool adds an admin tool that runs the guardrails on a sample tool call and
+// returns the full result, so injection patterns and loop policies can be verified safely
+func (s *MCPServer) addTestGuardrailsTool(mcpServer *server.MCPServer) {
+	testGuardrailsSchema := mcp.ToolInputSchema{
+		Type: "object",
+		Properties: map[string]any{
+			"tool_name": map[string]any{
+				"type":        "string",
+				"description": "The tool the sample call is made to (e.g. create)",
+			},
+			"args": map[string]any{
+				"type":        "object",
+				"description": "The sample arguments to check",
+			},
+		},
+		Required: []string{"tool_name", "args"},
+	}
+
+	testGuardrailsTool := mcp.Tool{
+		Name:        TestGuardrailsToolName,
+		Description: "Run the injection and loop guardrails on a sample tool call and show the detected patterns, severity and loop status. No API call is made, and samples are tracked separately from real calls",
+		InputSchema: testGuardrailsSchema,
+	}
+
+	mcpServer.AddTool(testGuardrailsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: Invalid arguments format",
+					},
+				},
+			}, nil
+		}
+
+		toolName, _ := args["tool_name"].(string)
+		sampleArgs, argsOK := args["args"].(map[string]interface{})
+		if toolName == "" || !argsOK {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Error: 'tool_name' and 'args' parameters are required",
+					},
+				},
+			}, nil
+		}
+
+		guardrailsResult := s.guardrailsTest.ValidateToolInput(toolName, sampleArgs)
+
+		resultJSON, err := marshalToolResult(map[string]interface{}{
+			"tool_name": toolName,
+			"result":    guardrailsResult,
+		}, s.config.PrettyJSON)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "Failed to format result",
+					},
+				},
+			}, nil
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: string(resultJSON),
+				},
+			},
+		}, nil
+	})
 }
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"context"
+	"mcolomerc/mcp-server/internal/types"
+)
+
+// NotificationListPage is the notification carrying one page of a list called with
+// "stream_pages": true. Over streamable HTTP the first one upgrades the call's response to an
+// SSE stream, so clients receive each page as soon as it is fetched.
+const NotificationListPage = "notifications/list_page"
+
+// Parameters of a list page notification
+const (
+	NotificationParamResource = "resource"
+	NotificationParamPage     = "page"
+	NotificationParamData     = "data"
+)
+
+// listPageNotifier returns a page writer that sends each page of a list to the calling client
+// as a list page notification
+func (s *MCPServer) listPageNotifier(ctx context.Context, resource string) types.PageWriter {
+	return func(page int, data []interface{}) error {
+		return s.mcpServer.SendNotificationToClient(ctx, NotificationListPage, map[string]any{
+			NotificationParamResource: resource,
+			NotificationParamPage:     page,
+			NotificationParamData:     data,
+		})
+	}
+}
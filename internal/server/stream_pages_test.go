@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// testClientSession is an initialized client session that buffers its notifications
+type testClientSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testClientSession) Initialize()       {}
+func (s *testClientSession) Initialized() bool { return true }
+func (s *testClientSession) SessionID() string { return "stream-session" }
+func (s *testClientSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func TestListStreamPages(t *testing.T) {
+	var apiServer *httptest.Server
+	apiServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page_token") {
+		case "":
+			fmt.Fprintf(w, `{"data":[{"topic_name":"a"},{"topic_name":"b"}],"metadata":{"next":"%s/kafka/v3/clusters/lkc-test/topics?page_token=p2"}}`, apiServer.URL)
+		case "p2":
+			fmt.Fprintf(w, `{"data":[{"topic_name":"c"}],"metadata":{"next":"%s/kafka/v3/clusters/lkc-test/topics?page_token=p3"}}`, apiServer.URL)
+		case "p3":
+			w.Write([]byte(`{"data":[{"topic_name":"d"}],"metadata":{"next":""}}`))
+		}
+	}))
+	defer apiServer.Close()
+
+	pageTopics := func(data []interface{}) string {
+		var names []string
+		for _, item := range data {
+			names = append(names, item.(map[string]interface{})["topic_name"].(string))
+		}
+		return strings.Join(names, ",")
+	}
+
+	t.Run("Each page is written as it is fetched", func(t *testing.T) {
+		server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+		var chunks []string
+		resp := server.InvokeTool(InvokeRequest{
+			Tool:      tools.ActionList,
+			Arguments: map[string]interface{}{"resource": "topics"},
+			PageWriter: func(page int, data []interface{}) error {
+				chunks = append(chunks, fmt.Sprintf("%d:%s", page, pageTopics(data)))
+				return nil
+			},
+		})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		if got := strings.Join(chunks, " "); got != "1:a,b 2:c 3:d" {
+			t.Errorf("Expected one chunk per page, got %s", got)
+		}
+		result := resp.Result.(map[string]interface{})
+		if data, _ := result["data"].([]interface{}); len(data) != 0 {
+			t.Errorf("Expected the streamed data not to be buffered, got %v", data)
+		}
+		if result[ResultFieldStreamedPages] != 3 || result[ResultFieldStreamedItems] != 4 {
+			t.Errorf("Expected 3 pages and 4 items streamed, got %v", result)
+		}
+	})
+
+	t.Run("A failing writer stops the listing", func(t *testing.T) {
+		server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+		resp := server.InvokeTool(InvokeRequest{
+			Tool:      tools.ActionList,
+			Arguments: map[string]interface{}{"resource": "topics"},
+			PageWriter: func(page int, data []interface{}) error {
+				if page == 2 {
+					return fmt.Errorf("client went away")
+				}
+				return nil
+			},
+		})
+		result := resp.Result.(map[string]interface{})
+		if result[ResultFieldPartial] != true || result[ResultFieldStreamedPages] != 1 || len(resp.Warnings) == 0 {
+			t.Errorf("Expected a partial result after 1 page with a warning, got %v warnings=%v", result, resp.Warnings)
+		}
+		if next, _ := result["metadata"].(map[string]interface{})["next"].(string); !strings.Contains(next, "page_token=p2") {
+			t.Errorf("Expected metadata.next to link the unsent page, got %q", next)
+		}
+	})
+
+	t.Run("stream_pages sends a notification per page", func(t *testing.T) {
+		server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+		session := &testClientSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+		ctx := server.mcpServer.WithContext(context.Background(), session)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"resource": "topics", "stream_pages": true}
+		result, err := server.createToolHandler(tools.ActionList)(ctx, request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		close(session.notifications)
+
+		var chunks []string
+		for notification := range session.notifications {
+			if notification.Method != NotificationListPage {
+				t.Fatalf("Expected a %s notification, got %s", NotificationListPage, notification.Method)
+			}
+			params := notification.Params.AdditionalFields
+			data, _ := params[NotificationParamData].([]interface{})
+			chunks = append(chunks, fmt.Sprintf("%s %v:%s", params[NotificationParamResource], params[NotificationParamPage], pageTopics(data)))
+		}
+		if got := strings.Join(chunks, " | "); got != "topics 1:a,b | topics 2:c | topics 3:d" {
+			t.Errorf("Expected one notification per page, got %s", got)
+		}
+
+		text, _ := result.Content[0].(mcp.TextContent)
+		var summary map[string]interface{}
+		if err := json.Unmarshal([]byte(text.Text), &summary); err != nil {
+			t.Fatalf("Expected a JSON result, got %q", text.Text)
+		}
+		if summary[ResultFieldStreamedItems] != float64(4) {
+			t.Errorf("Expected the result to count 4 streamed items, got %v", summary)
+		}
+	})
+}
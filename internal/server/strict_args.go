@@ -0,0 +1,100 @@
+package server
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"slices"
+	"sort"
+)
+
+// strictControlArguments are arguments the server understands on any semantic call, besides the
+// parameters of the operation itself. Per-call options such as profile are consumed before the
+// strict check runs, so they need no entry here.
+var strictControlArguments = []string{
+	"resource",
+	"parameters",
+	ParamName,
+	ParamEnvironment, ParamEnvironmentID,
+	ParamClusterID, ParamKafkaClusterID,
+	ParamComputePoolID, ParamPoolID,
+	ParamOrganizationID, ParamOrgID, ParamOrg,
+	ParamSchemaRegistryEndpoint,
+	ParamPatchOperations,
+}
+
+// perCallArguments are the server's per-call options. They configure the call rather than the
+// operation, so they are never part of a request body.
+var perCallArguments = []string{
+	ParamPretty, ParamUnwrapData,
+	ParamProfile,
+	ParamIdempotencyKey,
+	ParamIfMatch, ParamETag,
+	ParamPaginate, ParamContinuationToken, ParamAllPages, ParamStreamPages,
+	ParamBaseURLOverride,
+}
+
+// unknownArguments lists, sorted, the call arguments that are not path, query or header parameters
+// of the operation, request body properties, or control arguments. The operation's own arguments
+// are returned as the second value for the error message.
+func unknownArguments(spec *openapi.OpenAPISpec, mapping *tools.EndpointMapping, pathPattern string, args map[string]interface{}) (unknown []string, known []string) {
+	knownSet := map[string]bool{}
+	for _, name := range tools.ExtractPathParameters(pathPattern) {
+		knownSet[name] = true
+	}
+	for _, name := range mapping.RequiredParams {
+		knownSet[name] = true
+	}
+	for _, name := range mapping.OptionalParams {
+		knownSet[name] = true
+	}
+	if operation := spec.FindOperation(mapping.Method, pathPattern); operation != nil {
+		for _, param := range operation.Parameters {
+			knownSet[param.Name] = true
+		}
+	}
+	bodyProperties := requestBodyPropertyNames(mapping.RequestBodySchema["schema"])
+	for _, name := range bodyProperties {
+		knownSet[name] = true
+	}
+
+	for name := range knownSet {
+		known = append(known, name)
+	}
+	sort.Strings(known)
+
+	for name := range args {
+		if knownSet[name] || slices.Contains(strictControlArguments, name) {
+			continue
+		}
+		// Friendly names such as partitions are mapped onto body properties when the body is built
+		if slices.ContainsFunc(bodyProperties, func(property string) bool { return mapArgumentToProperty(name, property) }) {
+			continue
+		}
+		unknown = append(unknown, name)
+	}
+	sort.Strings(unknown)
+
+	return unknown, known
+}
+
+// requestBodyPropertyNames lists the top-level properties of a request body schema, which is either
+// a parsed *openapi.Schema or the raw map decoded from the spec
+func requestBodyPropertyNames(schema interface{}) []string {
+	var names []string
+	switch s := schema.(type) {
+	case *openapi.Schema:
+		names = getSchemaPropertyNames(s)
+	case map[string]interface{}:
+		switch properties := s["properties"].(type) {
+		case map[string]interface{}:
+			for name := range properties {
+				names = append(names, name)
+			}
+		case map[string]*openapi.Schema:
+			for name := range properties {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
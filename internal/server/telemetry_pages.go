@@ -0,0 +1,109 @@
+package server
+
+import (
+	"fmt"
+	"mcolomerc/mcp-server/internal/tools"
+)
+
+// telemetryNextPage returns how to fetch the page after a telemetry result: the links.next URL
+// of descriptor listings, or the meta.pagination.next_page_token of metric queries
+func telemetryNextPage(result map[string]interface{}) (link, token string) {
+	if links, ok := result["links"].(map[string]interface{}); ok {
+		link, _ = links["next"].(string)
+	}
+	if meta, ok := result["meta"].(map[string]interface{}); ok {
+		if pagination, ok := meta["pagination"].(map[string]interface{}); ok {
+			token, _ = pagination["next_page_token"].(string)
+		}
+	}
+	return link, token
+}
+
+// setTelemetryNextPage records the page after an aggregated telemetry result, clearing both
+// fields once the last page has been fetched
+func setTelemetryNextPage(result map[string]interface{}, link, token string) {
+	if links, ok := result["links"].(map[string]interface{}); ok {
+		if link != "" {
+			links["next"] = link
+		} else {
+			delete(links, "next")
+		}
+	}
+	if meta, ok := result["meta"].(map[string]interface{}); ok {
+		if pagination, ok := meta["pagination"].(map[string]interface{}); ok {
+			if token != "" {
+				pagination["next_page_token"] = token
+			} else {
+				delete(pagination, "next_page_token")
+			}
+		}
+	}
+}
+
+// collectTelemetryPages is collectAllPages for get_telemetry results, which page through
+// links.next and meta.pagination.next_page_token instead of metadata.next. GET listings follow
+// links.next; other calls, such as metric queries, are repeated with the page_token query
+// parameter. The data points of each page are appended to the result.
+func (s *MCPServer) collectTelemetryPages(result map[string]interface{}, method, apiPath string, parameters map[string]interface{}, requestBody interface{}, opts APICallOptions) []string {
+	maxPages := s.config.MaxAutoPages
+	if maxPages <= 0 {
+		maxPages = DefaultMaxAutoPages
+	}
+
+	data, _ := result["data"].([]interface{})
+	pages := 1
+	link, token := telemetryNextPage(result)
+	var warnings []string
+	for link != "" || token != "" {
+		if pages >= maxPages {
+			warnings = append(warnings, fmt.Sprintf("Stopped after %d pages (MAX_AUTO_PAGES); links.next or meta.pagination.next_page_token refers to the remaining pages", pages))
+			break
+		}
+
+		if budgetExhausted(opts.Deadline) {
+			err := budgetExceededError(s.config)
+			result[ResultFieldPartial] = true
+			result[ResultFieldError] = err.Error()
+			warnings = append(warnings, fmt.Sprintf("Returning %d page(s); %v", pages, err))
+			break
+		}
+
+		page, err := s.fetchTelemetryPage(link, token, method, apiPath, parameters, requestBody, opts)
+		if err != nil {
+			result[ResultFieldPartial] = true
+			result[ResultFieldError] = err.Error()
+			warnings = append(warnings, fmt.Sprintf("Returning %d page(s); page %d failed: %v", pages, pages+1, err))
+			break
+		}
+		pageData, _ := page["data"].([]interface{})
+		data = append(data, pageData...)
+		link, token = telemetryNextPage(page)
+		pages++
+	}
+
+	result["data"] = data
+	setTelemetryNextPage(result, link, token)
+	return warnings
+}
+
+// fetchTelemetryPage fetches the telemetry page a next link or page token refers to
+func (s *MCPServer) fetchTelemetryPage(link, token, method, apiPath string, parameters map[string]interface{}, requestBody interface{}, opts APICallOptions) (map[string]interface{}, error) {
+	if link != "" && method == tools.HTTPMethodGet {
+		nextPath, query, err := parseNextPageLink(link)
+		if err != nil {
+			return nil, err
+		}
+		return ExecuteAPICallWithOptions(s.config, s.telemetrySpec, "GET", nextPath, query, nil, opts)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("telemetry %s %s returned a next link without a page token", method, apiPath)
+	}
+
+	query := make(map[string]string, len(opts.Query)+1)
+	for key, value := range opts.Query {
+		query[key] = value
+	}
+	query[ParamTelemetryPageToken] = token
+	opts.Query = query
+	return ExecuteAPICallWithOptions(s.config, s.telemetrySpec, method, apiPath, parameters, requestBody, opts)
+}
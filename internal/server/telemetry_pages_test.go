@@ -0,0 +1,128 @@
+package server
+
+import (
+	"fmt"
+	"mcolomerc/mcp-server/internal/config"
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTelemetryTestServer builds an MCPServer whose telemetry spec has a metric query and a
+// descriptor listing; calls reach the API through base_url_override
+func newTelemetryTestServer(t *testing.T, cfg *config.Config) *MCPServer {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+	cfg.AllowBaseURLOverride = true
+	cfg.BaseURLOverrideAllowedHosts = []string{"127.0.0.1"}
+
+	telemetrySpec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/v2/metrics/{dataset}/query": {
+				Post: &openapi.Operation{Summary: "Query metric values"},
+			},
+			"/v2/metrics/{dataset}/descriptors/resources": {
+				Get: &openapi.Operation{Summary: "List resource descriptors"},
+			},
+		},
+	}
+	telemetryTools, err := tools.GenerateSemanticToolsForTelemetry(*telemetrySpec)
+	if err != nil {
+		t.Fatalf("Failed to generate telemetry tools: %v", err)
+	}
+	return NewCompositeServer(cfg, &openapi.OpenAPISpec{}, telemetrySpec, telemetryTools)
+}
+
+func TestTelemetryAllPages(t *testing.T) {
+	var apiServer *httptest.Server
+	apiServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		token := r.URL.Query().Get("page_token")
+		switch r.URL.Path {
+		case "/v2/metrics/cloud/query":
+			if r.Method != http.MethodPost {
+				t.Errorf("Expected every query page to be a POST, got %s", r.Method)
+			}
+			switch token {
+			case "":
+				w.Write([]byte(`{"data":[{"value":1}],"meta":{"pagination":{"page_size":1,"next_page_token":"q2"}}}`))
+			case "q2":
+				w.Write([]byte(`{"data":[{"value":2}],"meta":{"pagination":{"page_size":1,"next_page_token":"q3"}}}`))
+			case "q3":
+				w.Write([]byte(`{"data":[{"value":3}],"meta":{"pagination":{"page_size":1}}}`))
+			}
+		case "/v2/metrics/cloud/descriptors/resources":
+			switch token {
+			case "":
+				fmt.Fprintf(w, `{"data":[{"type":"kafka"}],"meta":{"pagination":{"page_size":1,"next_page_token":"d2"}},"links":{"next":"%s/v2/metrics/cloud/descriptors/resources?page_token=d2"}}`, apiServer.URL)
+			case "d2":
+				w.Write([]byte(`{"data":[{"type":"connector"}],"meta":{"pagination":{"page_size":1}},"links":{}}`))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer apiServer.Close()
+
+	dataValues := func(result map[string]interface{}, field string) []interface{} {
+		var values []interface{}
+		data, _ := result["data"].([]interface{})
+		for _, item := range data {
+			values = append(values, item.(map[string]interface{})[field])
+		}
+		return values
+	}
+	invoke := func(server *MCPServer, resource string) InvokeResponse {
+		return server.InvokeTool(InvokeRequest{Tool: tools.TelemetryAction, Arguments: map[string]interface{}{
+			"resource":          resource,
+			"dataset":           "cloud",
+			"all_pages":         true,
+			"base_url_override": apiServer.URL,
+		}})
+	}
+
+	t.Run("Query pages are aggregated through next_page_token", func(t *testing.T) {
+		server := newTelemetryTestServer(t, newTestConfig(t, apiServer.URL))
+		resp := invoke(server, "metrics")
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		result := resp.Result.(map[string]interface{})
+		if values := fmt.Sprint(dataValues(result, "value")); values != "[1 2 3]" {
+			t.Errorf("Expected the data points of all three pages, got %s", values)
+		}
+		pagination := result["meta"].(map[string]interface{})["pagination"].(map[string]interface{})
+		if _, exists := pagination["next_page_token"]; exists {
+			t.Errorf("Expected no next_page_token after the last page, got %v", pagination)
+		}
+	})
+
+	t.Run("Descriptor pages are aggregated through links.next", func(t *testing.T) {
+		server := newTelemetryTestServer(t, newTestConfig(t, apiServer.URL))
+		resp := invoke(server, "resources")
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		result := resp.Result.(map[string]interface{})
+		if types := fmt.Sprint(dataValues(result, "type")); types != "[kafka connector]" {
+			t.Errorf("Expected the descriptors of both pages, got %s", types)
+		}
+	})
+
+	t.Run("Page cap keeps the remaining page token", func(t *testing.T) {
+		cfg := newTestConfig(t, apiServer.URL)
+		cfg.MaxAutoPages = 2
+		server := newTelemetryTestServer(t, cfg)
+		resp := invoke(server, "metrics")
+		result := resp.Result.(map[string]interface{})
+		if values := fmt.Sprint(dataValues(result, "value")); values != "[1 2]" {
+			t.Errorf("Expected the first two pages, got %s", values)
+		}
+		pagination := result["meta"].(map[string]interface{})["pagination"].(map[string]interface{})
+		if pagination["next_page_token"] != "q3" || len(resp.Warnings) == 0 {
+			t.Errorf("Expected the remaining page token with a warning, got %v warnings=%v", pagination, resp.Warnings)
+		}
+	})
+}
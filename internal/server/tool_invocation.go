@@ -1,21 +1,45 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"mcolomerc/mcp-server/internal/guardrails"
 	"mcolomerc/mcp-server/internal/logger"
 	"mcolomerc/mcp-server/internal/openapi"
 	"mcolomerc/mcp-server/internal/tools"
+	"mcolomerc/mcp-server/internal/types"
+	"os"
 	"strings"
+	"time"
 )
 
 // Tool invocation business logic and helper functions
 
 // InvokeTool executes a tool with the given request
 func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
+	return s.invokeTool(req, false)
+}
+
+// invokeTool executes a tool inside its own trace span; batch items skip loop detection since the
+// batch was already checked as one call
+func (s *MCPServer) invokeTool(req InvokeRequest, batchItem bool) InvokeResponse {
+	req.Tool = s.canonicalToolName(req.Tool)
+	ctx, span := startInvokeSpan(req)
+	req.Context = ctx
+	resp := s.runTool(req, batchItem)
+	endInvokeSpan(span, resp)
+	return resp
+}
+
+// runTool validates the arguments of a tool call and executes it
+func (s *MCPServer) runTool(req InvokeRequest, batchItem bool) InvokeResponse {
 	logger.Debug("InvokeTool called with tool=%s, arguments=%v\n", req.Tool, req.Arguments)
 
+	// Retries and page fetches of this invocation share one time budget
+	deadline := s.invocationDeadline()
+
 	// Special debug logging for tagdefs
 	if req.Arguments["resource"] == "tagdefs" {
 		logger.Debug("*** TAGDEFS TOOL INVOCATION: tool=%s, arguments=%v", req.Tool, req.Arguments)
@@ -32,12 +56,60 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 		return InvokeResponse{Error: "Tool not found"}
 	}
 
+	// Bound simultaneous invocations so bursts of calls cannot exhaust sockets
+	if s.invocations != nil {
+		if err := s.invocations.acquire(); err != nil {
+			return InvokeResponse{Error: err.Error()}
+		}
+		defer s.invocations.release()
+	}
+
+	// Pull out the per-call base URL and Schema Registry endpoint overrides so they are never sent to the API
+	baseURLOverride := extractBaseURLOverride(req.Arguments)
+	schemaRegistryEndpoint := extractConsumedArgument(req.Arguments, ParamSchemaRegistryEndpoint)
+
+	// Reject unknown resources up front with the list of valid ones
+	if tools.IsSemanticAction(req.Tool) || req.Tool == "get_telemetry" {
+		if errMsg := validateResourceArgument(req.Tool, req.Arguments); errMsg != "" {
+			return InvokeResponse{Error: errMsg}
+		}
+	}
+
+	// On updates, an etag/if_match argument becomes the If-Match header instead of a parameter
+	ifMatch := ""
+	if req.Tool == tools.ActionUpdate {
+		ifMatch = extractIfMatch(req.Arguments)
+	}
+
+	// A profile argument selects the credential set for this call
+	profile := extractConsumedArgument(req.Arguments, ParamProfile)
+
+	// An idempotency_key argument becomes the Idempotency-Key header and makes the call retryable
+	idempotencyKey := extractIdempotencyKey(req.Arguments)
+
+	// Resolve "$last..." references from the previous result before any validation
+	chainingEnabled := s.config.EnableResultChaining && s.lastResults != nil
+	if chainingEnabled {
+		last, hasLast := s.lastResults.get(req.SessionID)
+		if err := resolveResultReferences(req.Arguments, last, hasLast); err != nil {
+			return InvokeResponse{Error: fmt.Sprintf("Result reference error: %v", err)}
+		}
+	}
+
 	// Apply input guardrails - validate tool parameters for injection attempts and loop detection
 	if s.guardrails != nil {
-		guardrailsResult := s.guardrails.ValidateToolInput(req.Tool, req.Arguments)
+		var guardrailsResult guardrails.GuardrailsResult
+		if batchItem {
+			guardrailsResult = s.guardrails.ValidateBatchItemInput(req.Tool, req.Arguments)
+		} else {
+			guardrailsResult = s.guardrails.ValidateToolInput(req.Tool, req.Arguments)
+		}
 		if guardrailsResult.Blocked {
 			logger.Debug("Tool call blocked by guardrails: %s", guardrailsResult.BlockingReason)
-			return InvokeResponse{Error: guardrailsResult.BlockingReason}
+			return InvokeResponse{
+				Error:    guardrailsResult.BlockingReason,
+				Cooldown: loopCooldown(guardrailsResult.LoopResult, time.Now()),
+			}
 		}
 
 		// Log additional info for monitoring
@@ -47,6 +119,30 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 		}
 	}
 
+	// On lists, paginate asks for one page plus a continuation token; the token fetches the next page.
+	// Both are read after guardrails so each page counts as a distinct call for loop detection.
+	// all_pages follows the next-page links within this call instead, on lists and telemetry queries.
+	paginate, allPages, continuationToken := false, false, ""
+	if req.Tool == tools.ActionList {
+		paginate, _ = req.Arguments[ParamPaginate].(bool)
+		delete(req.Arguments, ParamPaginate)
+		continuationToken = extractConsumedArgument(req.Arguments, ParamContinuationToken)
+	}
+	if req.Tool == tools.ActionList || req.Tool == tools.TelemetryAction {
+		allPages, _ = req.Arguments[ParamAllPages].(bool)
+		delete(req.Arguments, ParamAllPages)
+	}
+	// A page writer streams the pages of an all_pages list, so it implies all_pages
+	if req.Tool == tools.ActionList && req.PageWriter != nil {
+		allPages = true
+	}
+	if continuationToken != "" {
+		resource, _ := req.Arguments["resource"].(string)
+		opts := s.invocationCallOptions(req.Context, req.ClientToken, profile, deadline)
+		opts.BaseURLOverride, opts.SchemaRegistryEndpoint = baseURLOverride, schemaRegistryEndpoint
+		return s.listNextPage(req, resource, continuationToken, opts)
+	}
+
 	// Determine security type based on the endpoint and OpenAPI spec
 	securityType := "cloud-api-key" // Default fallback
 	endpoint := tool.Endpoint
@@ -82,7 +178,7 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 	resource := ""
 
 	// For semantic tools, get resource from arguments
-	if action == "create" || action == "update" || action == "delete" || action == "get" || action == "list" {
+	if tools.IsSemanticAction(action) {
 		if res, ok := req.Arguments["resource"].(string); ok {
 			resource = res
 		}
@@ -101,8 +197,8 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 	logger.Debug("action=%s, resource=%s\n", action, resource)
 
 	// Debug: Show required parameters for this action/resource combination
-	if resource != "" && (action == "create" || action == "update" || action == "delete" || action == "get" || action == "list") {
-		required, _ := tools.GetRequiredParametersForResource(action, resource)
+	if resource != "" && tools.IsSemanticAction(action) {
+		required, _ := tools.GetRequiredParametersForResource(action, resource, req.Arguments)
 		logger.Debug("Required parameters for %s %s: %v\n", action, resource, required)
 	}
 
@@ -115,8 +211,8 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 		}
 	}
 	// Also check for missing required parameters and apply defaults
-	if resource != "" && (action == "create" || action == "update" || action == "delete" || action == "get" || action == "list") {
-		required, _ := tools.GetRequiredParametersForResource(action, resource)
+	if resource != "" && tools.IsSemanticAction(action) {
+		required, _ := tools.GetRequiredParametersForResource(action, resource, req.Arguments)
 		for _, param := range required {
 			if _, ok := req.Arguments[param]; !ok {
 				if def := resolveDefaultParam(s.config, param, tool.Endpoint); def != "" {
@@ -136,11 +232,25 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 			}
 		}
 	}
+	// A generic id or name argument fills the identifier configured for the resource
+	if resource != "" && action != tools.ActionCreate && action != tools.ActionList {
+		fillResourceIDParam(resource, req.Arguments)
+	}
+	// Cloud and telemetry calls get the configured environment and organization where the operation declares them
+	if resource != "" && tools.IsSemanticAction(action) {
+		if mapping, err := tools.GetEndpointMappingForArgs(action, resource, req.Arguments); err == nil {
+			injectDefaultScope(s.config, s.spec, mapping, req.Arguments)
+		}
+	} else if action == tools.TelemetryAction && resource != "" {
+		if mapping, err := tools.GetTelemetryEndpointMapping(resource); err == nil {
+			injectDefaultScope(s.config, s.telemetrySpec, mapping, req.Arguments)
+		}
+	}
 	// --- End default parameter application ---
 
 	// --- Begin required parameter validation and auto-translation ---
-	if resource != "" && (action == "create" || action == "update" || action == "delete" || action == "get" || action == "list") {
-		required, _ := tools.GetRequiredParametersForResource(action, resource)
+	if resource != "" && tools.IsSemanticAction(action) {
+		required, _ := tools.GetRequiredParametersForResource(action, resource, req.Arguments)
 		missing := []string{}
 		translated := false
 
@@ -189,6 +299,7 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 				Result: map[string]interface{}{
 					"status":         "missing_required_params",
 					"requiredParams": missing,
+					"params":         s.describeMissingParams(action, resource, missing),
 					"message":        "Please provide the following required parameters.",
 				},
 			}
@@ -243,6 +354,7 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 					Result: map[string]interface{}{
 						"status":         "missing_required_params",
 						"requiredParams": missing,
+						"params":         s.describeMissingParams(action, resource, missing),
 						"message":        "Please provide the following required telemetry parameters.",
 					},
 				}
@@ -253,12 +365,16 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 
 	// --- Build request body if schema is present ---
 	var requestBody interface{} = nil
-	if resource != "" && (action == "create" || action == "update") {
+	if resource != "" && (action == "create" || action == "update" || tools.IsCustomSemanticAction(action)) {
 		logger.Debug("Starting request body build for action=%s resource=%s\n", action, resource)
-		mapping, _ := tools.GetEndpointMapping(action, resource)
+		mapping, _ := tools.GetEndpointMappingForArgs(action, resource, req.Arguments)
 		logger.Debug("Building request body for %s %s, schema available: %v\n", action, resource, mapping.RequestBodySchema != nil)
 		logger.Debug("Building request body for %s %s, schema available: %v\n", action, resource, mapping.RequestBodySchema != nil)
-		if mapping.RequestBodySchema != nil {
+		if resource == ResourceConnectors && action == "create" && mapping != nil {
+			// Connectors expect a {name, config:{...}} envelope rather than flat schema properties
+			requestBody = buildConnectorRequestBody(req.Arguments, mapping.PathPattern)
+			logger.Debug("Built connector request body: %v\n", requestBody)
+		} else if mapping.RequestBodySchema != nil {
 			// For semantic tools, parameters can be under req.Arguments["parameters"] or directly in req.Arguments
 			var dataArgs map[string]interface{}
 			if params, ok := req.Arguments["parameters"].(map[string]interface{}); ok {
@@ -330,13 +446,26 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 		} else {
 			logger.Debug("No request body schema found for %s %s\n", action, resource)
 		}
+
+		// PATCH bodies take the shape of their declared patch format
+		if mapping != nil && mapping.Method == tools.HTTPMethodPatch {
+			if contentType, _ := mapping.RequestBodySchema["contentType"].(string); isPatchContentType(contentType) {
+				schemaBody, _ := requestBody.(map[string]interface{})
+				patchBody, err := buildPatchRequestBody(contentType, schemaBody, mapping, req.Arguments)
+				if err != nil {
+					return InvokeResponse{Error: fmt.Sprintf("Invalid patch for %s %s: %v", action, resource, err)}
+				}
+				requestBody = patchBody
+				logger.Debug("Built %s request body: %v\n", contentType, requestBody)
+			}
+		}
 	}
 	// --- End request body build ---
 
 	// --- Actually call the API if this is a semantic tool ---
 	if resource != "" {
 		var mapping *tools.EndpointMapping
-		var apiPath string
+		var pathPattern, apiPath string
 		var spec *openapi.OpenAPISpec
 
 		if action == "get_telemetry" {
@@ -346,17 +475,25 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 				return InvokeResponse{Error: fmt.Sprintf("Telemetry resource error: %v", err)}
 			}
 			mapping = telemetryMapping
-			apiPath = tools.BuildAPIPath(mapping.PathPattern, req.Arguments)
+			pathPattern = mapping.PathPattern
+			if missing := s.resolvePathParameters(pathPattern, req.Arguments); len(missing) > 0 {
+				return InvokeResponse{Error: missingPathParametersError(action, resource, missing)}
+			}
+			apiPath = tools.BuildAPIPath(pathPattern, req.Arguments)
 			spec = s.telemetrySpec // Use telemetry spec instead of main spec
 			logger.Debug("About to call Telemetry API with method=%s, path=%s, parameters=%v\n", mapping.Method, apiPath, req.Arguments)
 		} else {
 			// Regular semantic tool handling
-			regularMapping, err := tools.GetEndpointMapping(action, resource)
+			regularMapping, err := tools.GetEndpointMappingForArgs(action, resource, req.Arguments)
 			if err != nil {
 				return InvokeResponse{Error: fmt.Sprintf("Endpoint mapping error: %v", err)}
 			}
 			mapping = regularMapping
-			apiPath = tools.BuildAPIPath(mapping.PathPattern, req.Arguments)
+			pathPattern = mapping.PathPatternFor(req.Arguments)
+			if missing := s.resolvePathParameters(pathPattern, req.Arguments); len(missing) > 0 {
+				return InvokeResponse{Error: missingPathParametersError(action, resource, missing)}
+			}
+			apiPath = tools.BuildAPIPath(pathPattern, req.Arguments)
 			spec = s.spec // Use main spec
 
 			// Special debug logging for tagdefs
@@ -368,9 +505,65 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 			logger.Debug("About to call API with method=%s, path=%s, parameters=%v, requestBody=%#v\n", mapping.Method, apiPath, req.Arguments, requestBody)
 		}
 
-		result, err := ExecuteAPICall(s.config, spec, mapping.Method, apiPath, req.Arguments, requestBody)
+		// Send the body with the content type its schema was extracted for
+		opts := s.invocationCallOptions(req.Context, req.ClientToken, profile, deadline)
+		opts.BaseURLOverride, opts.SchemaRegistryEndpoint = baseURLOverride, schemaRegistryEndpoint
+		opts.Headers = map[string]string{}
+		opts.Retryable = isRetryableCall(s.config, action, mapping.Method, idempotencyKey != "")
+		if ifMatch != "" {
+			opts.Headers[HeaderIfMatch] = ifMatch
+		}
+		if idempotencyKey != "" {
+			opts.Headers[HeaderIdempotencyKey] = idempotencyKey
+		}
+		if requestBody != nil {
+			if contentType, ok := mapping.RequestBodySchema["contentType"].(string); ok {
+				opts.ContentType = contentType
+			}
+		}
+
+		if s.config.UseSpecServers {
+			opts.ServerVariables = extractServerVariables(spec, apiPath, req.Arguments)
+		}
+
+		// Obviously wrong ids, such as an environment id passed as cluster_id, fail before the call
+		if s.config.ValidateIDFormats {
+			if invalid := invalidIDArguments(req.Arguments); len(invalid) > 0 {
+				return InvokeResponse{Error: fmt.Sprintf("Invalid id arguments for %s %s: %s", action, resource, strings.Join(invalid, "; "))}
+			}
+		}
+
+		// Strict mode rejects arguments the operation does not know, e.g. hallucinated parameters.
+		// Connector creation is exempt: its flat arguments are free-form connector config.
+		if s.config.StrictArgs && !(resource == ResourceConnectors && action == tools.ActionCreate) {
+			if unknown, known := unknownArguments(spec, mapping, pathPattern, req.Arguments); len(unknown) > 0 {
+				return InvokeResponse{Error: fmt.Sprintf("Unknown arguments for %s %s: %s. Valid arguments: %s",
+					action, resource, strings.Join(unknown, ", "), strings.Join(known, ", "))}
+			}
+		}
+
+		var etag string
+		opts.ETag = &etag
+		result, err := ExecuteAPICallWithOptions(s.config, spec, mapping.Method, apiPath, req.Arguments, requestBody, opts)
 		if err != nil {
-			return InvokeResponse{Error: err.Error()}
+			return InvokeResponse{Error: err.Error(), Repro: reproFromError(err), FieldErrors: fieldErrorsFromError(err)}
+		}
+		opts.ETag = nil
+
+		response := InvokeResponse{Result: result, ETag: etag}
+
+		if paginate {
+			if err := s.addContinuationToken(req.SessionID, resource, result); err != nil {
+				response.Warnings = append(response.Warnings, err.Error())
+			}
+		} else if allPages && action == tools.TelemetryAction {
+			response.Warnings = append(response.Warnings, s.collectTelemetryPages(result, mapping.Method, apiPath, req.Arguments, requestBody, opts)...)
+		} else if allPages {
+			response.Warnings = append(response.Warnings, s.collectAllPages(result, opts, req.PageWriter)...)
+		}
+
+		if chainingEnabled {
+			s.lastResults.set(req.SessionID, result)
 		}
 
 		// Check for sensitive operations and add warnings (without modifying the API result)
@@ -378,19 +571,11 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 			sensitiveInfo := guardrails.CheckSensitiveOperation(action, resource, req.Arguments)
 			if sensitiveInfo.IsSensitive {
 				logger.Debug("Sensitive operation detected: %s %s - %s", action, resource, sensitiveInfo.Warning)
-
-				// For sensitive operations, wrap the result to include a warning
-				// This keeps the API response clean while adding contextual information
-				wrappedResult := map[string]interface{}{
-					"data":           result,
-					"warning":        sensitiveInfo.Warning,
-					"operation_type": "sensitive",
-				}
-				return InvokeResponse{Result: wrappedResult}
+				response.Warnings = append(response.Warnings, sensitiveInfo.Warning)
 			}
 		}
 
-		return InvokeResponse{Result: result}
+		return response
 	}
 	// fallback: return error for non-semantic tool
 	return InvokeResponse{Error: "Invalid or unsupported tool invocation"}
@@ -398,6 +583,117 @@ func (s *MCPServer) InvokeTool(req InvokeRequest) InvokeResponse {
 
 // Helper functions for tool invocation
 
+// invocationCallOptions returns the options shared by the API calls of a tool invocation,
+// generated or custom: the credential profile or client token they authenticate with and how a
+// rejected client token is refreshed, the context carrying their trace span and the end of the
+// invocation budget
+func (s *MCPServer) invocationCallOptions(ctx context.Context, clientToken, profile string, deadline time.Time) APICallOptions {
+	return APICallOptions{
+		Profile:            profile,
+		BearerToken:        clientToken,
+		RefreshBearerToken: s.clientTokenRefresh(clientToken),
+		Context:            ctx,
+		Deadline:           deadline,
+	}
+}
+
+// loopCooldown describes the cooldown of a loop-blocked call so clients can back off
+// programmatically, or returns nil when the call was not blocked by a cooldown
+func loopCooldown(loopResult guardrails.LoopDetectionResult, now time.Time) *types.Cooldown {
+	if !loopResult.IsLoop || loopResult.CooldownUntil == nil {
+		return nil
+	}
+	retryAfter := int(math.Ceil(loopResult.CooldownUntil.Sub(now).Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	return &types.Cooldown{
+		CooldownUntil:     loopResult.CooldownUntil.UTC().Format(time.RFC3339),
+		RetryAfterSeconds: retryAfter,
+	}
+}
+
+// validateResourceArgument checks the resource argument against the resources supported by the action
+// and returns an error message listing the valid resources on mismatch
+func validateResourceArgument(action string, args map[string]interface{}) string {
+	supported := tools.GetSupportedResources(action)
+	resource, _ := args["resource"].(string)
+
+	if resource == "" {
+		return fmt.Sprintf("Missing 'resource' argument for '%s'. Valid resources: %s", action, strings.Join(supported, ", "))
+	}
+	// Singular/plural aliases are rewritten to the mapped name, e.g. "topic" -> "topics"
+	if canonical, found := tools.ResolveResourceName(resource, supported); found {
+		args["resource"] = canonical
+		return ""
+	}
+	return fmt.Sprintf("Unsupported resource '%s' for '%s'. Valid resources: %s", resource, action, strings.Join(supported, ", "))
+}
+
+// extractBaseURLOverride removes base_url_override from the top-level or nested parameters and returns it
+func extractBaseURLOverride(args map[string]interface{}) string {
+	override := ""
+	if value, ok := args[ParamBaseURLOverride].(string); ok {
+		override = value
+	}
+	delete(args, ParamBaseURLOverride)
+
+	if params, ok := args["parameters"].(map[string]interface{}); ok {
+		if value, ok := params[ParamBaseURLOverride].(string); ok && override == "" {
+			override = value
+		}
+		delete(params, ParamBaseURLOverride)
+	}
+
+	return strings.TrimSpace(override)
+}
+
+// extractIfMatch removes if_match/etag from the top-level or nested parameters and returns the value
+func extractIfMatch(args map[string]interface{}) string {
+	return extractConsumedArgument(args, ParamIfMatch, ParamETag)
+}
+
+// extractIdempotencyKey removes idempotency_key from the top-level or nested parameters and returns the value
+func extractIdempotencyKey(args map[string]interface{}) string {
+	return extractConsumedArgument(args, ParamIdempotencyKey)
+}
+
+// extractServerVariables removes arguments named after the spec server URL variables of a path and
+// returns their values, so they select the server instead of being sent as query parameters
+func extractServerVariables(spec *openapi.OpenAPISpec, path string, args map[string]interface{}) map[string]string {
+	if spec == nil {
+		return nil
+	}
+	values := map[string]string{}
+	for _, name := range spec.ServerVariableNames(path) {
+		if value := extractConsumedArgument(args, name); value != "" {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// extractConsumedArgument removes the named arguments, which the server handles itself, from the
+// top-level or nested parameters and returns the first string value found
+func extractConsumedArgument(args map[string]interface{}, names ...string) string {
+	found := ""
+	containers := []map[string]interface{}{args}
+	if params, ok := args["parameters"].(map[string]interface{}); ok {
+		containers = append(containers, params)
+	}
+
+	for _, container := range containers {
+		for _, name := range names {
+			if value, ok := container[name].(string); ok && found == "" {
+				found = strings.TrimSpace(value)
+			}
+			delete(container, name)
+		}
+	}
+
+	return found
+}
+
 // mapArgumentToProperty maps common argument names to schema property names
 func mapArgumentToProperty(argName, propName string) bool {
 	// Direct match
@@ -464,16 +760,24 @@ func buildRequestBodyFromSchema(schema *openapi.Schema, args map[string]interfac
 func buildRequestBodyFromSchemaMap(schemaMap map[string]interface{}, args map[string]interface{}) map[string]interface{} {
 	requestBody := make(map[string]interface{})
 
-	// Extract properties from schema map
-	if properties, ok := schemaMap["properties"].(map[string]interface{}); ok {
+	// Extract properties from schema map; a resolved $ref keeps its properties as parsed schemas
+	var propNames []string
+	switch properties := schemaMap["properties"].(type) {
+	case map[string]interface{}:
+		propNames = getMapKeys(properties)
+	case map[string]*openapi.Schema:
 		for propName := range properties {
-			if value, exists := args[propName]; exists {
-				if propName == ParamConfigs {
-					// Special handling for configs
-					requestBody[propName] = transformConfigsParameter(value)
-				} else {
-					requestBody[propName] = value
-				}
+			propNames = append(propNames, propName)
+		}
+	}
+
+	for _, propName := range propNames {
+		if value, exists := args[propName]; exists {
+			if propName == ParamConfigs {
+				// Special handling for configs
+				requestBody[propName] = transformConfigsParameter(value)
+			} else {
+				requestBody[propName] = value
 			}
 		}
 	}
@@ -525,6 +829,122 @@ func transformConfigsParameter(configs interface{}) interface{} {
 	return configs
 }
 
+// resolvePathParameters fills every path parameter of pathPattern that is not in args from the
+// config defaults (e.g. cluster_id from KAFKA_CLUSTER_ID), so nested paths only need the
+// innermost identifiers. It returns the parameters that still have no value, in path order.
+func (s *MCPServer) resolvePathParameters(pathPattern string, args map[string]interface{}) []string {
+	envVarMap := tools.PathParamEnvVarMap()
+	var missing []string
+	for _, param := range tools.ExtractPathParameters(pathPattern) {
+		if value, ok := args[param]; ok && value != nil && value != "" {
+			continue
+		}
+		// Match on the parameter name only: the endpoint would also match unrelated params such
+		// as topic_name on a Kafka path
+		if def := resolveDefaultParam(s.config, param, ""); def != "" {
+			args[param] = def
+			logger.Debug("Resolved path parameter %s from config: %s\n", param, def)
+			continue
+		}
+		if envVar, ok := envVarMap[param]; ok && os.Getenv(envVar) != "" {
+			continue // BuildAPIPath fills it from the environment
+		}
+		missing = append(missing, param)
+	}
+	return missing
+}
+
+// fillResourceIDParam moves a generic id or name argument to the identifier argument configured
+// for the resource in RESOURCE_ID_PARAMS, unless that argument is supplied. The generic argument
+// is removed so it is neither sent upstream nor rejected by STRICT_ARGS.
+func fillResourceIDParam(resource string, args map[string]interface{}) {
+	idParam := tools.ResourceIDParam(resource)
+	if idParam == "" {
+		return
+	}
+	if value, ok := args[idParam]; ok && value != nil && value != "" {
+		return
+	}
+	for _, generic := range []string{ParamID, ParamName} {
+		if value, ok := args[generic]; ok && value != nil && value != "" {
+			args[idParam] = value
+			delete(args, generic)
+			logger.Debug("Filled identifier %s of %s from %s\n", idParam, resource, generic)
+			return
+		}
+	}
+}
+
+// missingPathParametersError describes path parameters that could not be resolved
+func missingPathParametersError(action, resource string, missing []string) string {
+	return fmt.Sprintf("Missing path parameters for '%s %s': %s. Provide them as arguments or configure their defaults.",
+		action, resource, strings.Join(missing, ", "))
+}
+
+// buildConnectorRequestBody builds the {name, config:{...}} body expected when creating a connector.
+// Path parameters and the server's control arguments are left out, and every other flat argument
+// is nested under config as a string value.
+func buildConnectorRequestBody(args map[string]interface{}, pathPattern string) map[string]interface{} {
+	skip := map[string]bool{ParamConfig: true}
+	for _, param := range strictControlArguments {
+		skip[param] = true
+	}
+	for _, param := range perCallArguments {
+		skip[param] = true
+	}
+	for _, param := range tools.ExtractPathParameters(pathPattern) {
+		skip[param] = true
+	}
+
+	connectorConfig := make(map[string]interface{})
+
+	// Start from an explicit config object if one was provided
+	if explicit := transformConnectorConfig(args[ParamConfig]); explicit != nil {
+		for key, value := range explicit {
+			connectorConfig[key] = fmt.Sprintf("%v", value)
+		}
+	}
+
+	// Flat arguments such as connector.class or kafka.api.key belong under config
+	for key, value := range args {
+		if skip[key] || value == nil {
+			continue
+		}
+		connectorConfig[key] = fmt.Sprintf("%v", value)
+	}
+
+	requestBody := map[string]interface{}{
+		ParamConfig: connectorConfig,
+	}
+
+	if name, ok := args[ParamName]; ok && name != nil {
+		requestBody[ParamName] = name
+	} else if name, ok := connectorConfig[ParamName]; ok {
+		requestBody[ParamName] = name
+	} else if name, ok := connectorConfig[ParamConnectorName]; ok {
+		requestBody[ParamName] = name
+		delete(connectorConfig, ParamConnectorName)
+	}
+
+	return requestBody
+}
+
+// transformConnectorConfig returns the connector config as a map, parsing JSON strings if needed
+func transformConnectorConfig(config interface{}) map[string]interface{} {
+	if configMap, ok := config.(map[string]interface{}); ok {
+		return configMap
+	}
+
+	if configStr, ok := config.(string); ok {
+		var parsed map[string]interface{}
+		if err := json.Unmarshal([]byte(configStr), &parsed); err == nil {
+			return parsed
+		}
+	}
+
+	return nil
+}
+
 // determineSecurityTypeFromPath determines the security type based on path patterns
 // when the OpenAPI spec doesn't specify it explicitly
 func determineSecurityTypeFromPath(path string) string {
@@ -0,0 +1,1639 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mcolomerc/mcp-server/internal/config"
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newTestConfig returns a config whose REST endpoints all point at the given base URL
+func newTestConfig(t testing.TB, baseURL string) *config.Config {
+	return &config.Config{
+		PromptsFolder:           t.TempDir(),
+		DirectivesFolder:        t.TempDir(),
+		ConfluentEnvID:          "env-test",
+		ConfluentCloudAPIKey:    "test-cloud-key",
+		ConfluentCloudAPISecret: "test-cloud-secret",
+		BootstrapServers:        "test-servers",
+		KafkaAPIKey:             "test-kafka-key",
+		KafkaAPISecret:          "test-kafka-secret",
+		KafkaRestEndpoint:       baseURL,
+		KafkaClusterID:          "lkc-test",
+		FlinkOrgID:              "test-org",
+		FlinkRestEndpoint:       baseURL,
+		FlinkEnvName:            "test",
+		FlinkDatabaseName:       "test",
+		FlinkAPIKey:             "test-flink-key",
+		FlinkAPISecret:          "test-flink-secret",
+		FlinkComputePoolID:      "lfcp-test",
+		SchemaRegistryAPIKey:    "test-sr-key",
+		SchemaRegistryAPISecret: "test-sr-secret",
+		SchemaRegistryEndpoint:  baseURL,
+	}
+}
+
+// newTopicsTestServer builds an MCPServer backed by a minimal Kafka topics spec
+func newTopicsTestServer(t *testing.T, cfg *config.Config) *MCPServer {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+
+	spec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Get: &openapi.Operation{Summary: "List topics"},
+			},
+			"/kafka/v3/clusters/{cluster_id}/topics/{topic_name}": {
+				Get:    &openapi.Operation{Summary: "Get topic"},
+				Patch:  &openapi.Operation{Summary: "Update topic"},
+				Delete: &openapi.Operation{Summary: "Delete topic"},
+			},
+		},
+	}
+
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+
+	return NewCompositeServer(cfg, spec, &openapi.OpenAPISpec{}, semanticTools)
+}
+
+func TestInvokeToolSensitiveOperationWarnings(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"orders","kind":"KafkaTopic"}`))
+	}))
+	defer apiServer.Close()
+
+	server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+
+	args := map[string]interface{}{
+		"resource":   "topics",
+		"topic_name": "orders",
+	}
+
+	t.Run("InvokeTool keeps result unchanged", func(t *testing.T) {
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionDelete, Arguments: args})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+
+		expected := map[string]interface{}{
+			"topic_name":  "orders",
+			"kind":        "KafkaTopic",
+			"status_code": http.StatusOK,
+		}
+		if !reflect.DeepEqual(resp.Result, expected) {
+			t.Errorf("Expected result %v, got %v", expected, resp.Result)
+		}
+
+		if len(resp.Warnings) != 1 {
+			t.Fatalf("Expected 1 warning, got %d: %v", len(resp.Warnings), resp.Warnings)
+		}
+		if !strings.Contains(resp.Warnings[0], "DESTRUCTIVE OPERATION") {
+			t.Errorf("Expected destructive operation warning, got %q", resp.Warnings[0])
+		}
+	})
+
+	t.Run("Tool handler surfaces warnings separately", func(t *testing.T) {
+		server.guardrails.ClearAllCooldowns()
+
+		handler := server.createToolHandler(tools.ActionDelete)
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"resource":   "topics",
+			"topic_name": "orders",
+		}
+
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Content) != 2 {
+			t.Fatalf("Expected 2 content items, got %d", len(result.Content))
+		}
+
+		body, ok := result.Content[0].(mcp.TextContent)
+		if !ok {
+			t.Fatalf("Expected text content, got %T", result.Content[0])
+		}
+		var parsed map[string]interface{}
+		if err := json.Unmarshal([]byte(body.Text), &parsed); err != nil {
+			t.Fatalf("Primary content is not the raw JSON result: %v", err)
+		}
+		if parsed["topic_name"] != "orders" {
+			t.Errorf("Expected raw API body in primary content, got %s", body.Text)
+		}
+		if _, wrapped := parsed["warning"]; wrapped {
+			t.Error("Primary content should not contain wrapped warning")
+		}
+
+		warning, ok := result.Content[1].(mcp.TextContent)
+		if !ok || !strings.HasPrefix(warning.Text, "Warning: ") {
+			t.Errorf("Expected warning content, got %v", result.Content[1])
+		}
+	})
+}
+
+func TestExecuteAPICallWithOptionsContentType(t *testing.T) {
+	var receivedContentType string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedContentType = r.Header.Get(HeaderContentType)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	body := map[string]interface{}{"schema": "{}"}
+
+	t.Run("Vendor media type is sent when requested", func(t *testing.T) {
+		opts := APICallOptions{ContentType: tools.ContentTypeSchemaRegistryV1JSON}
+		if _, err := ExecuteAPICallWithOptions(cfg, nil, "POST", "/subjects/orders-value/versions", nil, body, opts); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if receivedContentType != tools.ContentTypeSchemaRegistryV1JSON {
+			t.Errorf("Expected Content-Type %q, got %q", tools.ContentTypeSchemaRegistryV1JSON, receivedContentType)
+		}
+	})
+
+	t.Run("Defaults to application/json", func(t *testing.T) {
+		if _, err := ExecuteAPICall(cfg, nil, "POST", "/subjects/orders-value/versions", nil, body); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if receivedContentType != ContentTypeJSON {
+			t.Errorf("Expected Content-Type %q, got %q", ContentTypeJSON, receivedContentType)
+		}
+	})
+}
+
+func TestExecuteAPICallAcceptHeader(t *testing.T) {
+	var receivedAccept string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedAccept = r.Header.Get(HeaderAccept)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	schemaRegistryResponse := openapi.Response{Content: map[string]openapi.MediaType{
+		"application/vnd.schemaregistry.v1+json":      {},
+		"application/vnd.schemaregistry+json; qs=0.9": {},
+		"application/json; qs=0.5":                    {},
+	}}
+	spec := &openapi.OpenAPISpec{
+		Paths: map[string]openapi.PathItem{
+			"/subjects/{subject}/versions": {
+				Get: &openapi.Operation{Responses: map[string]openapi.Response{
+					"200": schemaRegistryResponse,
+					"404": {Content: map[string]openapi.MediaType{"application/json": {}}},
+				}},
+			},
+			"/schemas/ids/{id}": {
+				Get: &openapi.Operation{Responses: map[string]openapi.Response{
+					"200": {Ref: "#/components/responses/SchemaResponse"},
+				}},
+			},
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Get: &openapi.Operation{Responses: map[string]openapi.Response{
+					"200": {Content: map[string]openapi.MediaType{"application/json": {}}},
+				}},
+			},
+		},
+		Components: &openapi.Components{
+			Responses: map[string]openapi.Response{"SchemaResponse": schemaRegistryResponse},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		spec     *openapi.OpenAPISpec
+		path     string
+		expected string
+	}{
+		{"Schema Registry vendor type is preferred", spec, "/subjects/orders-value/versions", tools.ContentTypeSchemaRegistryV1JSON},
+		{"Response references are resolved", spec, "/schemas/ids/1", tools.ContentTypeSchemaRegistryV1JSON},
+		{"Declared JSON response", spec, "/kafka/v3/clusters/lkc-1/topics", ContentTypeJSON},
+		{"Undeclared operation defaults to JSON", nil, "/subjects/orders-value/versions", ContentTypeJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ExecuteAPICall(cfg, tt.spec, "GET", tt.path, nil, nil); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if receivedAccept != tt.expected {
+				t.Errorf("Expected Accept %q, got %q", tt.expected, receivedAccept)
+			}
+		})
+	}
+}
+
+func TestExecuteAPICallArrayQueryParameters(t *testing.T) {
+	var receivedQuery string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	noExplode := false
+	spec := &openapi.OpenAPISpec{
+		Paths: map[string]openapi.PathItem{
+			"/subjects": {
+				Get: &openapi.Operation{
+					Parameters: []openapi.Parameter{
+						{Name: "subjectPrefix", In: "query", Style: "form", Explode: &noExplode},
+						{Name: "state", In: "query", Style: "pipeDelimited"},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		spec     *openapi.OpenAPISpec
+		params   map[string]interface{}
+		expected string
+	}{
+		{
+			name:     "Undeclared array parameter repeats the key",
+			spec:     nil,
+			params:   map[string]interface{}{"subjectPrefix": []interface{}{"orders", "payments"}},
+			expected: "subjectPrefix=orders&subjectPrefix=payments",
+		},
+		{
+			name:     "Form style without explode is comma-joined",
+			spec:     spec,
+			params:   map[string]interface{}{"subjectPrefix": []interface{}{"orders", "payments"}},
+			expected: "subjectPrefix=orders%2Cpayments",
+		},
+		{
+			name:     "Pipe-delimited style is pipe-joined",
+			spec:     spec,
+			params:   map[string]interface{}{"state": []string{"ACTIVE", "DELETED"}},
+			expected: "state=ACTIVE%7CDELETED",
+		},
+		{
+			name:     "Scalar values are unchanged",
+			spec:     spec,
+			params:   map[string]interface{}{"subjectPrefix": "orders"},
+			expected: "subjectPrefix=orders",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ExecuteAPICall(cfg, tt.spec, "GET", "/subjects", tt.params, nil); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if receivedQuery != tt.expected {
+				t.Errorf("Expected query %q, got %q", tt.expected, receivedQuery)
+			}
+		})
+	}
+}
+
+func TestExecuteAPICallObjectQueryParameters(t *testing.T) {
+	var receivedQuery string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedQuery, _ = url.QueryUnescape(r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	noExplode := false
+	spec := &openapi.OpenAPISpec{
+		Paths: map[string]openapi.PathItem{
+			"/subjects": {
+				Get: &openapi.Operation{
+					Parameters: []openapi.Parameter{
+						{Name: "filter", In: "query", Style: "deepObject", Schema: &openapi.Schema{Type: "object"}},
+						{Name: "labels", In: "query", Style: "form", Explode: &noExplode, Schema: &openapi.Schema{Type: "object"}},
+						{Name: "match", In: "query", Schema: &openapi.Schema{
+							Type: "object",
+							Properties: map[string]*openapi.Schema{
+								"owner": {Type: "object", Properties: map[string]*openapi.Schema{"team": {Type: "string"}}},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		expected string
+	}{
+		{
+			name: "Deep object style nests keys in brackets",
+			params: map[string]interface{}{"filter": map[string]interface{}{
+				"name":  "orders",
+				"owner": map[string]interface{}{"team": "payments"},
+			}},
+			expected: "filter[name]=orders&filter[owner][team]=payments",
+		},
+		{
+			name:     "Form style without explode joins properties and values",
+			params:   map[string]interface{}{"labels": map[string]interface{}{"env": "prod", "tier": 1}},
+			expected: "labels=env,prod,tier,1",
+		},
+		{
+			name:     "Undeclared object sends each property as a parameter",
+			params:   map[string]interface{}{"options": map[string]interface{}{"limit": 10}},
+			expected: "limit=10",
+		},
+		{
+			name:     "Nested object schema without a style uses deep object",
+			params:   map[string]interface{}{"match": map[string]interface{}{"owner": map[string]interface{}{"team": "payments"}}},
+			expected: "match[owner][team]=payments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ExecuteAPICall(cfg, spec, "GET", "/subjects", tt.params, nil); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if receivedQuery != tt.expected {
+				t.Errorf("Expected query %q, got %q", tt.expected, receivedQuery)
+			}
+		})
+	}
+}
+
+func TestExecuteAPICallSpecBasePath(t *testing.T) {
+	var receivedPath string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer apiServer.Close()
+
+	tests := []struct {
+		name     string
+		basePath string
+		path     string
+		expected string
+	}{
+		{name: "No base path", basePath: "", path: "/subjects", expected: "/subjects"},
+		{name: "Prefix is prepended", basePath: "/confluent-proxy", path: "/subjects", expected: "/confluent-proxy/subjects"},
+		{name: "Slashes are normalized", basePath: "confluent-proxy/", path: "/subjects", expected: "/confluent-proxy/subjects"},
+		{name: "Already prefixed path is unchanged", basePath: "/confluent-proxy", path: "/confluent-proxy/subjects", expected: "/confluent-proxy/subjects"},
+		{name: "Partial segment match is not treated as applied", basePath: "/sub", path: "/subjects", expected: "/sub/subjects"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(t, apiServer.URL)
+			cfg.SpecBasePath = tt.basePath
+			if _, err := ExecuteAPICall(cfg, nil, "GET", tt.path, nil, nil); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if receivedPath != tt.expected {
+				t.Errorf("Expected path %q, got %q", tt.expected, receivedPath)
+			}
+		})
+	}
+}
+
+func TestBuildConnectorRequestBody(t *testing.T) {
+	pathPattern := "/connect/v1/environments/{environment_id}/clusters/{kafka_cluster_id}/connectors"
+
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "Flat args are nested under config",
+			args: map[string]interface{}{
+				"resource":         "connectors",
+				"environment_id":   "env-123",
+				"kafka_cluster_id": "lkc-123",
+				"name":             "orders-sink",
+				"connector.class":  "S3_SINK",
+				"kafka.api.key":    "key",
+				"tasks.max":        1,
+			},
+			expected: map[string]interface{}{
+				"name": "orders-sink",
+				"config": map[string]interface{}{
+					"connector.class": "S3_SINK",
+					"kafka.api.key":   "key",
+					"tasks.max":       "1",
+				},
+			},
+		},
+		{
+			name: "Explicit config is merged with flat args",
+			args: map[string]interface{}{
+				"resource":          "connectors",
+				"name":              "orders-sink",
+				"config":            `{"connector.class":"S3_SINK","topics":"orders"}`,
+				"input.data.format": "JSON",
+			},
+			expected: map[string]interface{}{
+				"name": "orders-sink",
+				"config": map[string]interface{}{
+					"connector.class":   "S3_SINK",
+					"topics":            "orders",
+					"input.data.format": "JSON",
+				},
+			},
+		},
+		{
+			name: "connector_name is used when name is missing",
+			args: map[string]interface{}{
+				"resource":        "connectors",
+				"connector_name":  "orders-source",
+				"connector.class": "DatagenSource",
+			},
+			expected: map[string]interface{}{
+				"name": "orders-source",
+				"config": map[string]interface{}{
+					"connector.class": "DatagenSource",
+				},
+			},
+		},
+		{
+			name: "Control arguments are left out of config",
+			args: map[string]interface{}{
+				"resource":        "connectors",
+				"name":            "orders-sink",
+				"connector.class": "S3_SINK",
+				"pretty":          true,
+				"profile":         "prod",
+				"idempotency_key": "create-orders-sink",
+				"paginate":        true,
+				"environment":     "env-123",
+			},
+			expected: map[string]interface{}{
+				"name": "orders-sink",
+				"config": map[string]interface{}{
+					"connector.class": "S3_SINK",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := buildConnectorRequestBody(tt.args, pathPattern)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestInvokeToolKafkaClusters(t *testing.T) {
+	newClusterServer := func(clusterID string, calls *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*calls = append(*calls, clusterID+" "+r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":[]}`))
+		}))
+	}
+	var calls []string
+	east := newClusterServer("lkc-east", &calls)
+	defer east.Close()
+	west := newClusterServer("lkc-west", &calls)
+	defer west.Close()
+
+	cfg := newTestConfig(t, "http://127.0.0.1:1")
+	cfg.KafkaClusters = map[string]string{"lkc-east": east.URL, "lkc-west": west.URL}
+	server := newTopicsTestServer(t, cfg)
+
+	for _, clusterID := range []string{"lkc-east", "lkc-west"} {
+		resp := server.InvokeTool(InvokeRequest{
+			Tool:      tools.ActionList,
+			Arguments: map[string]interface{}{"resource": "topics", "cluster_id": clusterID},
+		})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error for %s: %s", clusterID, resp.Error)
+		}
+	}
+
+	want := []string{
+		"lkc-east /kafka/v3/clusters/lkc-east/topics",
+		"lkc-west /kafka/v3/clusters/lkc-west/topics",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected each cluster to be called on its own endpoint %v, got %v", want, calls)
+	}
+}
+
+func TestInvokeToolServiceHeaders(t *testing.T) {
+	var received http.Header
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	cfg.ServiceHeaders = map[string]map[string]string{
+		tools.ServiceKafka: {"X-Target-Cluster": "lkc-test"},
+		tools.ServiceCloud: {"Confluent-Allow-Api-Keys": "true"},
+	}
+	server := newTopicsTestServer(t, cfg)
+
+	resp := server.InvokeTool(InvokeRequest{
+		Tool:      tools.ActionList,
+		Arguments: map[string]interface{}{"resource": "topics"},
+	})
+	if resp.Error != "" {
+		t.Fatalf("Unexpected error: %s", resp.Error)
+	}
+	if got := received.Get("X-Target-Cluster"); got != "lkc-test" {
+		t.Errorf("Expected the Kafka service header, got %q", got)
+	}
+	if got := received.Get("Confluent-Allow-Api-Keys"); got != "" {
+		t.Errorf("Expected no Cloud service header on a Kafka call, got %q", got)
+	}
+}
+
+func TestExecuteAPICallReproOnError(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error_code":400,"message":"invalid config"}`))
+	}))
+	defer apiServer.Close()
+
+	path := "/kafka/v3/clusters/lkc-test/topics"
+	body := map[string]interface{}{"topic_name": "it's"}
+
+	for _, reproOnError := range []bool{false, true} {
+		cfg := newTestConfig(t, apiServer.URL)
+		cfg.ReproOnError = reproOnError
+
+		_, err := ExecuteAPICall(cfg, &openapi.OpenAPISpec{}, "POST", path, nil, body)
+		if err == nil {
+			t.Fatalf("Expected the 400 response to fail the call")
+		}
+		repro := reproFromError(err)
+		if !reproOnError {
+			if repro != "" {
+				t.Errorf("Expected no repro without REPRO_ON_ERROR, got %s", repro)
+			}
+			continue
+		}
+
+		prefix := "curl -X POST '" + apiServer.URL + path + "' "
+		if !strings.HasPrefix(repro, prefix) {
+			t.Errorf("Expected repro to start with %q, got %s", prefix, repro)
+		}
+		wantBody := ` -d '{"topic_name":"it'\''s"}'`
+		if !strings.HasSuffix(repro, wantBody) {
+			t.Errorf("Expected repro to end with the body %q, got %s", wantBody, repro)
+		}
+		if !strings.Contains(repro, `-u "$CONFLUENT_CLOUD_API_KEY:$CONFLUENT_CLOUD_API_SECRET"`) || strings.Contains(repro, cfg.ConfluentCloudAPISecret) {
+			t.Errorf("Expected repro credentials to be redacted, got %s", repro)
+		}
+	}
+}
+
+func TestExecuteAPICallReproHidesServiceHeaders(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error_code":400,"message":"invalid config"}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := newTestConfig(t, apiServer.URL)
+	cfg.ReproOnError = true
+	cfg.ServiceHeaders = map[string]map[string]string{
+		tools.ServiceKafka: {"X-Gateway-Token": "s3cr3t-gateway"},
+	}
+
+	opts := APICallOptions{Headers: map[string]string{HeaderIdempotencyKey: "create-orders"}}
+	_, err := ExecuteAPICallWithOptions(cfg, &openapi.OpenAPISpec{}, "POST", "/kafka/v3/clusters/lkc-test/topics", nil, map[string]interface{}{"topic_name": "orders"}, opts)
+	if err == nil {
+		t.Fatalf("Expected the 400 response to fail the call")
+	}
+	repro := reproFromError(err)
+	if strings.Contains(repro, "s3cr3t-gateway") {
+		t.Errorf("Expected the service header value to be hidden, got %s", repro)
+	}
+	if !strings.Contains(repro, `-H 'X-Gateway-Token: ***'`) {
+		t.Errorf("Expected the service header with a placeholder, got %s", repro)
+	}
+	if !strings.Contains(repro, `-H 'Idempotency-Key: create-orders'`) {
+		t.Errorf("Expected the call's own headers to be kept, got %s", repro)
+	}
+}
+
+func TestInvokeToolBaseURLOverride(t *testing.T) {
+	configuredHits, overrideHits := 0, 0
+	configuredServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		configuredHits++
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer configuredServer.Close()
+	overrideServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		overrideHits++
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer overrideServer.Close()
+
+	tests := []struct {
+		name             string
+		allowOverride    bool
+		allowedHosts     []string
+		expectError      string
+		expectOverridden bool
+	}{
+		{
+			name:             "Override is used when allowed",
+			allowOverride:    true,
+			allowedHosts:     []string{"127.0.0.1"},
+			expectOverridden: true,
+		},
+		{
+			name:          "Override is rejected when flag is off",
+			allowOverride: false,
+			allowedHosts:  []string{"127.0.0.1"},
+			expectError:   "ALLOW_BASE_URL_OVERRIDE",
+		},
+		{
+			name:          "Override is rejected for hosts outside the allowlist",
+			allowOverride: true,
+			expectError:   "not in the allowed hosts",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configuredHits, overrideHits = 0, 0
+
+			cfg := newTestConfig(t, configuredServer.URL)
+			cfg.AllowBaseURLOverride = tt.allowOverride
+			cfg.BaseURLOverrideAllowedHosts = tt.allowedHosts
+			server := newTopicsTestServer(t, cfg)
+
+			resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: map[string]interface{}{
+				"resource":          "topics",
+				"topic_name":        fmt.Sprintf("orders-%d", i),
+				"base_url_override": overrideServer.URL,
+			}})
+
+			if tt.expectError != "" {
+				if !strings.Contains(resp.Error, tt.expectError) {
+					t.Errorf("Expected error containing %q, got %q", tt.expectError, resp.Error)
+				}
+				if configuredHits+overrideHits != 0 {
+					t.Errorf("Expected no API calls, got %d configured and %d override", configuredHits, overrideHits)
+				}
+				return
+			}
+
+			if resp.Error != "" {
+				t.Fatalf("Unexpected error: %s", resp.Error)
+			}
+			if overrideHits != 1 || configuredHits != 0 {
+				t.Errorf("Expected call to go to override server, got %d configured and %d override", configuredHits, overrideHits)
+			}
+		})
+	}
+}
+
+func TestInvokeToolEscapesPathParameters(t *testing.T) {
+	var receivedPath string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.EscapedPath()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"team/orders v2"}`))
+	}))
+	defer apiServer.Close()
+
+	server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+	resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: map[string]interface{}{
+		"resource":   "topics",
+		"topic_name": "team/orders v2",
+	}})
+	if resp.Error != "" {
+		t.Fatalf("Unexpected error: %s", resp.Error)
+	}
+	if expected := "/kafka/v3/clusters/lkc-test/topics/team%2Forders%20v2"; receivedPath != expected {
+		t.Errorf("Expected the id to be sent as one escaped segment %s, got %s", expected, receivedPath)
+	}
+}
+
+func TestInvokeToolSchemaRegistryEndpointOverride(t *testing.T) {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+
+	var configuredPaths, overridePaths []string
+	var overrideQuery, overrideAuth string
+	configuredServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		configuredPaths = append(configuredPaths, r.URL.Path)
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer configuredServer.Close()
+	overrideServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		overridePaths = append(overridePaths, r.URL.Path)
+		overrideQuery, overrideAuth = r.URL.RawQuery, r.Header.Get(HeaderAuth)
+		w.Write([]byte(`["orders-value"]`))
+	}))
+	defer overrideServer.Close()
+
+	spec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/subjects": {Get: &openapi.Operation{
+				Summary:  "List subjects",
+				Security: []map[string][]string{{SecurityTypeResourceAPIKey: {}}},
+			}},
+			"/kafka/v3/clusters/{cluster_id}/topics": {Get: &openapi.Operation{Summary: "List topics"}},
+		},
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+	newServer := func(allowedHosts []string) *MCPServer {
+		cfg := newTestConfig(t, configuredServer.URL)
+		cfg.AllowBaseURLOverride = true
+		cfg.BaseURLOverrideAllowedHosts = allowedHosts
+		cfg.CredentialProfiles = map[string]config.CredentialProfile{
+			"other-sr": {
+				SchemaRegistryEndpoint:  overrideServer.URL,
+				SchemaRegistryAPIKey:    "other-sr-key",
+				SchemaRegistryAPISecret: "other-sr-secret",
+			},
+		}
+		return NewCompositeServer(cfg, spec, &openapi.OpenAPISpec{}, semanticTools)
+	}
+	list := func(server *MCPServer, resource, profile string) InvokeResponse {
+		arguments := map[string]interface{}{
+			"resource":                 resource,
+			"schema_registry_endpoint": overrideServer.URL,
+		}
+		if profile != "" {
+			arguments[ParamProfile] = profile
+		}
+		return server.InvokeTool(InvokeRequest{Tool: tools.ActionList, Arguments: arguments})
+	}
+
+	t.Run("Schema Registry calls go to the override endpoint", func(t *testing.T) {
+		configuredPaths, overridePaths = nil, nil
+		resp := list(newServer([]string{"127.0.0.1"}), "subjects", "other-sr")
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		if expected := AuthBasicPrefix + base64.StdEncoding.EncodeToString([]byte("other-sr-key:other-sr-secret")); overrideAuth != expected {
+			t.Errorf("Expected the profile's Schema Registry credentials, got %q", overrideAuth)
+		}
+		if len(overridePaths) != 1 || overridePaths[0] != "/subjects" || len(configuredPaths) != 0 {
+			t.Errorf("Expected the call to go to the override endpoint, got override=%v configured=%v", overridePaths, configuredPaths)
+		}
+		if strings.Contains(overrideQuery, ParamSchemaRegistryEndpoint) {
+			t.Errorf("Expected the override not to be sent to the API, got query %q", overrideQuery)
+		}
+	})
+
+	t.Run("Other services keep their configured endpoint", func(t *testing.T) {
+		configuredPaths, overridePaths = nil, nil
+		resp := list(newServer([]string{"127.0.0.1"}), "topics", "")
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		if len(configuredPaths) != 1 || len(overridePaths) != 0 {
+			t.Errorf("Expected the Kafka call to go to the configured endpoint, got override=%v configured=%v", overridePaths, configuredPaths)
+		}
+	})
+
+	t.Run("Hosts outside the allowlist are rejected", func(t *testing.T) {
+		configuredPaths, overridePaths = nil, nil
+		resp := list(newServer(nil), "subjects", "other-sr")
+		if !strings.Contains(resp.Error, "not in the allowed hosts") {
+			t.Errorf("Expected an allowlist error, got %q", resp.Error)
+		}
+		if len(configuredPaths)+len(overridePaths) != 0 {
+			t.Errorf("Expected no API calls, got override=%v configured=%v", overridePaths, configuredPaths)
+		}
+	})
+
+	t.Run("Configured credentials are not sent to the override endpoint", func(t *testing.T) {
+		configuredPaths, overridePaths = nil, nil
+		resp := list(newServer([]string{"127.0.0.1"}), "subjects", "")
+		if !strings.Contains(resp.Error, "needs a credential profile") {
+			t.Errorf("Expected the override to be rejected without a matching profile, got %q", resp.Error)
+		}
+		if len(configuredPaths)+len(overridePaths) != 0 {
+			t.Errorf("Expected no API calls, got override=%v configured=%v", overridePaths, configuredPaths)
+		}
+	})
+}
+
+func TestInvokeToolSpecServers(t *testing.T) {
+	var receivedQuery string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedQuery = r.URL.RawQuery
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+	apiHost := strings.TrimPrefix(apiServer.URL, "http://")
+
+	cfg := newTestConfig(t, "http://configured.invalid")
+	cfg.UseSpecServers = true
+	cfg.SpecServerVariables = map[string]string{"host": "configured.invalid"}
+	server := newTopicsTestServer(t, cfg)
+	server.spec.Servers = []openapi.Server{{
+		URL:       "{scheme}://{host}",
+		Variables: map[string]openapi.ServerVariable{"scheme": {Default: "http", Enum: []string{"http", "https"}}},
+	}}
+
+	resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: map[string]interface{}{
+		"resource":   "topics",
+		"cluster_id": "lkc-1",
+		"topic_name": "orders",
+		"host":       apiHost,
+	}})
+	if resp.Error != "" {
+		t.Fatalf("Expected the call to reach the server from the spec, got error: %s", resp.Error)
+	}
+	if strings.Contains(receivedQuery, "host") {
+		t.Errorf("Expected the server variable argument not to be sent, got query %q", receivedQuery)
+	}
+}
+
+func TestInvokeToolETagOptimisticConcurrency(t *testing.T) {
+	var receivedIfMatch, receivedQuery string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedIfMatch = r.Header.Get(HeaderIfMatch)
+		receivedQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(HeaderETag, `"v2"`)
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+
+	t.Run("Response ETag is surfaced next to the result", func(t *testing.T) {
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: map[string]interface{}{
+			"resource":   "topics",
+			"topic_name": "orders",
+		}})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		if resp.ETag != `"v2"` {
+			t.Errorf("Expected etag %q, got %q", `"v2"`, resp.ETag)
+		}
+		result, _ := resp.Result.(map[string]interface{})
+		if _, exists := result["etag"]; exists {
+			t.Errorf("Expected the API result to be left unchanged, got %v", resp.Result)
+		}
+		if receivedIfMatch != "" {
+			t.Errorf("Expected no If-Match header on get, got %q", receivedIfMatch)
+		}
+	})
+
+	t.Run("Tool result lists the ETag after the API result", func(t *testing.T) {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"resource": "topics", "topic_name": "orders"}
+		result, err := server.createToolHandler(tools.ActionGet)(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Content) != 2 {
+			t.Fatalf("Expected the result and the ETag, got %v", result.Content)
+		}
+		if etag, ok := result.Content[1].(mcp.TextContent); !ok || etag.Text != `ETag: "v2"` {
+			t.Errorf("Expected the ETag item, got %v", result.Content[1])
+		}
+	})
+
+	t.Run("etag argument is sent as If-Match on update", func(t *testing.T) {
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionUpdate, Arguments: map[string]interface{}{
+			"resource":   "topics",
+			"topic_name": "orders",
+			"parameters": map[string]interface{}{"etag": `"v1"`},
+		}})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		if receivedIfMatch != `"v1"` {
+			t.Errorf("Expected If-Match %q, got %q", `"v1"`, receivedIfMatch)
+		}
+	})
+
+	t.Run("if_match argument is not forwarded as a parameter", func(t *testing.T) {
+		args := map[string]interface{}{
+			"resource":   "topics",
+			"topic_name": "orders",
+			"if_match":   `"v3"`,
+		}
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionUpdate, Arguments: args})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		if receivedIfMatch != `"v3"` {
+			t.Errorf("Expected If-Match %q, got %q", `"v3"`, receivedIfMatch)
+		}
+		if _, exists := args["if_match"]; exists || strings.Contains(receivedQuery, "if_match") {
+			t.Error("Expected if_match to be consumed rather than sent as a parameter")
+		}
+	})
+}
+
+func TestToolHandlerPrettyJSON(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	tests := []struct {
+		name         string
+		prettyConfig bool
+		prettyArg    interface{}
+		expectIndent bool
+	}{
+		{name: "Compact by default", prettyConfig: false, expectIndent: false},
+		{name: "Indented when PRETTY_JSON is set", prettyConfig: true, expectIndent: true},
+		{name: "Per-call pretty overrides compact default", prettyConfig: false, prettyArg: true, expectIndent: true},
+		{name: "Per-call pretty=false overrides PRETTY_JSON", prettyConfig: true, prettyArg: false, expectIndent: false},
+	}
+
+	t.Run("Advertised in the tool schema", func(t *testing.T) {
+		server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+		for _, tool := range server.GetTools() {
+			property, ok := server.advertisedTool(tool).InputSchema.Properties[ParamPretty].(map[string]interface{})
+			if !ok || property["type"] != "boolean" {
+				t.Errorf("Expected %s to advertise a boolean pretty argument, got %v", tool.Name, property)
+			}
+		}
+	})
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(t, apiServer.URL)
+			cfg.PrettyJSON = tt.prettyConfig
+			server := newTopicsTestServer(t, cfg)
+
+			request := mcp.CallToolRequest{}
+			args := map[string]interface{}{
+				"resource":   "topics",
+				"topic_name": fmt.Sprintf("orders-%d", i),
+			}
+			if tt.prettyArg != nil {
+				args["pretty"] = tt.prettyArg
+			}
+			request.Params.Arguments = args
+
+			result, err := server.createToolHandler(tools.ActionGet)(context.Background(), request)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			text := result.Content[0].(mcp.TextContent).Text
+
+			if indented := strings.Contains(text, "\n"+PrettyJSONIndent+`"`); indented != tt.expectIndent {
+				t.Errorf("Expected indented=%v, got output %q", tt.expectIndent, text)
+			}
+			var parsed map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &parsed); err != nil {
+				t.Errorf("Output is not valid JSON: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolHandlerUnwrapData(t *testing.T) {
+	enveloped := `{"data":[{"topic_name":"orders"}],"metadata":{"next":"https://example.com/page2"}}`
+	plain := `{"topic_name":"orders"}`
+
+	tests := []struct {
+		name          string
+		response      string
+		unwrapConfig  bool
+		unwrapArg     interface{}
+		expectUnwrap  bool
+		expectContent int
+	}{
+		{name: "Envelope kept by default", response: enveloped, expectUnwrap: false, expectContent: 1},
+		{name: "Unwrapped when UNWRAP_DATA is set", response: enveloped, unwrapConfig: true, expectUnwrap: true, expectContent: 2},
+		{name: "Per-call unwrap_data overrides default", response: enveloped, unwrapArg: true, expectUnwrap: true, expectContent: 2},
+		{name: "Per-call unwrap_data=false overrides UNWRAP_DATA", response: enveloped, unwrapConfig: true, unwrapArg: false, expectUnwrap: false, expectContent: 1},
+		{name: "Non-enveloped response untouched", response: plain, unwrapConfig: true, expectUnwrap: false, expectContent: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.response))
+			}))
+			defer apiServer.Close()
+
+			cfg := newTestConfig(t, apiServer.URL)
+			cfg.UnwrapData = tt.unwrapConfig
+			server := newTopicsTestServer(t, cfg)
+
+			request := mcp.CallToolRequest{}
+			args := map[string]interface{}{"resource": "topics"}
+			if tt.unwrapArg != nil {
+				args["unwrap_data"] = tt.unwrapArg
+			}
+			request.Params.Arguments = args
+
+			result, err := server.createToolHandler(tools.ActionList)(context.Background(), request)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(result.Content) != tt.expectContent {
+				t.Fatalf("Expected %d content items, got %d", tt.expectContent, len(result.Content))
+			}
+
+			var first interface{}
+			if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &first); err != nil {
+				t.Fatalf("Output is not valid JSON: %v", err)
+			}
+			if !tt.expectUnwrap {
+				firstMap, ok := first.(map[string]interface{})
+				if !ok {
+					t.Fatalf("Expected the response object, got %v", first)
+				}
+				if _, hasData := firstMap["data"]; hasData != strings.Contains(tt.response, `"data"`) {
+					t.Errorf("Expected the response to be returned unchanged, got %v", firstMap)
+				}
+				return
+			}
+
+			items, ok := first.([]interface{})
+			if !ok || len(items) != 1 {
+				t.Fatalf("Expected the data array as the first result, got %v", first)
+			}
+			var envelope map[string]interface{}
+			if err := json.Unmarshal([]byte(result.Content[1].(mcp.TextContent).Text), &envelope); err != nil {
+				t.Fatalf("Envelope is not valid JSON: %v", err)
+			}
+			metadata, ok := envelope["metadata"].(map[string]interface{})
+			if !ok || metadata["next"] != "https://example.com/page2" {
+				t.Errorf("Expected metadata to be preserved, got %v", envelope)
+			}
+			if _, hasData := envelope["data"]; hasData {
+				t.Error("Expected data to be removed from the envelope")
+			}
+		})
+	}
+}
+
+func TestInvokeToolLoopCooldown(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer apiServer.Close()
+
+	t.Setenv("LOOP_DETECTION_MAX_CONSECUTIVE", "2")
+	t.Setenv("LOOP_DETECTION_COOLDOWN", "30")
+	server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+
+	var resp InvokeResponse
+	for i := 0; i < 3; i++ {
+		resp = server.InvokeTool(InvokeRequest{
+			Tool:      tools.ActionList,
+			Arguments: map[string]interface{}{"resource": "topics", "cluster_id": "lkc-1"},
+		})
+	}
+
+	if resp.Error == "" || resp.Cooldown == nil {
+		t.Fatalf("Expected the third call to be blocked with a cooldown, got %+v", resp)
+	}
+	cooldownUntil, err := time.Parse(time.RFC3339, resp.Cooldown.CooldownUntil)
+	if err != nil {
+		t.Fatalf("Expected an RFC3339 cooldown_until, got %q", resp.Cooldown.CooldownUntil)
+	}
+	if !cooldownUntil.After(time.Now()) {
+		t.Errorf("Expected cooldown_until in the future, got %s", cooldownUntil)
+	}
+	if resp.Cooldown.RetryAfterSeconds < 1 || resp.Cooldown.RetryAfterSeconds > 30 {
+		t.Errorf("Expected retry_after_seconds within the cooldown, got %d", resp.Cooldown.RetryAfterSeconds)
+	}
+
+	t.Run("Tool result carries the cooldown as JSON", func(t *testing.T) {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"resource": "topics", "cluster_id": "lkc-1"}
+
+		result, err := server.createToolHandler(tools.ActionList)(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Content) != 2 {
+			t.Fatalf("Expected the error and cooldown content, got %v", result.Content)
+		}
+		var cooldown map[string]interface{}
+		if err := json.Unmarshal([]byte(result.Content[1].(mcp.TextContent).Text), &cooldown); err != nil {
+			t.Fatalf("Cooldown is not valid JSON: %v", err)
+		}
+		if cooldown["cooldown_until"] == nil || cooldown["retry_after_seconds"] == nil {
+			t.Errorf("Expected cooldown_until and retry_after_seconds, got %v", cooldown)
+		}
+	})
+}
+
+func TestInvokeToolStrictArgs(t *testing.T) {
+	apiCalls := 0
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiCalls++
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	tests := []struct {
+		name        string
+		strict      bool
+		args        map[string]interface{}
+		expectError string
+	}{
+		{
+			name:        "Bogus argument is rejected in strict mode",
+			strict:      true,
+			args:        map[string]interface{}{"resource": "topics", "cluster_id": "lkc-1", "topic_name": "orders", "include_secrets": true},
+			expectError: "Unknown arguments for get topics: include_secrets. Valid arguments: cluster_id, topic_name",
+		},
+		{
+			name:   "Known arguments pass in strict mode",
+			strict: true,
+			args:   map[string]interface{}{"resource": "topics", "cluster_id": "lkc-1", "topic_name": "orders", "profile": "default"},
+		},
+		{
+			name:   "Bogus argument passes through without strict mode",
+			strict: false,
+			args:   map[string]interface{}{"resource": "topics", "cluster_id": "lkc-1", "topic_name": "orders", "include_secrets": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiCalls = 0
+			cfg := newTestConfig(t, apiServer.URL)
+			cfg.StrictArgs = tt.strict
+			server := newTopicsTestServer(t, cfg)
+
+			resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: tt.args})
+			if tt.expectError != "" {
+				if resp.Error != tt.expectError {
+					t.Errorf("Expected error %q, got %q", tt.expectError, resp.Error)
+				}
+				if apiCalls != 0 {
+					t.Errorf("Expected no API call, got %d", apiCalls)
+				}
+				return
+			}
+			if resp.Error != "" {
+				t.Fatalf("Unexpected error: %s", resp.Error)
+			}
+			if apiCalls != 1 {
+				t.Errorf("Expected one API call, got %d", apiCalls)
+			}
+		})
+	}
+}
+
+func TestInvokeToolValidateIDFormats(t *testing.T) {
+	apiCalls := 0
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiCalls++
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	tests := []struct {
+		name        string
+		validate    bool
+		args        map[string]interface{}
+		expectError string
+	}{
+		{
+			name:     "Valid ids pass",
+			validate: true,
+			args:     map[string]interface{}{"resource": "topics", "cluster_id": "lkc-1", "topic_name": "orders", "environment": "env-1"},
+		},
+		{
+			name:        "Environment id passed as cluster id is rejected",
+			validate:    true,
+			args:        map[string]interface{}{"resource": "topics", "cluster_id": "env-1", "topic_name": "orders"},
+			expectError: "Invalid id arguments for get topics: cluster_id 'env-1' must start with 'lkc-'",
+		},
+		{
+			name:        "Every invalid id is reported",
+			validate:    true,
+			args:        map[string]interface{}{"resource": "topics", "cluster_id": "lkc-1", "topic_name": "orders", "environment_id": "orders", "pool_id": "lkc-1"},
+			expectError: "Invalid id arguments for get topics: environment_id 'orders' must start with 'env-'; pool_id 'lkc-1' must start with 'lfcp-'",
+		},
+		{
+			name:     "Invalid ids pass through without validation",
+			validate: false,
+			args:     map[string]interface{}{"resource": "topics", "cluster_id": "env-1", "topic_name": "orders"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiCalls = 0
+			cfg := newTestConfig(t, apiServer.URL)
+			cfg.ValidateIDFormats = tt.validate
+			server := newTopicsTestServer(t, cfg)
+
+			resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: tt.args})
+			if tt.expectError != "" {
+				if resp.Error != tt.expectError {
+					t.Errorf("Expected error %q, got %q", tt.expectError, resp.Error)
+				}
+				if apiCalls != 0 {
+					t.Errorf("Expected no API call, got %d", apiCalls)
+				}
+				return
+			}
+			if resp.Error != "" {
+				t.Fatalf("Unexpected error: %s", resp.Error)
+			}
+			if apiCalls != 1 {
+				t.Errorf("Expected one API call, got %d", apiCalls)
+			}
+		})
+	}
+}
+
+func TestInvokeToolRejectsUnsupportedResource(t *testing.T) {
+	apiCalls := 0
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiCalls++
+		w.Write([]byte(`{}`))
+	}))
+	defer apiServer.Close()
+
+	server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		expectError string
+	}{
+		{
+			name:        "Unknown resource lists valid resources",
+			args:        map[string]interface{}{"resource": "widgets"},
+			expectError: "Unsupported resource 'widgets' for 'get'. Valid resources: topics",
+		},
+		{
+			name:        "Missing resource lists valid resources",
+			args:        map[string]interface{}{"topic_name": "orders"},
+			expectError: "Missing 'resource' argument for 'get'. Valid resources: topics",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: tt.args})
+			if resp.Error != tt.expectError {
+				t.Errorf("Expected error %q, got %q", tt.expectError, resp.Error)
+			}
+		})
+	}
+
+	if apiCalls != 0 {
+		t.Errorf("Expected no API calls for invalid resources, got %d", apiCalls)
+	}
+}
+
+func TestInvokeToolResolvesNestedPathParameters(t *testing.T) {
+	var receivedPath string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"partition_id":0}`))
+	}))
+	defer apiServer.Close()
+
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+	spec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics/{topic_name}/partitions/{partition_id}": {
+				Get: &openapi.Operation{Summary: "Get partition"},
+			},
+		},
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+
+	t.Run("Outer parameters come from config", func(t *testing.T) {
+		server := NewCompositeServer(newTestConfig(t, apiServer.URL), spec, &openapi.OpenAPISpec{}, semanticTools)
+		resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: map[string]interface{}{
+			"resource":     "partitions",
+			"topic_name":   "orders",
+			"partition_id": 0,
+		}})
+		if resp.Error != "" {
+			t.Fatalf("Unexpected error: %s", resp.Error)
+		}
+		expected := "/kafka/v3/clusters/lkc-test/topics/orders/partitions/0"
+		if receivedPath != expected {
+			t.Errorf("Expected path %q, got %q", expected, receivedPath)
+		}
+	})
+
+	t.Run("Unresolvable parameters are listed", func(t *testing.T) {
+		cfg := newTestConfig(t, apiServer.URL)
+		cfg.KafkaClusterID = ""
+		server := NewCompositeServer(cfg, spec, &openapi.OpenAPISpec{}, semanticTools)
+
+		missing := server.resolvePathParameters("/kafka/v3/clusters/{cluster_id}/topics/{topic_name}/partitions/{partition_id}",
+			map[string]interface{}{"partition_id": 0})
+		if !reflect.DeepEqual(missing, []string{"cluster_id", "topic_name"}) {
+			t.Errorf("Expected missing [cluster_id topic_name], got %v", missing)
+		}
+	})
+}
+
+func TestInvokeToolAcceptsResourceAliases(t *testing.T) {
+	var receivedPath string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	server := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+
+	for _, alias := range []string{"topics", "topic", "Topic"} {
+		t.Run(alias, func(t *testing.T) {
+			mapping, err := tools.GetEndpointMapping(tools.ActionGet, alias)
+			if err != nil {
+				t.Fatalf("Expected a mapping for %q: %v", alias, err)
+			}
+			if mapping.PathPattern != "/kafka/v3/clusters/{cluster_id}/topics/{topic_name}" {
+				t.Errorf("Unexpected mapping path %s", mapping.PathPattern)
+			}
+
+			args := map[string]interface{}{"resource": alias, "topic_name": "orders"}
+			resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: args})
+			if resp.Error != "" {
+				t.Fatalf("Unexpected error: %s", resp.Error)
+			}
+			if receivedPath != "/kafka/v3/clusters/lkc-test/topics/orders" {
+				t.Errorf("Unexpected request path %s", receivedPath)
+			}
+			if args["resource"] != "topics" {
+				t.Errorf("Expected resource to be rewritten to 'topics', got %v", args["resource"])
+			}
+		})
+	}
+}
+
+func TestInvokeToolSchemaRegistrySettings(t *testing.T) {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+
+	var receivedMethod, receivedPath, receivedUser string
+	var receivedBody map[string]interface{}
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedMethod, receivedPath = r.Method, r.URL.Path
+		receivedUser, _, _ = r.BasicAuth()
+		receivedBody = nil
+		json.NewDecoder(r.Body).Decode(&receivedBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer apiServer.Close()
+
+	spec, err := openapi.ParseOpenAPISpecBytes([]byte(`{
+		"openapi": "3.0.0",
+		"paths": {
+			"/mode": {"put": {
+				"security": [{"resource-api-key": []}],
+				"requestBody": {"content": {"application/vnd.schemaregistry.v1+json": {"schema": {"$ref": "#/components/schemas/ModeUpdateRequest"}}}}
+			}},
+			"/mode/{subject}": {"put": {
+				"security": [{"resource-api-key": []}],
+				"parameters": [{"name": "subject", "in": "path", "required": true, "schema": {"type": "string"}}],
+				"requestBody": {"content": {"application/vnd.schemaregistry.v1+json": {"schema": {"$ref": "#/components/schemas/ModeUpdateRequest"}}}}
+			}},
+			"/config": {"put": {
+				"security": [{"resource-api-key": []}],
+				"requestBody": {"content": {"application/vnd.schemaregistry.v1+json": {"schema": {"$ref": "#/components/schemas/ConfigUpdateRequest"}}}}
+			}},
+			"/config/{subject}": {"put": {
+				"security": [{"resource-api-key": []}],
+				"parameters": [{"name": "subject", "in": "path", "required": true, "schema": {"type": "string"}}],
+				"requestBody": {"content": {"application/vnd.schemaregistry.v1+json": {"schema": {"$ref": "#/components/schemas/ConfigUpdateRequest"}}}}
+			}}
+		},
+		"components": {
+			"schemas": {
+				"ModeUpdateRequest": {"type": "object", "required": ["mode"], "properties": {"mode": {"type": "string"}}},
+				"ConfigUpdateRequest": {"type": "object", "properties": {"compatibility": {"type": "string"}, "normalize": {"type": "boolean"}}}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+	server := NewCompositeServer(newTestConfig(t, apiServer.URL), spec, &openapi.OpenAPISpec{}, semanticTools)
+
+	tests := []struct {
+		name          string
+		args          map[string]interface{}
+		expectPath    string
+		expectBody    map[string]interface{}
+		expectMissing string
+	}{
+		{
+			name:       "Global mode",
+			args:       map[string]interface{}{"resource": "mode", "mode": "READONLY"},
+			expectPath: "/mode",
+			expectBody: map[string]interface{}{"mode": "READONLY"},
+		},
+		{
+			name:       "Subject mode",
+			args:       map[string]interface{}{"resource": "mode", "mode": "IMPORT", "subject": "orders-value"},
+			expectPath: "/mode/orders-value",
+			expectBody: map[string]interface{}{"mode": "IMPORT"},
+		},
+		{
+			name:       "Global compatibility",
+			args:       map[string]interface{}{"resource": "config", "compatibility": "FULL"},
+			expectPath: "/config",
+			expectBody: map[string]interface{}{"compatibility": "FULL"},
+		},
+		{
+			name:       "Subject compatibility",
+			args:       map[string]interface{}{"resource": "config", "compatibility": "BACKWARD", "subject": "orders-value"},
+			expectPath: "/config/orders-value",
+			expectBody: map[string]interface{}{"compatibility": "BACKWARD"},
+		},
+		{
+			name:       "Compatibility is optional when the spec does not require it",
+			args:       map[string]interface{}{"resource": "config", "normalize": true},
+			expectPath: "/config",
+			expectBody: map[string]interface{}{"normalize": true},
+		},
+		{
+			name:          "Missing required setting value",
+			args:          map[string]interface{}{"resource": "mode"},
+			expectMissing: "mode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receivedPath = ""
+			resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionUpdate, Arguments: tt.args})
+			if resp.Error != "" {
+				t.Fatalf("Unexpected error: %s", resp.Error)
+			}
+
+			if tt.expectMissing != "" {
+				result, _ := resp.Result.(map[string]interface{})
+				if result["status"] != "missing_required_params" || !reflect.DeepEqual(result["requiredParams"], []string{tt.expectMissing}) {
+					t.Errorf("Expected %s to be reported missing, got %v", tt.expectMissing, resp.Result)
+				}
+				if receivedPath != "" {
+					t.Error("Expected no API call without the setting value")
+				}
+				return
+			}
+
+			if receivedMethod != http.MethodPut || receivedPath != tt.expectPath {
+				t.Errorf("Expected PUT %s, got %s %s", tt.expectPath, receivedMethod, receivedPath)
+			}
+			if !reflect.DeepEqual(receivedBody, tt.expectBody) {
+				t.Errorf("Expected body %v, got %v", tt.expectBody, receivedBody)
+			}
+			if receivedUser != "test-sr-key" {
+				t.Errorf("Expected Schema Registry credentials, got key %q", receivedUser)
+			}
+		})
+	}
+}
+
+func TestInvokeToolConfiguredResourceIDParam(t *testing.T) {
+	var receivedPath, receivedQuery string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		receivedQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"gizmo_ref":"g-1"}`))
+	}))
+	defer apiServer.Close()
+
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+	// Only the item path: its collection would map to get as well, as no heuristic knows gizmo_ref
+	spec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/gizmos/{gizmo_ref}": {
+				Get: &openapi.Operation{Summary: "Get gizmo"},
+			},
+		},
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+	args := func() map[string]interface{} {
+		return map[string]interface{}{"resource": "gizmos", "id": "g-1"}
+	}
+
+	server := NewCompositeServer(newTestConfig(t, apiServer.URL), spec, &openapi.OpenAPISpec{}, semanticTools)
+	resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: args()})
+	if result, _ := resp.Result.(map[string]interface{}); result["status"] != "missing_required_params" {
+		t.Fatalf("Expected gizmo_ref to be missing without configuration, got %+v", resp)
+	}
+
+	tools.SetResourceIDParams(map[string]string{"gizmo": "gizmo_ref"})
+	t.Cleanup(func() { tools.SetResourceIDParams(nil) })
+
+	for _, strict := range []bool{false, true} {
+		t.Run(fmt.Sprintf("strict=%v", strict), func(t *testing.T) {
+			receivedPath, receivedQuery = "", ""
+			cfg := newTestConfig(t, apiServer.URL)
+			cfg.StrictArgs = strict
+			server := NewCompositeServer(cfg, spec, &openapi.OpenAPISpec{}, semanticTools)
+
+			resp := server.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: args()})
+			if resp.Error != "" {
+				t.Fatalf("Unexpected error: %s", resp.Error)
+			}
+			if expected := "/kafka/v3/clusters/lkc-test/gizmos/g-1"; receivedPath != expected {
+				t.Errorf("Expected path %q, got %q", expected, receivedPath)
+			}
+			if query, _ := url.ParseQuery(receivedQuery); query.Has("id") {
+				t.Errorf("Expected the generic id not to be sent upstream, got query %q", receivedQuery)
+			}
+		})
+	}
+}
+
+func TestCreateToolHandlerReturnsResourceURI(t *testing.T) {
+	t.Setenv("DISABLE_RESOURCE_DISCOVERY", "true")
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"orders","kind":"KafkaTopic"}`))
+	}))
+	defer apiServer.Close()
+
+	spec := &openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Post: &openapi.Operation{Summary: "Create topic"},
+			},
+		},
+	}
+	semanticTools, err := tools.GenerateSemanticTools(*spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+	server := NewCompositeServer(newTestConfig(t, apiServer.URL), spec, &openapi.OpenAPISpec{}, semanticTools)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"resource":   "topics",
+		"parameters": map[string]interface{}{"topic_name": "orders"},
+	}
+	result, err := server.createToolHandler(tools.ActionCreate)(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("Expected the result and the resource URI, got %v", result.Content)
+	}
+	uri, ok := result.Content[1].(mcp.TextContent)
+	if !ok || uri.Text != "Resource URI: confluent://topics/orders" {
+		t.Errorf("Expected the registered resource URI, got %v", result.Content[1])
+	}
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"encoding/json"
+	"mcolomerc/mcp-server/internal/openapi"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolsExport is the generated tool set with the endpoint mappings behind it. Tools are sorted by
+// name and map keys are sorted when encoded, so exports of two spec versions can be diffed.
+type ToolsExport struct {
+	Spec          ExportedSpec                                  `json:"spec"`
+	TelemetrySpec ExportedSpec                                  `json:"telemetry_spec"`
+	Tools         []ExportedTool                                `json:"tools"`
+	Mappings      map[string]map[string]ExportedEndpointMapping `json:"mappings"` // action -> resource -> mapping
+}
+
+// ExportedSpec identifies the spec a tool set was generated from
+type ExportedSpec struct {
+	Title   string `json:"title,omitempty"`
+	Version string `json:"version,omitempty"`
+	OpenAPI string `json:"openapi,omitempty"`
+}
+
+// ExportedTool is a generated tool as advertised to MCP clients
+type ExportedTool struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	InputSchema mcp.ToolInputSchema `json:"input_schema"`
+	Services    []string            `json:"services,omitempty"`
+}
+
+// ExportedEndpointMapping is the resolved endpoint of an action and resource
+type ExportedEndpointMapping struct {
+	Method             string                 `json:"method"`
+	PathPattern        string                 `json:"path_pattern"`
+	SubjectPathPattern string                 `json:"subject_path_pattern,omitempty"`
+	RequiredParams     []string               `json:"required_params,omitempty"`
+	OptionalParams     []string               `json:"optional_params,omitempty"`
+	RequestBody        map[string]interface{} `json:"request_body,omitempty"` // schema, contentType and example
+	Deprecated         bool                   `json:"deprecated,omitempty"`
+	Service            string                 `json:"service,omitempty"`
+	ResponseArrayField string                 `json:"response_array_field,omitempty"`
+	ResponseItemFields []string               `json:"response_item_fields,omitempty"`
+}
+
+// exportSpec identifies a spec for the export
+func exportSpec(spec *openapi.OpenAPISpec) ExportedSpec {
+	if spec == nil {
+		return ExportedSpec{}
+	}
+	return ExportedSpec{Title: spec.Info.Title, Version: spec.Info.Version, OpenAPI: spec.OpenAPI}
+}
+
+// exportTools builds the export of the generated tools and their endpoint mappings
+func (s *MCPServer) exportTools() ToolsExport {
+	export := ToolsExport{
+		Spec:          exportSpec(s.spec),
+		TelemetrySpec: exportSpec(s.telemetrySpec),
+		Tools:         make([]ExportedTool, 0, len(s.tools)),
+		Mappings:      make(map[string]map[string]ExportedEndpointMapping),
+	}
+
+	for _, tool := range s.tools {
+		mcpTool := s.advertisedTool(tool)
+		export.Tools = append(export.Tools, ExportedTool{
+			Name:        mcpTool.Name,
+			Description: mcpTool.Description,
+			InputSchema: mcpTool.InputSchema,
+			Services:    tool.Services,
+		})
+
+		for _, resource := range tools.GetSupportedResources(tool.Name) {
+			mapping, err := tools.GetEndpointMapping(tool.Name, resource)
+			if err != nil {
+				continue
+			}
+			required := append([]string(nil), mapping.RequiredParams...)
+			sort.Strings(required)
+			optional := append([]string(nil), mapping.OptionalParams...)
+			sort.Strings(optional)
+
+			if export.Mappings[tool.Name] == nil {
+				export.Mappings[tool.Name] = make(map[string]ExportedEndpointMapping)
+			}
+			export.Mappings[tool.Name][resource] = ExportedEndpointMapping{
+				Method:             mapping.Method,
+				PathPattern:        mapping.PathPattern,
+				SubjectPathPattern: mapping.SubjectPathPattern,
+				RequiredParams:     required,
+				OptionalParams:     optional,
+				RequestBody:        mapping.RequestBodySchema,
+				Deprecated:         mapping.Deprecated,
+				Service:            mapping.Service,
+				ResponseArrayField: mapping.ResponseArrayField,
+				ResponseItemFields: mapping.ResponseItemFields,
+			}
+		}
+	}
+	sort.Slice(export.Tools, func(i, j int) bool { return export.Tools[i].Name < export.Tools[j].Name })
+
+	return export
+}
+
+// ToolsExportHandler serves the generated tools, their input schemas and the endpoint mappings
+// behind them as a downloadable JSON document
+func (s *MCPServer) ToolsExportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jsonData, err := json.MarshalIndent(s.exportTools(), "", PrettyJSONIndent)
+	if err != nil {
+		http.Error(w, "Failed to marshal tools", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(HeaderContentType, ContentTypeJSON)
+	w.Header().Set(HeaderContentDisposition, `attachment; filename="`+ToolsExportFileName+`"`)
+	w.Write(jsonData)
+}
+
+// ToolGroups lists the tools by service so clients can display them grouped
+type ToolGroups struct {
+	Services map[string]map[string][]string `json:"services"` // service -> tool -> resources of the service
+}
+
+// groupTools groups the generated tools by the service of each resource they handle; tools
+// without resource mappings are listed under their own services, custom tools under "custom"
+func (s *MCPServer) groupTools() ToolGroups {
+	groups := ToolGroups{Services: make(map[string]map[string][]string)}
+	add := func(service, toolName string, resources ...string) {
+		if groups.Services[service] == nil {
+			groups.Services[service] = make(map[string][]string)
+		}
+		groups.Services[service][toolName] = append(groups.Services[service][toolName], resources...)
+	}
+
+	for _, tool := range s.tools {
+		name := s.exposedToolName(tool.Name)
+		resources := tools.GetSupportedResources(tool.Name)
+		if len(resources) == 0 {
+			for _, service := range tool.Services {
+				add(service, name)
+			}
+			continue
+		}
+		for _, resource := range resources {
+			mapping, err := tools.GetEndpointMapping(tool.Name, resource)
+			if err != nil {
+				continue
+			}
+			service := mapping.Service
+			if service == "" {
+				service = tools.ServiceForPath(mapping.PathPattern)
+			}
+			add(service, name, resource)
+		}
+	}
+	for name := range s.customTools {
+		add(ToolGroupCustom, name)
+	}
+	return groups
+}
+
+// ToolsHandler serves the tools grouped by service
+func (s *MCPServer) ToolsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jsonData, err := json.MarshalIndent(s.groupTools(), "", PrettyJSONIndent)
+	if err != nil {
+		http.Error(w, "Failed to marshal tools", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(HeaderContentType, ContentTypeJSON)
+	w.Write(jsonData)
+}
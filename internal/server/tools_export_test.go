@@ -0,0 +1,84 @@
+package server
+
+import (
+	"encoding/json"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestToolsExportHandler(t *testing.T) {
+	s := newTopicsTestServer(t, newTestConfig(t, "http://localhost"))
+
+	recorder := httptest.NewRecorder()
+	s.ToolsExportHandler(recorder, httptest.NewRequest(http.MethodGet, "/tools/export", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+	if disposition := recorder.Header().Get(HeaderContentDisposition); !strings.Contains(disposition, ToolsExportFileName) {
+		t.Errorf("Expected an attachment named %s, got %q", ToolsExportFileName, disposition)
+	}
+
+	var export ToolsExport
+	if err := json.Unmarshal(recorder.Body.Bytes(), &export); err != nil {
+		t.Fatalf("Failed to decode export: %v", err)
+	}
+	if export.Spec.OpenAPI != "3.0.0" {
+		t.Errorf("Expected the spec version in the export, got %+v", export.Spec)
+	}
+
+	if len(export.Tools) == 0 {
+		t.Fatal("Expected generated tools in the export")
+	}
+	for i, tool := range export.Tools {
+		if i > 0 && export.Tools[i-1].Name > tool.Name {
+			t.Errorf("Expected tools sorted by name, got %s before %s", export.Tools[i-1].Name, tool.Name)
+		}
+		if tool.InputSchema.Type != "object" {
+			t.Errorf("Expected an object input schema for %s, got %q", tool.Name, tool.InputSchema.Type)
+		}
+	}
+
+	update, ok := export.Mappings[tools.ActionUpdate]["topics"]
+	if !ok {
+		t.Fatalf("Expected an update mapping for topics, got %v", export.Mappings)
+	}
+	if update.Method != http.MethodPatch || update.PathPattern != "/kafka/v3/clusters/{cluster_id}/topics/{topic_name}" {
+		t.Errorf("Unexpected update mapping: %+v", update)
+	}
+	if _, ok := export.Mappings[tools.ActionList]["topics"]; !ok {
+		t.Errorf("Expected a list mapping for topics, got %v", export.Mappings[tools.ActionList])
+	}
+
+	recorder = httptest.NewRecorder()
+	s.ToolsExportHandler(recorder, httptest.NewRequest(http.MethodPost, "/tools/export", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 for POST, got %d", recorder.Code)
+	}
+}
+
+func TestToolsHandlerGroupsByService(t *testing.T) {
+	s := newTopicsTestServer(t, newTestConfig(t, "http://localhost"))
+
+	recorder := httptest.NewRecorder()
+	s.ToolsHandler(recorder, httptest.NewRequest(http.MethodGet, "/tools", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+
+	var groups ToolGroups
+	if err := json.Unmarshal(recorder.Body.Bytes(), &groups); err != nil {
+		t.Fatalf("Failed to decode tool groups: %v", err)
+	}
+	kafka := groups.Services[tools.ServiceKafka]
+	for _, action := range []string{tools.ActionList, tools.ActionGet, tools.ActionUpdate, tools.ActionDelete} {
+		if resources := kafka[action]; len(resources) != 1 || resources[0] != "topics" {
+			t.Errorf("Expected %s topics in the kafka group, got %v", action, resources)
+		}
+	}
+	if len(groups.Services) != 1 {
+		t.Errorf("Expected only the kafka group, got %v", groups.Services)
+	}
+}
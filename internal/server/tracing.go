@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"mcolomerc/mcp-server/internal/tracing"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startInvokeSpan starts the span of a tool invocation as a child of the request context
+func startInvokeSpan(req InvokeRequest) (context.Context, trace.Span) {
+	parent := req.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	resource, _ := req.Arguments["resource"].(string)
+	return tracing.Tracer().Start(parent, "InvokeTool "+req.Tool, trace.WithAttributes(
+		attribute.String(SpanAttrTool, req.Tool),
+		attribute.String(SpanAttrAction, req.Tool),
+		attribute.String(SpanAttrResource, resource),
+	))
+}
+
+// endInvokeSpan ends the span of a tool invocation, marking failed invocations as errors
+func endInvokeSpan(span trace.Span, resp InvokeResponse) {
+	if resp.Error != "" {
+		recordSpanError(span, errors.New(resp.Error))
+	}
+	span.End()
+}
+
+// startAPICallSpan starts a client span for an API request as a child of the invocation span
+func startAPICallSpan(parent context.Context, method, fullURL string) (context.Context, trace.Span) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	attributes := []attribute.KeyValue{semconv.HTTPRequestMethodKey.String(method)}
+	if parsed, err := url.Parse(fullURL); err == nil {
+		attributes = append(attributes, semconv.ServerAddress(parsed.Hostname()))
+	}
+	return tracing.Tracer().Start(parent, method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+}
+
+// recordSpanError marks a span as failed
+func recordSpanError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"mcolomerc/mcp-server/internal/tools"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// useInMemoryTracing installs a tracer provider recording spans in memory for the test
+func useInMemoryTracing(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return exporter
+}
+
+// spanAttribute returns the value of a span attribute, or an empty value when it is not set
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestInvokeToolTracing(t *testing.T) {
+	var traceparent string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"topic_name":"orders"}`))
+	}))
+	defer apiServer.Close()
+
+	exporter := useInMemoryTracing(t)
+	s := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+
+	resp := s.InvokeTool(InvokeRequest{Tool: tools.ActionGet, Arguments: map[string]interface{}{
+		"resource":   "topics",
+		"topic_name": "orders",
+	}})
+	if resp.Error != "" {
+		t.Fatalf("Unexpected error: %s", resp.Error)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected an invocation span and an API call span, got %d spans", len(spans))
+	}
+	// Child spans end first
+	apiSpan, invokeSpan := spans[0], spans[1]
+
+	if invokeSpan.Name != "InvokeTool get" {
+		t.Errorf("Expected invocation span 'InvokeTool get', got '%s'", invokeSpan.Name)
+	}
+	if invokeSpan.Parent.IsValid() {
+		t.Error("Expected the invocation span to be a root span")
+	}
+	for key, expected := range map[attribute.Key]string{SpanAttrTool: "get", SpanAttrAction: "get", SpanAttrResource: "topics"} {
+		if value := spanAttribute(invokeSpan, key).AsString(); value != expected {
+			t.Errorf("Expected %s=%s on the invocation span, got '%s'", key, expected, value)
+		}
+	}
+
+	if apiSpan.Parent.SpanID() != invokeSpan.SpanContext.SpanID() {
+		t.Error("Expected the API call span to be a child of the invocation span")
+	}
+	if apiSpan.SpanKind != trace.SpanKindClient {
+		t.Errorf("Expected a client span for the API call, got %v", apiSpan.SpanKind)
+	}
+	host := mustHostname(t, apiServer.URL)
+	if value := spanAttribute(apiSpan, "server.address").AsString(); value != host {
+		t.Errorf("Expected server.address=%s, got '%s'", host, value)
+	}
+	if value := spanAttribute(apiSpan, "http.request.method").AsString(); value != "GET" {
+		t.Errorf("Expected http.request.method=GET, got '%s'", value)
+	}
+	if value := spanAttribute(apiSpan, "http.response.status_code").AsInt64(); value != http.StatusOK {
+		t.Errorf("Expected http.response.status_code=200, got %d", value)
+	}
+
+	// The API receives the trace context of the API call span
+	expected := "00-" + apiSpan.SpanContext.TraceID().String() + "-" + apiSpan.SpanContext.SpanID().String() + "-01"
+	if traceparent != expected {
+		t.Errorf("Expected traceparent %s, got '%s'", expected, traceparent)
+	}
+}
+
+func TestContinuationCallTracing(t *testing.T) {
+	var apiServer *httptest.Server
+	apiServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page_token") == "" {
+			fmt.Fprintf(w, `{"data":[{"topic_name":"a"}],"metadata":{"next":"%s/kafka/v3/clusters/lkc-test/topics?page_token=p2"}}`, apiServer.URL)
+			return
+		}
+		w.Write([]byte(`{"data":[{"topic_name":"b"}],"metadata":{"next":""}}`))
+	}))
+	defer apiServer.Close()
+
+	s := newTopicsTestServer(t, newTestConfig(t, apiServer.URL))
+	first := s.InvokeTool(InvokeRequest{Tool: tools.ActionList, SessionID: "session-1", Arguments: map[string]interface{}{
+		"resource": "topics",
+		"paginate": true,
+	}})
+	token, _ := first.Result.(map[string]interface{})[ParamContinuationToken].(string)
+	if token == "" {
+		t.Fatalf("Expected a continuation token, got %v (%s)", first.Result, first.Error)
+	}
+
+	exporter := useInMemoryTracing(t)
+	resp := s.InvokeTool(InvokeRequest{Tool: tools.ActionList, SessionID: "session-1", Arguments: map[string]interface{}{
+		"resource":           "topics",
+		"continuation_token": token,
+	}})
+	if resp.Error != "" {
+		t.Fatalf("Unexpected error: %s", resp.Error)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected an invocation span and an API call span, got %d spans", len(spans))
+	}
+	if apiSpan, invokeSpan := spans[0], spans[1]; apiSpan.Parent.SpanID() != invokeSpan.SpanContext.SpanID() {
+		t.Error("Expected the continuation call span to be a child of the invocation span")
+	}
+}
+
+func mustHostname(t *testing.T, rawURL string) string {
+	t.Helper()
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("Invalid URL %s: %v", rawURL, err)
+	}
+	return parsed.Hostname()
+}
@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ActionRule maps endpoints whose path ends with Suffix to an extra semantic action
+type ActionRule struct {
+	Action string // Semantic action (tool) name, e.g. "rotate"
+	Method string // HTTP method the rule applies to
+	Suffix string // Path suffix that identifies the action, e.g. ":rotate" or "/restart"
+}
+
+// actionNamePattern restricts custom action names to simple tool-friendly identifiers
+var actionNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+var (
+	semanticActionRules []ActionRule
+	actionRulesMutex    sync.RWMutex
+)
+
+// ParseSemanticActionRules parses rules of the form "action=suffix" or "action=METHOD suffix".
+// The method defaults to POST, e.g. "rotate=:rotate" or "restart=PUT /restart".
+func ParseSemanticActionRules(entries []string) ([]ActionRule, error) {
+	var rules []ActionRule
+	for _, entry := range entries {
+		action, target, found := strings.Cut(entry, "=")
+		action = strings.TrimSpace(action)
+		target = strings.TrimSpace(target)
+		if !found || action == "" || target == "" {
+			return nil, fmt.Errorf("invalid semantic action rule '%s': expected action=suffix", entry)
+		}
+
+		if !actionNamePattern.MatchString(action) {
+			return nil, fmt.Errorf("invalid semantic action name '%s': use lowercase letters, digits and underscores", action)
+		}
+		if isBuiltInSemanticAction(action) {
+			return nil, fmt.Errorf("semantic action '%s' is built in and cannot be redefined", action)
+		}
+
+		rule := ActionRule{Action: action, Method: HTTPMethodPost, Suffix: target}
+		if method, suffix, hasMethod := strings.Cut(target, " "); hasMethod {
+			rule.Method = strings.ToUpper(strings.TrimSpace(method))
+			rule.Suffix = strings.TrimSpace(suffix)
+		}
+		if rule.Suffix == "" {
+			return nil, fmt.Errorf("invalid semantic action rule '%s': missing path suffix", entry)
+		}
+
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// WithoutReservedActions drops the rules whose action would take the name of another tool, such as
+// get_telemetry or one of the reserved names, with a warning for each
+func WithoutReservedActions(rules []ActionRule, reserved []string) []ActionRule {
+	taken := map[string]bool{TelemetryAction: true}
+	for _, name := range reserved {
+		taken[name] = true
+	}
+
+	kept := make([]ActionRule, 0, len(rules))
+	for _, rule := range rules {
+		if taken[rule.Action] {
+			fmt.Fprintf(os.Stderr, "Warning: SEMANTIC_ACTION_RULES action '%s' clashes with another tool, skipping\n", rule.Action)
+			continue
+		}
+		kept = append(kept, rule)
+	}
+	return kept
+}
+
+// SetSemanticActionRules replaces the extra semantic action rules. Rules must be set before
+// tools are generated; nil restores the built-in actions only.
+func SetSemanticActionRules(rules []ActionRule) {
+	actionRulesMutex.Lock()
+	defer actionRulesMutex.Unlock()
+	semanticActionRules = rules
+}
+
+// matchSemanticActionRule returns the custom action for a method and path, if a rule matches
+func matchSemanticActionRule(httpMethod, path string) string {
+	actionRulesMutex.RLock()
+	defer actionRulesMutex.RUnlock()
+
+	for _, rule := range semanticActionRules {
+		if rule.Method == httpMethod && strings.HasSuffix(path, rule.Suffix) {
+			return rule.Action
+		}
+	}
+	return ""
+}
+
+// customSemanticActions returns the distinct actions defined by the configured rules
+func customSemanticActions() []string {
+	actionRulesMutex.RLock()
+	defer actionRulesMutex.RUnlock()
+
+	seen := make(map[string]bool)
+	var actions []string
+	for _, rule := range semanticActionRules {
+		if !seen[rule.Action] {
+			seen[rule.Action] = true
+			actions = append(actions, rule.Action)
+		}
+	}
+	return actions
+}
+
+// isBuiltInSemanticAction checks whether an action is one of the fixed CRUD actions
+func isBuiltInSemanticAction(action string) bool {
+	switch action {
+	case ActionCreate, ActionList, ActionGet, ActionUpdate, ActionDelete:
+		return true
+	}
+	return false
+}
+
+// IsCustomSemanticAction checks whether an action was added through semantic action rules
+func IsCustomSemanticAction(action string) bool {
+	for _, custom := range customSemanticActions() {
+		if custom == action {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSemanticAction checks whether an action is a built-in or custom semantic action
+func IsSemanticAction(action string) bool {
+	return isBuiltInSemanticAction(action) || IsCustomSemanticAction(action)
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"testing"
+)
+
+func TestParseSemanticActionRules(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		entries     []string
+		expected    []ActionRule
+		expectError bool
+	}{
+		{
+			desc:     "Method defaults to POST",
+			entries:  []string{"rotate=:rotate"},
+			expected: []ActionRule{{Action: "rotate", Method: HTTPMethodPost, Suffix: ":rotate"}},
+		},
+		{
+			desc:     "Explicit method is honoured",
+			entries:  []string{"restart=put /restart"},
+			expected: []ActionRule{{Action: "restart", Method: HTTPMethodPut, Suffix: "/restart"}},
+		},
+		{
+			desc:        "Built-in actions cannot be redefined",
+			entries:     []string{"create=:create"},
+			expectError: true,
+		},
+		{
+			desc:        "Missing suffix is rejected",
+			entries:     []string{"rotate="},
+			expectError: true,
+		},
+		{
+			desc:        "Invalid action name is rejected",
+			entries:     []string{"Rotate Key=:rotate"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			rules, err := ParseSemanticActionRules(tc.entries)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %v, got rules %v", tc.entries, rules)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(rules) != len(tc.expected) {
+				t.Fatalf("Expected %d rules, got %d", len(tc.expected), len(rules))
+			}
+			for i := range rules {
+				if rules[i] != tc.expected[i] {
+					t.Errorf("Expected rule %+v, got %+v", tc.expected[i], rules[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWithoutReservedActions(t *testing.T) {
+	rules := []ActionRule{
+		{Action: "rotate", Method: HTTPMethodPost, Suffix: ":rotate"},
+		{Action: "batch", Method: HTTPMethodPost, Suffix: ":batch"},
+		{Action: TelemetryAction, Method: HTTPMethodPost, Suffix: "/query"},
+	}
+
+	kept := WithoutReservedActions(rules, []string{"batch", "search"})
+	if len(kept) != 1 || kept[0].Action != "rotate" {
+		t.Errorf("Expected only the rotate rule to be kept, got %+v", kept)
+	}
+}
+
+func TestGenerateSemanticTools_CustomActionRule(t *testing.T) {
+	spec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/iam/v2/api-keys": {
+				Get:  &openapi.Operation{Summary: "List API keys"},
+				Post: &openapi.Operation{Summary: "Create API key"},
+			},
+			"/iam/v2/api-keys/{id}:rotate": {
+				Post: &openapi.Operation{Summary: "Rotate API key"},
+			},
+		},
+	}
+
+	rules, err := ParseSemanticActionRules([]string{"rotate=:rotate"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	SetSemanticActionRules(rules)
+	defer SetSemanticActionRules(nil)
+
+	generated, err := GenerateSemanticTools(spec)
+	if err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+
+	found := false
+	for _, tool := range generated {
+		if tool.Name == "rotate" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("Expected a 'rotate' tool to be generated")
+	}
+
+	mapping, err := GetEndpointMapping("rotate", "api-keys")
+	if err != nil {
+		t.Fatalf("Expected rotate mapping for api-keys: %v", err)
+	}
+	if mapping.Method != HTTPMethodPost || mapping.PathPattern != "/iam/v2/api-keys/{id}:rotate" {
+		t.Errorf("Unexpected rotate mapping: %s %s", mapping.Method, mapping.PathPattern)
+	}
+
+	// The rotate endpoint must no longer be collapsed into create
+	if createMapping, err := GetEndpointMapping(ActionCreate, "api-keys"); err != nil || createMapping.PathPattern != "/iam/v2/api-keys" {
+		t.Errorf("Expected create to map to the collection endpoint, got %+v (err: %v)", createMapping, err)
+	}
+
+	if !IsSemanticAction("rotate") {
+		t.Error("Expected 'rotate' to be a semantic action")
+	}
+}
@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"mcolomerc/mcp-server/internal/logger"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	canonicalResourceNames      bool
+	canonicalResourceNamesMutex sync.RWMutex
+)
+
+// SetCanonicalResourceNames controls whether singular and plural variants of a resource name,
+// such as "subject" and "subjects", are collapsed into the plural form during tool generation.
+// Must be set before tools are generated.
+func SetCanonicalResourceNames(enabled bool) {
+	canonicalResourceNamesMutex.Lock()
+	defer canonicalResourceNamesMutex.Unlock()
+	canonicalResourceNames = enabled
+}
+
+// canonicalResourceNamesEnabled reports whether resource name variants are collapsed
+func canonicalResourceNamesEnabled() bool {
+	canonicalResourceNamesMutex.RLock()
+	defer canonicalResourceNamesMutex.RUnlock()
+	return canonicalResourceNames
+}
+
+// canonicalizeResourceNames renames the resources that have both a singular and a plural variant
+// across the actions to the plural form and merges their mappings. Only variants of the same
+// service and path prefix are merged, so an unrelated resource with a similar name, such as the
+// Schema Registry "config" next to Kafka topic "configs", keeps its name. Resources without a
+// variant and Schema Registry settings resources are left alone. When both variants map the same
+// action, the mapping already under the plural name wins unless it is deprecated and the other is
+// not.
+func canonicalizeResourceNames(mappings map[string]map[string]EndpointMapping) {
+	variants := make(map[string]map[string]bool) // singular form, service and path prefix -> names seen
+	for action, resourceMappings := range mappings {
+		if action == TelemetryAction {
+			continue
+		}
+		for resource, mapping := range resourceMappings {
+			if settings, _ := schemaRegistrySettingsResource(mapping.PathPattern); settings != "" {
+				continue
+			}
+			singular := SingularResourceName(resource)
+			key := strings.Join([]string{singular, mapping.Service, resourcePathPrefix(singular, mapping.PathPattern)}, " ")
+			if variants[key] == nil {
+				variants[key] = make(map[string]bool)
+			}
+			variants[key][resource] = true
+		}
+	}
+
+	canonical := make(map[string]string) // variant -> canonical name
+	for _, names := range variants {
+		if len(names) < 2 {
+			continue
+		}
+		sorted := make([]string, 0, len(names))
+		for name := range names {
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+		plural := PluralResourceName(sorted[0])
+		for _, name := range sorted {
+			if name != plural {
+				canonical[name] = plural
+			}
+		}
+	}
+
+	for action, resourceMappings := range mappings {
+		if action == TelemetryAction {
+			continue
+		}
+		for variant, plural := range canonical {
+			mapping, exists := resourceMappings[variant]
+			if !exists {
+				continue
+			}
+			delete(resourceMappings, variant)
+			if existing, exists := resourceMappings[plural]; exists && (mapping.Deprecated || !existing.Deprecated) {
+				logger.Debug("Merged resource '%s' into '%s' for %s, keeping %s %s\n", variant, plural, action, existing.Method, existing.PathPattern)
+				continue
+			}
+			resourceMappings[plural] = mapping
+			logger.Debug("Renamed resource '%s' to '%s' for %s\n", variant, plural, action)
+		}
+	}
+}
+
+// resourcePathPrefix returns the part of a path before the segment naming a resource, given in
+// singular form, or the whole path when no segment names it
+func resourcePathPrefix(singular, path string) string {
+	segments := strings.Split(path, PathSeparator)
+	for i, segment := range segments {
+		if SingularResourceName(segment) == singular {
+			return strings.Join(segments[:i], PathSeparator)
+		}
+	}
+	return path
+}
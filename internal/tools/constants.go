@@ -41,3 +41,11 @@ var CommonPluralEndings = []string{
 const (
 	ContentTypeConfluentJSON = "application/vnd.confluent+json" // Confluent-specific JSON format
 )
+
+// MaxExampleLength bounds a request body example shown in a tool's parameter description
+const MaxExampleLength = 300
+
+// Endpoint Mapping Cache - bounds the read-through cache in front of the semantic registry
+const (
+	MappingCacheSize = 256 // Maximum number of action+resource mappings kept
+)
@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Service names used to select per-service behaviour
+const (
+	ServiceCloud          = "cloud"
+	ServiceKafka          = "kafka"
+	ServiceFlink          = "flink"
+	ServiceSchemaRegistry = "schema-registry"
+	ServiceTableflow      = "tableflow"
+	ServiceTelemetry      = "telemetry"
+)
+
+// Vendor media types used by Confluent services
+const (
+	ContentTypeSchemaRegistryJSON   = "application/vnd.schemaregistry+json"
+	ContentTypeSchemaRegistryV1JSON = "application/vnd.schemaregistry.v1+json"
+)
+
+// PATCH media types: a merge patch is a partial object (RFC 7396), a JSON patch is a list
+// of operations (RFC 6902)
+const (
+	ContentTypeMergePatchJSON = "application/merge-patch+json"
+	ContentTypeJSONPatchJSON  = "application/json-patch+json"
+)
+
+// patchContentTypeOrder ranks the PATCH media types after the preferred ones: a merge patch
+// maps directly onto flat tool arguments, so it wins when an operation accepts both
+var patchContentTypeOrder = []string{ContentTypeMergePatchJSON, ContentTypeJSONPatchJSON}
+
+// Default request body content type preference order
+var defaultContentTypePreference = []string{ContentTypeJSON, ContentTypeConfluentJSON}
+
+// Default per-service content type preference orders, consulted before the global order
+var defaultServiceContentTypePreferences = map[string][]string{
+	ServiceSchemaRegistry: {ContentTypeSchemaRegistryV1JSON, ContentTypeSchemaRegistryJSON},
+}
+
+// serviceEndpointPatterns maps services to the path fragments that identify them (checked in
+// order). Leading patterns only match the first segment of a path: Schema Registry paths have no
+// service prefix, and names such as /config also appear inside Connect and Kafka paths.
+var serviceEndpointPatterns = []struct {
+	service  string
+	patterns []string
+	leading  bool
+}{
+	{ServiceKafka, []string{"/kafka/"}, false},
+	{ServiceFlink, []string{"/flink/", "/sql/v1/"}, false},
+	{ServiceSchemaRegistry, []string{"/subjects", "/schemas", "/mode", "/config", "/exporters", "/contexts", "/compatibility", "/dek-registry", "/catalog"}, true},
+	{ServiceTableflow, []string{"/tableflow/"}, false},
+	{ServiceTelemetry, []string{"/v2/metrics/", "/v2/descriptors/"}, false},
+}
+
+var (
+	contentTypePreference         = defaultContentTypePreference
+	serviceContentTypePreferences = defaultServiceContentTypePreferences
+	contentTypeMutex              sync.RWMutex
+)
+
+// SetContentTypePreferences overrides the global and per-service content type preference orders.
+// Empty values keep the built-in defaults.
+func SetContentTypePreferences(global []string, perService map[string][]string) {
+	contentTypeMutex.Lock()
+	defer contentTypeMutex.Unlock()
+
+	contentTypePreference = defaultContentTypePreference
+	if len(global) > 0 {
+		contentTypePreference = global
+	}
+
+	serviceContentTypePreferences = make(map[string][]string)
+	for service, order := range defaultServiceContentTypePreferences {
+		serviceContentTypePreferences[service] = order
+	}
+	for service, order := range perService {
+		if len(order) > 0 {
+			serviceContentTypePreferences[service] = order
+		}
+	}
+}
+
+// ServiceForPath determines which Confluent service an API path belongs to
+func ServiceForPath(path string) string {
+	pathLower := strings.ToLower(path)
+	for _, entry := range serviceEndpointPatterns {
+		for _, pattern := range entry.patterns {
+			if entry.leading && (pathLower == pattern || strings.HasPrefix(pathLower, pattern+"/")) {
+				return entry.service
+			}
+			if !entry.leading && strings.Contains(pathLower, pattern) {
+				return entry.service
+			}
+		}
+	}
+	return ServiceCloud
+}
+
+// PreferredContentTypes returns the content type preference order for a path,
+// with service-specific types ahead of the global order
+func PreferredContentTypes(path string) []string {
+	contentTypeMutex.RLock()
+	defer contentTypeMutex.RUnlock()
+
+	var order []string
+	seen := make(map[string]bool)
+	for _, contentType := range append(serviceContentTypePreferences[ServiceForPath(path)], contentTypePreference...) {
+		if !seen[contentType] {
+			seen[contentType] = true
+			order = append(order, contentType)
+		}
+	}
+	return order
+}
+
+// orderedContentTypes lists the content types of a request body, preferred types first,
+// then the PATCH types, and the remainder sorted for deterministic fallback
+func orderedContentTypes(content map[string]openapi.MediaType, path string) []string {
+	var ordered []string
+	seen := make(map[string]bool)
+	for _, contentType := range append(PreferredContentTypes(path), patchContentTypeOrder...) {
+		if seen[contentType] {
+			continue
+		}
+		if _, ok := content[contentType]; ok {
+			ordered = append(ordered, contentType)
+			seen[contentType] = true
+		}
+	}
+
+	var remaining []string
+	for contentType := range content {
+		if !seen[contentType] {
+			remaining = append(remaining, contentType)
+		}
+	}
+	sort.Strings(remaining)
+
+	return append(ordered, remaining...)
+}
+
+// PreferredResponseContentType picks the media type to request for a response from the types an
+// operation declares, in the same preference order as request bodies. Media type parameters such
+// as the Schema Registry's "qs" weights are dropped. It returns "" when nothing is declared.
+func PreferredResponseContentType(declared []string, path string) string {
+	content := make(map[string]openapi.MediaType, len(declared))
+	for _, contentType := range declared {
+		mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+		if mediaType != "" {
+			content[mediaType] = openapi.MediaType{}
+		}
+	}
+	if len(content) == 0 {
+		return ""
+	}
+	return orderedContentTypes(content, path)[0]
+}
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"testing"
+)
+
+func TestExtractRequestBodySchema_ContentTypePreference(t *testing.T) {
+	defer SetContentTypePreferences(nil, nil)
+
+	schemaFor := func(property string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{property: map[string]interface{}{"type": "string"}},
+		}
+	}
+	requestBody := &openapi.RequestBody{
+		Content: map[string]openapi.MediaType{
+			ContentTypeJSON:                 {Schema: schemaFor("json_field")},
+			ContentTypeSchemaRegistryV1JSON: {Schema: schemaFor("vendor_field")},
+		},
+	}
+	spec := &openapi.OpenAPISpec{}
+
+	testCases := []struct {
+		desc       string
+		path       string
+		global     []string
+		perService map[string][]string
+		expected   string
+	}{
+		{
+			desc:     "Schema Registry path prefers vendor media type by default",
+			path:     "/subjects/{subject}/versions",
+			expected: ContentTypeSchemaRegistryV1JSON,
+		},
+		{
+			desc:     "Non Schema Registry path prefers application/json by default",
+			path:     "/kafka/v3/clusters/{cluster_id}/topics",
+			expected: ContentTypeJSON,
+		},
+		{
+			desc:     "Global preference lets vendor media type win everywhere",
+			path:     "/kafka/v3/clusters/{cluster_id}/topics",
+			global:   []string{ContentTypeSchemaRegistryV1JSON, ContentTypeJSON},
+			expected: ContentTypeSchemaRegistryV1JSON,
+		},
+		{
+			desc:       "Per-service preference overrides the Schema Registry default",
+			path:       "/subjects/{subject}/versions",
+			perService: map[string][]string{ServiceSchemaRegistry: {ContentTypeJSON}},
+			expected:   ContentTypeJSON,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			SetContentTypePreferences(tc.global, tc.perService)
+
+			info := extractRequestBodySchema(requestBody, spec, tc.path)
+			if info == nil {
+				t.Fatal("Expected request body info, got nil")
+			}
+			if info.ContentType != tc.expected {
+				t.Errorf("Expected content type '%s', got '%s'", tc.expected, info.ContentType)
+			}
+		})
+	}
+}
+
+func TestServiceForPath(t *testing.T) {
+	testCases := map[string]string{
+		"/kafka/v3/clusters/{cluster_id}/topics/{topic_name}/configs": ServiceKafka,
+		"/sql/v1/organizations/{org_id}/statements":                   ServiceFlink,
+		"/subjects/{subject}/versions":                                ServiceSchemaRegistry,
+		"/config/{subject}":                                           ServiceSchemaRegistry,
+		"/config":                                                     ServiceSchemaRegistry,
+		"/mode":                                                       ServiceSchemaRegistry,
+		"/compatibility/subjects/{subject}/versions":                  ServiceSchemaRegistry,
+		"/catalog/v1/types/tagdefs":                                   ServiceSchemaRegistry,
+		"/org/v2/environments":                                        ServiceCloud,
+		"/connect/v1/environments/{environment_id}/clusters/{kafka_cluster_id}/connectors/{connector_name}/config": ServiceCloud,
+		"/kafka/v3/clusters/{cluster_id}/broker-configs":                                                           ServiceKafka,
+		"/kafka/v3/clusters/{cluster_id}/topics/-/configs":                                                         ServiceKafka,
+	}
+
+	for path, expected := range testCases {
+		if service := ServiceForPath(path); service != expected {
+			t.Errorf("Expected service '%s' for path '%s', got '%s'", expected, path, service)
+		}
+	}
+}
+
+func TestOrderedContentTypes_PatchTypes(t *testing.T) {
+	content := map[string]openapi.MediaType{
+		ContentTypeJSONPatchJSON:  {},
+		ContentTypeMergePatchJSON: {},
+		"text/plain":              {},
+	}
+
+	ordered := orderedContentTypes(content, "/org/v2/environments/{id}")
+	expected := []string{ContentTypeMergePatchJSON, ContentTypeJSONPatchJSON, "text/plain"}
+	if len(ordered) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, ordered)
+	}
+	for i := range expected {
+		if ordered[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, ordered)
+			break
+		}
+	}
+}
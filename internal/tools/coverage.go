@@ -0,0 +1,52 @@
+package tools
+
+// Reasons a spec path produced no semantic mapping
+const (
+	SkipReasonNoResource = "no_resource" // no resource name could be extracted from the path
+	SkipReasonDeprecated = "deprecated"  // every operation is deprecated and HIDE_DEPRECATED is set
+	SkipReasonTags       = "tags"        // every operation is filtered out by INCLUDE_TAGS or EXCLUDE_TAGS
+	SkipReasonNoAction   = "no_action"   // no operation maps to a semantic action
+	SkipReasonNoMethods  = "no_methods"  // the path declares no operations
+	SkipReasonSuperseded = "superseded"  // a current operation on another path kept the action and resource
+)
+
+// RegistryCoverage describes how much of the spec the semantic registry covers
+type RegistryCoverage struct {
+	SpecPaths    int
+	MappedPaths  int
+	SkippedPaths map[string]string // spec path -> skip reason
+}
+
+// GetRegistryCoverage returns the coverage of the spec the registry was last built from
+func GetRegistryCoverage() RegistryCoverage {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	coverage := RegistryCoverage{SkippedPaths: make(map[string]string)}
+	if GlobalSemanticRegistry == nil || GlobalSemanticRegistry.Spec == nil {
+		return coverage
+	}
+
+	coverage.SpecPaths = len(GlobalSemanticRegistry.Spec.Paths)
+	for path, reason := range GlobalSemanticRegistry.Skipped {
+		coverage.SkippedPaths[path] = reason
+	}
+	coverage.MappedPaths = coverage.SpecPaths - len(coverage.SkippedPaths)
+	return coverage
+}
+
+// skipReason explains why a path with a resource produced no mapping from its operation counts
+func skipReason(operations, deprecated, filtered, withAction int) string {
+	switch {
+	case operations == 0:
+		return SkipReasonNoMethods
+	case deprecated == operations:
+		return SkipReasonDeprecated
+	case deprecated+filtered == operations:
+		return SkipReasonTags
+	case withAction == 0:
+		return SkipReasonNoAction
+	default:
+		return SkipReasonSuperseded
+	}
+}
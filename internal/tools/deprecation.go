@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"sort"
+	"sync"
+)
+
+var (
+	hideDeprecated      bool
+	hideDeprecatedMutex sync.RWMutex
+)
+
+// SetHideDeprecated controls whether operations marked `deprecated: true` in the spec are skipped
+// during tool generation. When they are kept, their tools are annotated as deprecated instead.
+// Must be set before tools are generated.
+func SetHideDeprecated(hide bool) {
+	hideDeprecatedMutex.Lock()
+	defer hideDeprecatedMutex.Unlock()
+	hideDeprecated = hide
+}
+
+// hideDeprecatedOperations reports whether deprecated operations are left out of generated tools
+func hideDeprecatedOperations() bool {
+	hideDeprecatedMutex.RLock()
+	defer hideDeprecatedMutex.RUnlock()
+	return hideDeprecated
+}
+
+// deprecatedResources returns the sorted resources whose mapping comes from a deprecated operation
+func deprecatedResources(resourceMappings map[string]EndpointMapping) []string {
+	var resources []string
+	for resource, mapping := range resourceMappings {
+		if mapping.Deprecated {
+			resources = append(resources, resource)
+		}
+	}
+	sort.Strings(resources)
+	return resources
+}
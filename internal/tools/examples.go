@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// requestBodyExamples returns, per resource, the trimmed request body example of its mapping
+// as compact JSON. Resources without an example are left out.
+func requestBodyExamples(resourceMappings map[string]EndpointMapping) map[string]string {
+	examples := make(map[string]string)
+	for resource, mapping := range resourceMappings {
+		example, ok := mapping.RequestBodySchema["example"]
+		if !ok || example == nil {
+			continue
+		}
+		exampleJSON, err := json.Marshal(example)
+		if err != nil {
+			continue
+		}
+		examples[resource] = trimExample(string(exampleJSON))
+	}
+	return examples
+}
+
+// trimExample shortens an example to MaxExampleLength characters so it stays a hint
+func trimExample(example string) string {
+	if len(example) <= MaxExampleLength {
+		return example
+	}
+	end := MaxExampleLength
+	for end > 0 && !utf8.RuneStart(example[end]) {
+		end--
+	}
+	return example[:end] + "..."
+}
+
+// describeBodyExamples appends the request body examples to a parameter description
+func describeBodyExamples(description string, examples map[string]string) string {
+	if len(examples) == 0 {
+		return description
+	}
+	resources := make([]string, 0, len(examples))
+	for resource := range examples {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	hints := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		hints = append(hints, fmt.Sprintf("%s: %s", resource, examples[resource]))
+	}
+	return description + ". Example request bodies - " + strings.Join(hints, "; ")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"mcolomerc/mcp-server/internal/openapi"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -13,6 +14,54 @@ func GenerateTools(spec openapi.OpenAPISpec) ([]Tool, error) {
 	return GenerateSemanticTools(spec)
 }
 
+// NormalizeToolNames converts tool names to MCP-compliant names the way generated tool names are
+// normalized. Names that collide after normalization, e.g. "List Topics" and "list-topics", are
+// kept apart with a -2, -3, ... suffix in the given order; the renamed ones are listed as clashes.
+func NormalizeToolNames(names []string) (normalized []string, clashes []string) {
+	normalized = make([]string, len(names))
+	taken := make(map[string]bool, len(names))
+	for i, name := range names {
+		normalized[i] = normalizeToolName(name)
+		taken[normalized[i]] = true
+	}
+
+	seen := make(map[string]bool, len(names))
+	for i, name := range normalized {
+		if !seen[name] {
+			seen[name] = true
+			continue
+		}
+		for n := 2; ; n++ {
+			candidate := fmt.Sprintf("%s-%d", name, n)
+			if !taken[candidate] {
+				normalized[i] = candidate
+				taken[candidate] = true
+				seen[candidate] = true
+				break
+			}
+		}
+		clashes = append(clashes, fmt.Sprintf("%s (as %s)", names[i], normalized[i]))
+	}
+	return normalized, clashes
+}
+
+// findDuplicateToolNames returns the sorted tool names used by more than one tool
+func findDuplicateToolNames(tools []Tool) []string {
+	counts := make(map[string]int, len(tools))
+	for _, tool := range tools {
+		counts[tool.Name]++
+	}
+
+	var duplicates []string
+	for name, count := range counts {
+		if count > 1 {
+			duplicates = append(duplicates, name)
+		}
+	}
+	sort.Strings(duplicates)
+	return duplicates
+}
+
 // extractHTTPOperations extracts all HTTP operations from a path item
 func extractHTTPOperations(pathItem *openapi.PathItem) []HTTPOperation {
 	var operations []HTTPOperation
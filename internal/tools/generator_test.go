@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeToolNames(t *testing.T) {
+	names, clashes := NormalizeToolNames([]string{"List Topics", "list-topics", "list_topics", "List Topics!", "list-topics-2"})
+
+	expected := []string{"list-topics", "list-topics-3", "list_topics", "list-topics-4", "list-topics-2"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected names %v, got %v", expected, names)
+	}
+	expectedClashes := []string{"list-topics (as list-topics-3)", "List Topics! (as list-topics-4)"}
+	if !reflect.DeepEqual(clashes, expectedClashes) {
+		t.Errorf("Expected clashes %v, got %v", expectedClashes, clashes)
+	}
+}
+
+func TestFindDuplicateToolNames(t *testing.T) {
+	tools := []Tool{{Name: "list"}, {Name: "get_telemetry"}, {Name: "list"}, {Name: "get"}}
+
+	duplicates := findDuplicateToolNames(tools)
+	if len(duplicates) != 1 || duplicates[0] != "list" {
+		t.Errorf("Expected [list], got %v", duplicates)
+	}
+	if duplicates := findDuplicateToolNames(tools[1:]); len(duplicates) != 0 {
+		t.Errorf("Expected no duplicates, got %v", duplicates)
+	}
+}
+
+func TestGenerateTools_Deprecated(t *testing.T) {
+	spec := openapi.OpenAPISpec{
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Get: &openapi.Operation{OperationID: "listKafkaTopics"},
+			},
+			"/iam/v2/service-accounts": {
+				Get: &openapi.Operation{OperationID: "listServiceAccounts", Deprecated: true},
+			},
+		},
+	}
+
+	findTool := func(tools []Tool, name string) *Tool {
+		for i := range tools {
+			if tools[i].Name == name {
+				return &tools[i]
+			}
+		}
+		return nil
+	}
+
+	defer SetHideDeprecated(false)
+
+	t.Run("Deprecated operations are annotated by default", func(t *testing.T) {
+		SetHideDeprecated(false)
+
+		semanticTools, err := GenerateSemanticTools(spec)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		list := findTool(semanticTools, ActionList)
+		if list == nil {
+			t.Fatal("Expected a list tool")
+		}
+		if !strings.Contains(list.Description, "Deprecated (may be removed): service-accounts") {
+			t.Errorf("Expected service-accounts to be flagged as deprecated, got %q", list.Description)
+		}
+		if _, err := GetEndpointMapping(ActionList, "service-accounts"); err != nil {
+			t.Errorf("Expected the deprecated mapping to be kept: %v", err)
+		}
+	})
+
+	t.Run("HIDE_DEPRECATED skips deprecated operations", func(t *testing.T) {
+		SetHideDeprecated(true)
+
+		semanticTools, err := GenerateSemanticTools(spec)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		list := findTool(semanticTools, ActionList)
+		if list == nil || strings.Contains(list.Description, "service-accounts") {
+			t.Errorf("Expected service-accounts to be hidden, got %+v", list)
+		}
+		if _, err := GetEndpointMapping(ActionList, "service-accounts"); err == nil {
+			t.Error("Expected no mapping for the deprecated operation")
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"container/list"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// mappingCache is a read-through LRU cache of resolved endpoint mappings keyed by action+resource.
+// Hits are served without taking registryMutex, so hot tools no longer queue behind a registry
+// rebuild, which holds the write lock for the whole spec parse. Entries are dropped whenever the
+// registry is rebuilt; the generation counter stops lookups that raced with a rebuild from
+// storing stale mappings afterwards.
+type mappingCache struct {
+	mu         sync.Mutex
+	capacity   int
+	entries    map[mappingCacheKey]*list.Element
+	order      *list.List // Front is most recently used
+	generation uint64
+}
+
+// mappingCacheKey identifies a cached mapping
+type mappingCacheKey struct {
+	action   string
+	resource string
+}
+
+type mappingCacheEntry struct {
+	key     mappingCacheKey
+	mapping EndpointMapping
+}
+
+// endpointMappingCache caches lookups made by GetEndpointMapping and GetTelemetryEndpointMapping
+var endpointMappingCache = newMappingCache(MappingCacheSize)
+
+func newMappingCache(capacity int) *mappingCache {
+	return &mappingCache{
+		capacity: capacity,
+		entries:  make(map[mappingCacheKey]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// get returns a copy of the cached mapping and the current generation. The copy shares no maps
+// or slices with the cache, so callers may change it freely.
+func (c *mappingCache) get(key mappingCacheKey) (*EndpointMapping, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		mapping := cloneEndpointMapping(element.Value.(*mappingCacheEntry).mapping)
+		return &mapping, c.generation, true
+	}
+	return nil, c.generation, false
+}
+
+// put stores a copy of a mapping unless the cache was invalidated since generation was read
+func (c *mappingCache) put(key mappingCacheKey, mapping EndpointMapping, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	mapping = cloneEndpointMapping(mapping)
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*mappingCacheEntry).mapping = mapping
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&mappingCacheEntry{key: key, mapping: mapping})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*mappingCacheEntry).key)
+	}
+}
+
+// invalidate drops all cached mappings; called whenever the registry is rebuilt
+func (c *mappingCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[mappingCacheKey]*list.Element)
+	c.order.Init()
+	c.generation++
+}
+
+// len returns the number of cached mappings
+func (c *mappingCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// cloneEndpointMapping copies a mapping together with its maps and slices. Parsed spec schemas
+// referenced from the request body schema are shared, as nothing changes them after parsing.
+func cloneEndpointMapping(mapping EndpointMapping) EndpointMapping {
+	mapping.RequiredParams = slices.Clone(mapping.RequiredParams)
+	mapping.OptionalParams = slices.Clone(mapping.OptionalParams)
+	mapping.RequiredQuery = slices.Clone(mapping.RequiredQuery)
+	mapping.ResponseItemFields = slices.Clone(mapping.ResponseItemFields)
+	mapping.RateLimit = maps.Clone(mapping.RateLimit)
+	if mapping.RequestBodySchema != nil {
+		mapping.RequestBodySchema = cloneSchemaValue(mapping.RequestBodySchema).(map[string]interface{})
+	}
+	if mapping.QueryVariants != nil {
+		variants := make([]EndpointMapping, len(mapping.QueryVariants))
+		for i, variant := range mapping.QueryVariants {
+			variants[i] = cloneEndpointMapping(variant)
+		}
+		mapping.QueryVariants = variants
+	}
+	return mapping
+}
+
+// cloneSchemaValue copies the maps and slices of a decoded JSON schema value
+func cloneSchemaValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			clone[key] = cloneSchemaValue(item)
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(typed))
+		for i, item := range typed {
+			clone[i] = cloneSchemaValue(item)
+		}
+		return clone
+	case []string:
+		return slices.Clone(typed)
+	default:
+		return value
+	}
+}
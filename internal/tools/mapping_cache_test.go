@@ -0,0 +1,264 @@
+package tools
+
+import (
+	"fmt"
+	"mcolomerc/mcp-server/internal/openapi"
+	"sync"
+	"testing"
+)
+
+// newMappingCacheTestSpec builds a spec with a list endpoint for each of the given resources
+func newMappingCacheTestSpec(resources ...string) openapi.OpenAPISpec {
+	spec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths:   map[string]openapi.PathItem{},
+	}
+	for _, resource := range resources {
+		spec.Paths["/v1/"+resource] = openapi.PathItem{
+			Get: &openapi.Operation{Summary: "List " + resource},
+		}
+	}
+	return spec
+}
+
+func TestMappingCacheEviction(t *testing.T) {
+	cache := newMappingCache(2)
+	generation := cache.generation
+
+	cache.put(mappingCacheKey{resource: "a"}, EndpointMapping{PathPattern: "/a"}, generation)
+	cache.put(mappingCacheKey{resource: "b"}, EndpointMapping{PathPattern: "/b"}, generation)
+	cache.get(mappingCacheKey{resource: "a"}) // a is now most recently used
+	cache.put(mappingCacheKey{resource: "c"}, EndpointMapping{PathPattern: "/c"}, generation)
+
+	if _, _, ok := cache.get(mappingCacheKey{resource: "b"}); ok {
+		t.Error("Expected least recently used entry 'b' to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, _, ok := cache.get(mappingCacheKey{resource: key}); !ok {
+			t.Errorf("Expected entry '%s' to be cached", key)
+		}
+	}
+
+	cache.invalidate()
+	if cache.len() != 0 {
+		t.Errorf("Expected empty cache after invalidation, got %d entries", cache.len())
+	}
+
+	// A lookup that started before invalidation must not repopulate the cache
+	cache.put(mappingCacheKey{resource: "a"}, EndpointMapping{PathPattern: "/stale"}, generation)
+	if _, _, ok := cache.get(mappingCacheKey{resource: "a"}); ok {
+		t.Error("Expected stale put to be ignored after invalidation")
+	}
+}
+
+func TestGetEndpointMappingInvalidatedOnReload(t *testing.T) {
+	if _, err := GenerateSemanticTools(newMappingCacheTestSpec("topics")); err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+
+	mapping, err := GetEndpointMapping(ActionList, "topics")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mapping.PathPattern != "/v1/topics" {
+		t.Errorf("Unexpected path pattern: %s", mapping.PathPattern)
+	}
+
+	// Callers get their own copy, so mutating it must not affect the cache
+	mapping.PathPattern = "/mutated"
+	if cached, _ := GetEndpointMapping(ActionList, "topics"); cached.PathPattern == "/mutated" {
+		t.Error("Expected cached mapping to be isolated from caller mutations")
+	}
+
+	// Reloading with a spec that no longer has topics must drop the cached mapping
+	if _, err := GenerateSemanticTools(newMappingCacheTestSpec("connectors")); err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+	if _, err := GetEndpointMapping(ActionList, "topics"); err == nil {
+		t.Error("Expected stale 'topics' mapping to be invalidated on reload")
+	}
+	if _, err := GetEndpointMapping(ActionList, "connectors"); err != nil {
+		t.Errorf("Expected 'connectors' mapping after reload, got error: %v", err)
+	}
+}
+
+func TestResolveResourceSchemaServedFromCache(t *testing.T) {
+	spec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/v1/widgets": {Post: &openapi.Operation{RequestBody: &openapi.RequestBody{Content: map[string]openapi.MediaType{
+				ContentTypeJSON: {Schema: &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{"size": {Type: "integer"}}}},
+			}}}},
+		},
+	}
+	if _, err := GenerateSemanticTools(spec); err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+
+	first, err := ResolveResourceSchema(ActionCreate, "widgets")
+	if err != nil || first == nil {
+		t.Fatalf("Expected the widgets schema, got %v (error %v)", first, err)
+	}
+
+	// A registry change without a rebuild is not seen: the resolved schema comes from the cache
+	registryMutex.Lock()
+	mapping := GlobalSemanticRegistry.Mappings[ActionCreate]["widgets"]
+	mapping.RequestBodySchema = nil
+	GlobalSemanticRegistry.Mappings[ActionCreate]["widgets"] = mapping
+	registryMutex.Unlock()
+	if cached, _ := ResolveResourceSchema(ActionCreate, "widgets"); cached == nil {
+		t.Error("Expected the cached schema to be served")
+	}
+
+	// A rebuild drops it
+	if _, err := GenerateSemanticTools(newMappingCacheTestSpec("widgets")); err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+	if _, err := ResolveResourceSchema(ActionCreate, "widgets"); err == nil {
+		t.Error("Expected the cached schema to be invalidated on reload")
+	}
+}
+
+func TestGetEndpointMappingReturnsIndependentCopies(t *testing.T) {
+	spec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/v1/widgets/{widget_id}": {Put: &openapi.Operation{
+				Parameters: []openapi.Parameter{{Name: "widget_id", In: "path", Required: true}},
+				RequestBody: &openapi.RequestBody{Content: map[string]openapi.MediaType{
+					ContentTypeJSON: {Schema: &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{"size": {Type: "integer"}}}},
+				}},
+			}},
+		},
+	}
+	if _, err := GenerateSemanticTools(spec); err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+
+	// The first lookup fills the cache, the second is served from it; neither may leak changes
+	for i := 0; i < 2; i++ {
+		mapping, err := GetEndpointMapping(ActionUpdate, "widgets")
+		if err != nil {
+			t.Fatalf("Lookup %d failed: %v", i+1, err)
+		}
+		if len(mapping.RequiredParams) == 0 || mapping.RequestBodySchema == nil {
+			t.Fatalf("Lookup %d returned an incomplete mapping: %+v", i+1, mapping)
+		}
+		mapping.RequiredParams[0] = "mutated"
+		mapping.RequestBodySchema["contentType"] = "mutated"
+		mapping.RequestBodySchema["extra"] = true
+	}
+
+	mapping, err := GetEndpointMapping(ActionUpdate, "widgets")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if mapping.RequiredParams[0] != "widget_id" {
+		t.Errorf("Expected required params to be unchanged, got %v", mapping.RequiredParams)
+	}
+	if mapping.RequestBodySchema["contentType"] != ContentTypeJSON || mapping.RequestBodySchema["extra"] != nil {
+		t.Errorf("Expected the request body schema to be unchanged, got %v", mapping.RequestBodySchema)
+	}
+}
+
+func TestGetEndpointMappingConcurrentWithReload(t *testing.T) {
+	resources := []string{"topics", "connectors", "subjects"}
+	if _, err := GenerateSemanticTools(newMappingCacheTestSpec(resources...)); err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				resource := resources[(worker+j)%len(resources)]
+				mapping, err := GetEndpointMapping(ActionList, resource)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if expected := "/v1/" + resource; mapping.PathPattern != expected {
+					errs <- fmt.Errorf("expected %s, got %s", expected, mapping.PathPattern)
+					return
+				}
+			}
+		}(i)
+	}
+
+	// Rebuild the registry with the same resources while lookups are running
+	for i := 0; i < 5; i++ {
+		if _, err := GenerateSemanticTools(newMappingCacheTestSpec(resources...)); err != nil {
+			t.Fatalf("Failed to generate semantic tools: %v", err)
+		}
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// BenchmarkGetEndpointMappingParallel compares cached lookups with direct registry reads.
+// The RebuildContention variants keep a registry rebuild running alongside the lookups,
+// which is where cache hits avoid waiting on registryMutex.
+func BenchmarkGetEndpointMappingParallel(b *testing.B) {
+	var resources []string
+	for c := 'a'; c <= 'z'; c++ {
+		resources = append(resources, fmt.Sprintf("%cwidgets", c))
+	}
+	spec := newMappingCacheTestSpec(resources...)
+	if _, err := GenerateSemanticTools(spec); err != nil {
+		b.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+
+	lookups := map[string]func(action, resource string) (*EndpointMapping, error){
+		"Cached":   GetEndpointMapping,
+		"Uncached": lookupEndpointMapping,
+	}
+
+	for _, rebuild := range []bool{false, true} {
+		for _, name := range []string{"Cached", "Uncached"} {
+			lookup := lookups[name]
+			benchName := name
+			if rebuild {
+				benchName += "/RebuildContention"
+			}
+
+			b.Run(benchName, func(b *testing.B) {
+				stop := make(chan struct{})
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					for rebuild {
+						select {
+						case <-stop:
+							return
+						default:
+							initializeSemanticRegistry(spec)
+						}
+					}
+				}()
+
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					i := 0
+					for pb.Next() {
+						if _, err := lookup(ActionList, resources[i%len(resources)]); err != nil {
+							b.Error(err)
+							return
+						}
+						i++
+					}
+				})
+				b.StopTimer()
+
+				close(stop)
+				<-done
+			})
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"mcolomerc/mcp-server/internal/logger"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	maxTools      int
+	toolPriority  map[string]int
+	maxToolsMutex sync.RWMutex
+)
+
+// readOnlyActions are kept ahead of the other unprioritized tools when MAX_TOOLS is hit
+var readOnlyActions = map[string]bool{ActionList: true, ActionGet: true, TelemetryAction: true}
+
+// SetMaxTools caps the number of tools the server exposes, for clients that truncate long tool
+// lists. When the cap is hit, the tools named in priority are kept first, in the order given, then
+// the read-only actions, then the rest by name. Must be set before the server is built; a max of
+// 0 or less disables the cap.
+func SetMaxTools(max int, priority []string) {
+	ranks := make(map[string]int, len(priority))
+	for _, name := range priority {
+		if name = strings.TrimSpace(name); name != "" {
+			if _, exists := ranks[name]; !exists {
+				ranks[name] = len(ranks)
+			}
+		}
+	}
+
+	maxToolsMutex.Lock()
+	defer maxToolsMutex.Unlock()
+	maxTools = max
+	toolPriority = ranks
+}
+
+// LimitToolNames returns the lowest priority names beyond the configured maximum, sorted, and
+// logs them. It returns nil when the cap is disabled or not reached.
+func LimitToolNames(names []string) []string {
+	maxToolsMutex.RLock()
+	defer maxToolsMutex.RUnlock()
+
+	if maxTools <= 0 || len(names) <= maxTools {
+		return nil
+	}
+
+	ranked := append([]string(nil), names...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if rankA, rankB := toolRank(ranked[i]), toolRank(ranked[j]); rankA != rankB {
+			return rankA < rankB
+		}
+		return ranked[i] < ranked[j]
+	})
+
+	dropped := ranked[maxTools:]
+	sort.Strings(dropped)
+	logger.Info("MAX_TOOLS=%d reached, dropped %d tools: %s\n", maxTools, len(dropped), strings.Join(dropped, ", "))
+	return dropped
+}
+
+// toolRank orders tools for LimitToolNames: prioritized tools first, then read-only actions, then the
+// rest. Callers hold maxToolsMutex.
+func toolRank(name string) int {
+	if rank, ok := toolPriority[name]; ok {
+		return rank
+	}
+	if readOnlyActions[name] {
+		return len(toolPriority)
+	}
+	return len(toolPriority) + 1
+}
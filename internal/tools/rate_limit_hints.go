@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	rateLimitHints      bool
+	rateLimitHintsMutex sync.RWMutex
+)
+
+// SetRateLimitHints controls whether the x-ratelimit-* extensions of spec operations are added to
+// generated tool descriptions. Must be set before tools are generated.
+func SetRateLimitHints(enabled bool) {
+	rateLimitHintsMutex.Lock()
+	defer rateLimitHintsMutex.Unlock()
+	rateLimitHints = enabled
+}
+
+// rateLimitHintsEnabled reports whether tool descriptions include rate-limit hints
+func rateLimitHintsEnabled() bool {
+	rateLimitHintsMutex.RLock()
+	defer rateLimitHintsMutex.RUnlock()
+	return rateLimitHints
+}
+
+// formatRateLimit formats the rate-limit hints of an operation as sorted name=value pairs,
+// e.g. "limit=100, window=60s"
+func formatRateLimit(hints map[string]string) string {
+	pairs := make([]string, 0, len(hints))
+	for name, value := range hints {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// resourceRateLimits lists, sorted by resource, the rate limits declared for the operations of a
+// semantic tool, e.g. "topics (limit=100)"
+func resourceRateLimits(resourceMappings map[string]EndpointMapping) string {
+	var limits []string
+	for resource, mapping := range resourceMappings {
+		if limit := formatRateLimit(mapping.RateLimit); limit != "" {
+			limits = append(limits, fmt.Sprintf("%s (%s)", resource, limit))
+		}
+	}
+	sort.Strings(limits)
+	return strings.Join(limits, "; ")
+}
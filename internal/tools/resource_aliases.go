@@ -0,0 +1,86 @@
+package tools
+
+import "strings"
+
+// irregularResourcePlurals lists resource names whose plural is not formed by the regular rules
+// below. Keys are singular, values plural.
+var irregularResourcePlurals = map[string]string{
+	"status":   "statuses",
+	"alias":    "aliases",
+	"address":  "addresses",
+	"metadata": "metadata",
+}
+
+// PluralResourceName returns the plural form of a resource name, e.g. "topic" -> "topics",
+// "policy" -> "policies". Names that are already plural are returned unchanged.
+func PluralResourceName(name string) string {
+	lower := strings.ToLower(name)
+	prefix, last := splitLastWord(lower)
+
+	for singular, plural := range irregularResourcePlurals {
+		if last == singular || last == plural {
+			return prefix + plural
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(last, "ies"), strings.HasSuffix(last, "s") && !strings.HasSuffix(last, "ss"):
+		return lower
+	case strings.HasSuffix(last, "y") && len(last) > 1 && !strings.ContainsRune("aeiou", rune(last[len(last)-2])):
+		return prefix + strings.TrimSuffix(last, "y") + "ies"
+	case strings.HasSuffix(last, "ss"), strings.HasSuffix(last, "x"), strings.HasSuffix(last, "ch"), strings.HasSuffix(last, "sh"):
+		return prefix + last + "es"
+	}
+	return prefix + last + "s"
+}
+
+// SingularResourceName returns the singular form of a resource name, e.g. "topics" -> "topic",
+// "service-accounts" -> "service-account". Names that are already singular are returned unchanged.
+func SingularResourceName(name string) string {
+	lower := strings.ToLower(name)
+	prefix, last := splitLastWord(lower)
+
+	for singular, plural := range irregularResourcePlurals {
+		if last == singular || last == plural {
+			return prefix + singular
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(last, "ies") && len(last) > 3:
+		return prefix + strings.TrimSuffix(last, "ies") + "y"
+	case strings.HasSuffix(last, "sses"), strings.HasSuffix(last, "xes"), strings.HasSuffix(last, "ches"), strings.HasSuffix(last, "shes"):
+		return prefix + strings.TrimSuffix(last, "es")
+	case strings.HasSuffix(last, "s") && !strings.HasSuffix(last, "ss"):
+		return prefix + strings.TrimSuffix(last, "s")
+	}
+	return lower
+}
+
+// ResolveResourceName finds the entry of known that name refers to, accepting singular and
+// plural forms in any case, e.g. "Topic" resolves to "topics"
+func ResolveResourceName(name string, known []string) (string, bool) {
+	for _, candidate := range known {
+		if candidate == name {
+			return candidate, true
+		}
+	}
+
+	singular := SingularResourceName(name)
+	for _, candidate := range known {
+		if SingularResourceName(candidate) == singular {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// splitLastWord splits a hyphenated or underscored name before its last word, so only the
+// last word is inflected: "service-accounts" -> ("service-", "accounts")
+func splitLastWord(name string) (string, string) {
+	index := strings.LastIndexAny(name, "-_")
+	if index == -1 {
+		return "", name
+	}
+	return name[:index+1], name[index+1:]
+}
@@ -0,0 +1,67 @@
+package tools
+
+import "testing"
+
+func TestResourceNameInflection(t *testing.T) {
+	testCases := []struct {
+		singular string
+		plural   string
+	}{
+		{"topic", "topics"},
+		{"schema", "schemas"},
+		{"subject", "subjects"},
+		{"connector", "connectors"},
+		{"service-account", "service-accounts"},
+		{"role-binding", "role-bindings"},
+		{"policy", "policies"},
+		{"identity-provider", "identity-providers"},
+		{"status", "statuses"},
+		{"key", "keys"},
+		{"access", "accesses"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.singular, func(t *testing.T) {
+			if got := PluralResourceName(tc.singular); got != tc.plural {
+				t.Errorf("PluralResourceName(%q): expected %q, got %q", tc.singular, tc.plural, got)
+			}
+			if got := PluralResourceName(tc.plural); got != tc.plural {
+				t.Errorf("PluralResourceName(%q): expected %q unchanged, got %q", tc.plural, tc.plural, got)
+			}
+			if got := SingularResourceName(tc.plural); got != tc.singular {
+				t.Errorf("SingularResourceName(%q): expected %q, got %q", tc.plural, tc.singular, got)
+			}
+			if got := SingularResourceName(tc.singular); got != tc.singular {
+				t.Errorf("SingularResourceName(%q): expected %q unchanged, got %q", tc.singular, tc.singular, got)
+			}
+		})
+	}
+}
+
+func TestResolveResourceName(t *testing.T) {
+	known := []string{"topics", "schemas", "service-accounts", "policies", "mode"}
+
+	testCases := []struct {
+		input    string
+		expected string
+		found    bool
+	}{
+		{"topics", "topics", true},
+		{"topic", "topics", true},
+		{"Topic", "topics", true},
+		{"schema", "schemas", true},
+		{"service-account", "service-accounts", true},
+		{"policy", "policies", true},
+		{"modes", "mode", true},
+		{"clusters", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			resolved, found := ResolveResourceName(tc.input, known)
+			if resolved != tc.expected || found != tc.found {
+				t.Errorf("Expected (%q, %v), got (%q, %v)", tc.expected, tc.found, resolved, found)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package tools
+
+import "sync"
+
+var (
+	resourceIDParams      map[string]string
+	resourceIDParamsMutex sync.RWMutex
+)
+
+// SetResourceIDParams sets the argument that identifies one instance of a resource type, for
+// resources whose path parameter the naming heuristics do not find. Keys may be singular or plural.
+func SetResourceIDParams(params map[string]string) {
+	normalized := make(map[string]string, len(params))
+	for resource, param := range params {
+		normalized[PluralResourceName(resource)] = param
+	}
+
+	resourceIDParamsMutex.Lock()
+	defer resourceIDParamsMutex.Unlock()
+	resourceIDParams = normalized
+}
+
+// ResourceIDParam returns the configured identifier argument of a resource type, or an empty
+// string when none is configured
+func ResourceIDParam(resource string) string {
+	resourceIDParamsMutex.RLock()
+	defer resourceIDParamsMutex.RUnlock()
+	return resourceIDParams[PluralResourceName(resource)]
+}
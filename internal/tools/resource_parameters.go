@@ -0,0 +1,247 @@
+package tools
+
+import (
+	"maps"
+	"mcolomerc/mcp-server/internal/openapi"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// resourceParameter collects what the mappings of an action say about one parameter name
+type resourceParameter struct {
+	schemas   []map[string]interface{} // type, enum and description per distinct type, in declaration order
+	resources []string                 // resources using it, in sorted order
+	required  map[string]bool          // resources where a call must supply it
+	types     map[string]string        // type of the parameter per resource
+}
+
+// resourceParameterProperties builds the properties of the parameters object of a semantic tool
+// from the mappings of its resources: operation parameters and top-level request body fields,
+// with their types and enums. A parameter shared by several resources is listed once, and its
+// description names the resources that use it. A parameter is marked required for a resource
+// when any of its declarations there requires it, and one declared with different types is
+// described as a oneOf of those types, with the type of each resource in the description. An
+// enum is kept only when every resource declaring that type allows the same values.
+func resourceParameterProperties(resourceMappings map[string]EndpointMapping, spec *openapi.OpenAPISpec) map[string]interface{} {
+	resources := make([]string, 0, len(resourceMappings))
+	for resource := range resourceMappings {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	envDefaults := PathParamEnvVarMap()
+	parameters := make(map[string]*resourceParameter)
+	add := func(name, resource string, required bool, schema map[string]interface{}) {
+		if name == "" || name == "resource" {
+			return
+		}
+		param, exists := parameters[name]
+		if !exists {
+			param = &resourceParameter{required: make(map[string]bool), types: make(map[string]string)}
+			parameters[name] = param
+		}
+		if _, hasDefault := envDefaults[name]; required && !hasDefault {
+			param.required[resource] = true
+		}
+		if _, listed := param.types[resource]; listed {
+			return
+		}
+		param.resources = append(param.resources, resource)
+		param.types[resource] = propertyType(schema)
+		for i, known := range param.schemas {
+			if propertyType(known) != propertyType(schema) {
+				continue
+			}
+			// Resources allowing different values of the same type leave the value unrestricted
+			if _, restricted := known["enum"]; restricted && !reflect.DeepEqual(known["enum"], schema["enum"]) {
+				unrestricted := maps.Clone(known)
+				delete(unrestricted, "enum")
+				param.schemas[i] = unrestricted
+			}
+			return
+		}
+		param.schemas = append(param.schemas, schema)
+	}
+
+	for _, resource := range resources {
+		mapping := resourceMappings[resource]
+		required := make(map[string]bool, len(mapping.RequiredParams))
+		for _, name := range mapping.RequiredParams {
+			required[name] = true
+		}
+		for _, name := range requestBodyRequired(mapping.RequestBodySchema) {
+			required[name] = true
+		}
+
+		bodyProperties := requestBodyProperties(mapping.RequestBodySchema)
+		for _, name := range append(append([]string(nil), mapping.RequiredParams...), mapping.OptionalParams...) {
+			schema, inBody := bodyProperties[name]
+			if !inBody {
+				schema = operationParameterSchema(spec, mapping, name)
+			}
+			add(name, resource, required[name], schema)
+		}
+		bodyNames := make([]string, 0, len(bodyProperties))
+		for name := range bodyProperties {
+			bodyNames = append(bodyNames, name)
+		}
+		sort.Strings(bodyNames)
+		for _, name := range bodyNames {
+			add(name, resource, required[name], bodyProperties[name])
+		}
+	}
+
+	properties := make(map[string]interface{}, len(parameters))
+	for name, param := range parameters {
+		properties[name] = param.property()
+	}
+	return properties
+}
+
+// property renders the JSON Schema property of a parameter
+func (p *resourceParameter) property() map[string]interface{} {
+	property := make(map[string]interface{})
+	var description string
+	if len(p.schemas) == 1 {
+		for key, value := range p.schemas[0] {
+			property[key] = value
+		}
+		property["type"] = propertyType(p.schemas[0])
+		description, _ = p.schemas[0]["description"].(string)
+	} else {
+		oneOf := make([]interface{}, 0, len(p.schemas))
+		for _, schema := range p.schemas {
+			option := map[string]interface{}{"type": propertyType(schema)}
+			if enum, ok := schema["enum"]; ok {
+				option["enum"] = enum
+			}
+			if description == "" {
+				description, _ = schema["description"].(string)
+			}
+			oneOf = append(oneOf, option)
+		}
+		property["oneOf"] = oneOf
+	}
+
+	uses := make([]string, 0, len(p.resources))
+	for _, resource := range p.resources {
+		var notes []string
+		if len(p.schemas) > 1 {
+			notes = append(notes, p.types[resource])
+		}
+		if p.required[resource] {
+			notes = append(notes, "required")
+		}
+		if len(notes) > 0 {
+			resource += " (" + strings.Join(notes, ", ") + ")"
+		}
+		uses = append(uses, resource)
+	}
+	usedBy := "Used by: " + strings.Join(uses, ", ")
+	if description != "" {
+		usedBy = strings.TrimSuffix(description, ".") + ". " + usedBy
+	}
+	property["description"] = usedBy
+	return property
+}
+
+// propertyType returns the type of a property summary, string when it declares none
+func propertyType(schema map[string]interface{}) string {
+	if t, ok := schema["type"].(string); ok && t != "" {
+		return t
+	}
+	return "string"
+}
+
+// requestBodyProperties returns the type, enum and description of each top-level field of a
+// mapping's request body schema
+func requestBodyProperties(requestBodySchema map[string]interface{}) map[string]map[string]interface{} {
+	properties := make(map[string]map[string]interface{})
+	switch schema := requestBodySchema["schema"].(type) {
+	case *openapi.Schema:
+		for name, field := range schema.Properties {
+			if field == nil {
+				properties[name] = map[string]interface{}{}
+				continue
+			}
+			properties[name] = propertySummary(field.Type, enumValues(field), nil)
+		}
+	case map[string]interface{}:
+		fields, _ := schema["properties"].(map[string]interface{})
+		for name, field := range fields {
+			fieldSchema, _ := field.(map[string]interface{})
+			properties[name] = propertySummary(fieldSchema["type"], fieldSchema["enum"], fieldSchema["description"])
+		}
+	}
+	return properties
+}
+
+// requestBodyRequired returns the required top-level fields of a mapping's request body schema
+func requestBodyRequired(requestBodySchema map[string]interface{}) []string {
+	switch schema := requestBodySchema["schema"].(type) {
+	case *openapi.Schema:
+		if schema != nil {
+			return schema.Required
+		}
+	case map[string]interface{}:
+		// A resolved $ref keeps the []string of the component; inline maps decode as []interface{}
+		switch required := schema["required"].(type) {
+		case []string:
+			return required
+		case []interface{}:
+			names := make([]string, 0, len(required))
+			for _, field := range required {
+				if name, ok := field.(string); ok {
+					names = append(names, name)
+				}
+			}
+			return names
+		}
+	}
+	return nil
+}
+
+// enumValues returns the enum of a schema, or nil when it allows any value
+func enumValues(schema *openapi.Schema) interface{} {
+	if schema == nil || len(schema.Enum) == 0 {
+		return nil
+	}
+	return schema.Enum
+}
+
+// operationParameterSchema returns the type, enum and description of an operation parameter
+func operationParameterSchema(spec *openapi.OpenAPISpec, mapping EndpointMapping, name string) map[string]interface{} {
+	if spec == nil {
+		return nil
+	}
+	operation := spec.FindOperation(mapping.Method, mapping.PathPattern)
+	if operation == nil {
+		return nil
+	}
+	for _, param := range operation.Parameters {
+		if param.Name != name {
+			continue
+		}
+		if param.Schema == nil {
+			return propertySummary(nil, nil, param.Description)
+		}
+		return propertySummary(param.Schema.Type, enumValues(param.Schema), param.Description)
+	}
+	return nil
+}
+
+// propertySummary keeps the set values of a property's type, enum and description
+func propertySummary(schemaType, enum, description interface{}) map[string]interface{} {
+	summary := make(map[string]interface{})
+	if t, ok := schemaType.(string); ok && t != "" {
+		summary["type"] = t
+	}
+	if enum != nil {
+		summary["enum"] = enum
+	}
+	if d, ok := description.(string); ok && d != "" {
+		summary["description"] = d
+	}
+	return summary
+}
@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"sort"
+	"strings"
+)
+
+// responseArray returns the name of the array property wrapping the items of an operation's
+// successful JSON response, as declared by its response schema, and the resolved schema of those
+// items. The field is empty when the response is itself an array, and both are empty when the
+// response is neither. "data" is preferred when several properties are arrays.
+func responseArray(operation *openapi.Operation, spec *openapi.OpenAPISpec) (string, interface{}) {
+	codes := make([]string, 0, len(operation.Responses))
+	for code := range operation.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		response := spec.ResolveResponseRef(operation.Responses[code])
+		for _, contentType := range orderedResponseContentTypes(response.Content) {
+			schema := spec.ResolveSchemaRef(response.Content[contentType].Schema)
+			if schemaType(schema) == ParamTypeArray {
+				return "", spec.ResolveSchemaRef(schemaItems(schema))
+			}
+			arrays := arrayProperties(schema)
+			if len(arrays) == 0 {
+				continue
+			}
+			fields := make([]string, 0, len(arrays))
+			for field := range arrays {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			field := fields[0]
+			if _, ok := arrays["data"]; ok {
+				field = "data"
+			}
+			return field, spec.ResolveSchemaRef(arrays[field])
+		}
+	}
+	return "", nil
+}
+
+// responseItemFields returns the sorted property names of the items of an operation's successful
+// response, see responseArray, so list results can be read by their declared fields
+func responseItemFields(operation *openapi.Operation, spec *openapi.OpenAPISpec) []string {
+	_, items := responseArray(operation, spec)
+	var fields []string
+	switch s := items.(type) {
+	case *openapi.Schema:
+		if s != nil {
+			for name := range s.Properties {
+				fields = append(fields, name)
+			}
+		}
+	case map[string]interface{}:
+		switch properties := s["properties"].(type) {
+		case map[string]*openapi.Schema:
+			for name := range properties {
+				fields = append(fields, name)
+			}
+		case map[string]interface{}:
+			for name := range properties {
+				fields = append(fields, name)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// orderedResponseContentTypes returns the JSON media types of a response first, then the others
+func orderedResponseContentTypes(content map[string]openapi.MediaType) []string {
+	contentTypes := make([]string, 0, len(content))
+	for contentType := range content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Slice(contentTypes, func(i, j int) bool {
+		jsonI, jsonJ := strings.Contains(contentTypes[i], "json"), strings.Contains(contentTypes[j], "json")
+		if jsonI != jsonJ {
+			return jsonI
+		}
+		return contentTypes[i] < contentTypes[j]
+	})
+	return contentTypes
+}
+
+// arrayProperties returns the array properties of an object schema and their items schemas. The
+// schema may be a parsed *openapi.Schema or a generic map from a resolved reference or inline JSON.
+func arrayProperties(schema interface{}) map[string]interface{} {
+	arrays := make(map[string]interface{})
+	switch s := schema.(type) {
+	case *openapi.Schema:
+		if s == nil {
+			return nil
+		}
+		for name, property := range s.Properties {
+			if property != nil && property.Type == ParamTypeArray {
+				arrays[name] = property.Items
+			}
+		}
+	case map[string]interface{}:
+		switch properties := s["properties"].(type) {
+		case map[string]*openapi.Schema:
+			for name, property := range properties {
+				if property != nil && property.Type == ParamTypeArray {
+					arrays[name] = property.Items
+				}
+			}
+		case map[string]interface{}:
+			for name, property := range properties {
+				if propertyMap, ok := property.(map[string]interface{}); ok && propertyMap["type"] == ParamTypeArray {
+					arrays[name] = propertyMap["items"]
+				}
+			}
+		}
+	}
+	return arrays
+}
+
+// schemaType returns the type of a parsed or generic schema
+func schemaType(schema interface{}) string {
+	switch s := schema.(type) {
+	case *openapi.Schema:
+		if s != nil {
+			return s.Type
+		}
+	case map[string]interface{}:
+		schemaType, _ := s["type"].(string)
+		return schemaType
+	}
+	return ""
+}
+
+// schemaItems returns the items schema of a parsed or generic array schema
+func schemaItems(schema interface{}) interface{} {
+	switch s := schema.(type) {
+	case *openapi.Schema:
+		if s != nil && s.Items != nil {
+			return s.Items
+		}
+	case map[string]interface{}:
+		return s["items"]
+	}
+	return nil
+}
@@ -1,11 +1,15 @@
 package tools
 
 import (
+	"errors"
 	"fmt"
 	"mcolomerc/mcp-server/internal/logger"
 	"mcolomerc/mcp-server/internal/openapi"
+	"net/url"
 	"os"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -14,16 +18,33 @@ import (
 var GlobalSemanticRegistry *SemanticToolRegistry
 var registryMutex sync.RWMutex
 
+// ErrSpecHasNoPaths is returned when an OpenAPI spec has no paths to generate tools from
+var ErrSpecHasNoPaths = errors.New("OpenAPI spec has no paths")
+
 // initializeSemanticRegistry sets up the semantic tool mappings dynamically from OpenAPI spec
 func initializeSemanticRegistry(spec openapi.OpenAPISpec) {
 	registryMutex.Lock()
-	defer registryMutex.Unlock()
+	defer func() {
+		// Cached mappings belong to the previous spec; drop them before the lock is released
+		endpointMappingCache.invalidate()
+		registryMutex.Unlock()
+	}()
 
 	logger.Debug("Building semantic registry from OpenAPI spec with %d paths\n", len(spec.Paths))
 
+	// Telemetry mappings come from the telemetry spec, which may have been generated first
+	var telemetryMappings map[string]EndpointMapping
+	if GlobalSemanticRegistry != nil {
+		telemetryMappings = GlobalSemanticRegistry.Mappings[TelemetryAction]
+	}
+
 	GlobalSemanticRegistry = &SemanticToolRegistry{
 		Mappings: make(map[string]map[string]EndpointMapping),
 		Spec:     &spec,
+		Skipped:  make(map[string]string),
+	}
+	if telemetryMappings != nil {
+		GlobalSemanticRegistry.Mappings[TelemetryAction] = telemetryMappings
 	}
 
 	// Initialize action maps
@@ -32,10 +53,21 @@ func initializeSemanticRegistry(spec openapi.OpenAPISpec) {
 		GlobalSemanticRegistry.Mappings[action] = make(map[string]EndpointMapping)
 	}
 
+	// Schema Registry settings mappings, merged once both path levels have been seen
+	settingsMappings := make(map[string]map[string]*schemaRegistrySettingsMapping)
+
 	// Parse OpenAPI paths and categorize them
 	for path, pathItem := range spec.Paths {
+		if resource, subjectLevel := schemaRegistrySettingsResource(path); resource != "" {
+			if !collectSchemaRegistrySettings(settingsMappings, resource, subjectLevel, path, &pathItem, &spec) {
+				GlobalSemanticRegistry.Skipped[path] = SkipReasonNoAction
+			}
+			continue
+		}
+
 		resource := ExtractResourceFromPath(path)
 		if resource == "" {
+			GlobalSemanticRegistry.Skipped[path] = SkipReasonNoResource
 			continue
 		}
 
@@ -44,13 +76,41 @@ func initializeSemanticRegistry(spec openapi.OpenAPISpec) {
 			logger.Debug("Processing %s resource from path: %s\n", resource, path)
 		}
 
-		// Process each HTTP method using the operations we extracted
+		// Process each HTTP method using the operations we extracted, noting why a path that
+		// produces no mapping was skipped
 		operations := extractHTTPOperations(&pathItem)
+		mapped, deprecated, filtered, withAction := false, 0, 0, 0
 		for _, op := range operations {
+			if op.Operation.Deprecated && hideDeprecatedOperations() {
+				logger.Debug("Skipping deprecated operation %s %s\n", op.Method, path)
+				deprecated++
+				continue
+			}
+			if excludedByTags(op.Operation) {
+				logger.Debug("Skipping operation %s %s filtered by tags %v\n", op.Method, path, op.Operation.Tags)
+				filtered++
+				continue
+			}
+
 			action := determineSemanticAction(op.Method, path)
 			if action != "" {
+				withAction++
 				mapping := createEndpointMapping(op.Method, path, op.Operation, &spec)
 
+				// A deprecated operation never replaces a current one for the same action and resource
+				if existing, exists := GlobalSemanticRegistry.Mappings[action][resource]; exists && mapping.Deprecated && !existing.Deprecated {
+					continue
+				}
+				mapped = true
+
+				// Operations on the same path that differ only in required query parameters are kept
+				// side by side and picked per call from the arguments
+				if existing, exists := GlobalSemanticRegistry.Mappings[action][resource]; exists && isQueryVariant(existing, mapping) {
+					GlobalSemanticRegistry.Mappings[action][resource] = mergeQueryVariant(existing, mapping)
+					logger.Debug("Mapped %s %s -> %s %s as a query variant (required query: %v)\n", action, resource, mapping.Method, mapping.PathPattern, mapping.RequiredQuery)
+					continue
+				}
+
 				// Special debug logging for subjects resource to identify the mapping issue
 				if resource == "subjects" {
 					logger.Debug("*** SUBJECTS DEBUG: Processing path=%s, method=%s, action=%s, required_params=%v\n",
@@ -73,6 +133,16 @@ func initializeSemanticRegistry(spec openapi.OpenAPISpec) {
 				logger.Debug("*** TAGS DEBUG: No action determined for %s %s (path: %s)\n", op.Method, resource, path)
 			}
 		}
+		if !mapped {
+			GlobalSemanticRegistry.Skipped[path] = skipReason(len(operations), deprecated, filtered, withAction)
+		}
+	}
+
+	registerSchemaRegistrySettings(settingsMappings)
+
+	// Singular and plural variants of a resource become one plural resource
+	if canonicalResourceNamesEnabled() {
+		canonicalizeResourceNames(GlobalSemanticRegistry.Mappings)
 	}
 
 	// Log summary
@@ -86,10 +156,193 @@ func initializeSemanticRegistry(spec openapi.OpenAPISpec) {
 	logDiscoveredResources(&spec)
 }
 
+// schemaRegistrySettingsMapping holds the top-level and subject-level mappings of one settings
+// resource and action
+type schemaRegistrySettingsMapping struct {
+	global  *EndpointMapping
+	subject *EndpointMapping
+}
+
+// schemaRegistrySettingsResource reports whether a path is a Schema Registry settings endpoint
+// (/mode, /config or their /{subject} variants) and which level it addresses
+func schemaRegistrySettingsResource(path string) (resource string, subjectLevel bool) {
+	for name := range SchemaRegistrySettingsFields {
+		switch path {
+		case PathSeparator + name:
+			return name, false
+		case PathSeparator + name + PathSeparator + "{subject}":
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// collectSchemaRegistrySettings records the operations of a settings path by action and reports
+// whether any was recorded
+func collectSchemaRegistrySettings(settings map[string]map[string]*schemaRegistrySettingsMapping, resource string, subjectLevel bool, path string, pathItem *openapi.PathItem, spec *openapi.OpenAPISpec) bool {
+	collected := false
+	for _, op := range extractHTTPOperations(pathItem) {
+		if (op.Operation.Deprecated && hideDeprecatedOperations()) || excludedByTags(op.Operation) {
+			continue
+		}
+		action := determineSemanticAction(op.Method, path)
+		if action == "" {
+			continue
+		}
+		if settings[action] == nil {
+			settings[action] = make(map[string]*schemaRegistrySettingsMapping)
+		}
+		if settings[action][resource] == nil {
+			settings[action][resource] = &schemaRegistrySettingsMapping{}
+		}
+
+		mapping := createEndpointMapping(op.Method, path, op.Operation, spec)
+		if subjectLevel {
+			settings[action][resource].subject = &mapping
+		} else {
+			settings[action][resource].global = &mapping
+		}
+		collected = true
+	}
+	return collected
+}
+
+// registerSchemaRegistrySettings adds one mapping per settings resource and action. The top-level
+// path is used by default and the subject-level path when a subject is given. Updates require the
+// setting's body field only where the spec's request body schema marks it required.
+func registerSchemaRegistrySettings(settings map[string]map[string]*schemaRegistrySettingsMapping) {
+	for action, resources := range settings {
+		if _, exists := GlobalSemanticRegistry.Mappings[action]; !exists {
+			continue
+		}
+		for resource, levels := range resources {
+			var mapping EndpointMapping
+			if levels.global != nil {
+				mapping = *levels.global
+			} else {
+				mapping = *levels.subject
+			}
+			if levels.subject != nil {
+				mapping.SubjectPathPattern = levels.subject.PathPattern
+			}
+
+			if field := SchemaRegistrySettingsFields[resource]; action == ActionUpdate && slices.Contains(requestBodyRequired(mapping.RequestBodySchema), field) {
+				if !slices.Contains(mapping.RequiredParams, field) {
+					mapping.RequiredParams = append(mapping.RequiredParams, field)
+				}
+			}
+
+			GlobalSemanticRegistry.Mappings[action][resource] = mapping
+			logger.Debug("Mapped %s %s -> %s %s (subject path: %s)\n", action, resource, mapping.Method, mapping.PathPattern, mapping.SubjectPathPattern)
+		}
+	}
+}
+
+// PathPatternFor returns the path pattern to call for the given arguments, switching to the
+// subject-level path of a Schema Registry settings mapping when a subject is supplied
+func (m *EndpointMapping) PathPatternFor(args map[string]interface{}) string {
+	if m.SubjectPathPattern != "" {
+		if subject, ok := args["subject"]; ok && subject != nil && subject != "" {
+			return m.SubjectPathPattern
+		}
+	}
+	return m.PathPattern
+}
+
+// VariantFor returns the operation to call for the given arguments among the mapping and its
+// query variants: the one with the most required query parameters that are all supplied, or the
+// mapping itself when none match
+func (m *EndpointMapping) VariantFor(args map[string]interface{}) *EndpointMapping {
+	best := m
+	bestMatched := -1
+	for _, candidate := range append([]*EndpointMapping{m}, queryVariantPointers(m)...) {
+		if len(candidate.RequiredQuery) > bestMatched && hasArguments(args, candidate.RequiredQuery) {
+			best, bestMatched = candidate, len(candidate.RequiredQuery)
+		}
+	}
+	return best
+}
+
+// queryVariantPointers returns pointers to the query variants of a mapping
+func queryVariantPointers(m *EndpointMapping) []*EndpointMapping {
+	variants := make([]*EndpointMapping, len(m.QueryVariants))
+	for i := range m.QueryVariants {
+		variants[i] = &m.QueryVariants[i]
+	}
+	return variants
+}
+
+// hasArguments reports whether every name is supplied, at the top level or in the nested
+// parameters object of a semantic tool call
+func hasArguments(args map[string]interface{}, names []string) bool {
+	nested, _ := args["parameters"].(map[string]interface{})
+	for _, name := range names {
+		value, ok := args[name]
+		if !ok || value == nil {
+			value, ok = nested[name]
+		}
+		if !ok || value == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// isQueryVariant reports whether two mappings call the same method on the same path, ignoring a
+// trailing slash and path parameter names, but require different query parameters
+func isQueryVariant(existing, mapping EndpointMapping) bool {
+	if existing.Method != mapping.Method || pathShape(existing.PathPattern) != pathShape(mapping.PathPattern) {
+		return false
+	}
+	for _, candidate := range append([]EndpointMapping{existing}, existing.QueryVariants...) {
+		if strings.Join(candidate.RequiredQuery, ",") == strings.Join(mapping.RequiredQuery, ",") {
+			return false
+		}
+	}
+	return true
+}
+
+// pathShape normalizes a path pattern for comparison
+func pathShape(path string) string {
+	parts := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	for i, part := range parts {
+		if isPathParameter(part) {
+			parts[i] = "{}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// mergeQueryVariant adds a mapping to the query variants of another. The variant with the fewest
+// required query parameters becomes the default, so the result does not depend on spec order.
+func mergeQueryVariant(existing, mapping EndpointMapping) EndpointMapping {
+	variants := append([]EndpointMapping{}, existing.QueryVariants...)
+	existing.QueryVariants = nil
+	variants = append(variants, existing, mapping)
+	sort.Slice(variants, func(i, j int) bool {
+		if len(variants[i].RequiredQuery) != len(variants[j].RequiredQuery) {
+			return len(variants[i].RequiredQuery) < len(variants[j].RequiredQuery)
+		}
+		if query := strings.Join(variants[i].RequiredQuery, ","); query != strings.Join(variants[j].RequiredQuery, ",") {
+			return query < strings.Join(variants[j].RequiredQuery, ",")
+		}
+		return variants[i].PathPattern < variants[j].PathPattern
+	})
+
+	merged := variants[0]
+	merged.QueryVariants = variants[1:]
+	return merged
+}
+
 // GenerateSemanticTools creates semantic tools from OpenAPI spec
 func GenerateSemanticTools(spec openapi.OpenAPISpec) ([]Tool, error) {
 	logger.Debug("Generating semantic tools from %d paths\n", len(spec.Paths))
 
+	// A pathless spec would silently produce zero tools, which usually means the wrong file or URL was loaded
+	if len(spec.Paths) == 0 {
+		return nil, fmt.Errorf("%w (openapi version %q): check that OPENAPI_SPEC_URL points to a valid Confluent Cloud API spec", ErrSpecHasNoPaths, spec.OpenAPI)
+	}
+
 	// Initialize the semantic registry with the OpenAPI spec
 	initializeSemanticRegistry(spec)
 
@@ -97,8 +350,8 @@ func GenerateSemanticTools(spec openapi.OpenAPISpec) ([]Tool, error) {
 
 	// Create semantic tools based on our registry
 	for action, resourceMappings := range GlobalSemanticRegistry.Mappings {
-		if len(resourceMappings) == 0 {
-			continue // Skip actions with no resources
+		if len(resourceMappings) == 0 || action == TelemetryAction {
+			continue // Skip actions with no resources; telemetry has its own tool
 		}
 
 		var supportedResources []string
@@ -106,11 +359,24 @@ func GenerateSemanticTools(spec openapi.OpenAPISpec) ([]Tool, error) {
 			supportedResources = append(supportedResources, resource)
 		}
 
+		services := mappingServices(resourceMappings)
+		description := fmt.Sprintf("%s resources. Supported resources: %s", strings.Title(action), strings.Join(supportedResources, ", "))
+		if deprecated := deprecatedResources(resourceMappings); len(deprecated) > 0 {
+			description += fmt.Sprintf(". Deprecated (may be removed): %s", strings.Join(deprecated, ", "))
+		}
+		description += fmt.Sprintf(". Services: %s", strings.Join(services, ", "))
+		if rateLimitHintsEnabled() {
+			if limits := resourceRateLimits(resourceMappings); limits != "" {
+				description += ". Rate limits: " + limits
+			}
+		}
+
 		tool := Tool{
 			Name:        action,
-			Description: fmt.Sprintf("%s resources. Supported resources: %s", strings.Title(action), strings.Join(supportedResources, ", ")),
+			Description: description,
 			Endpoint:    action,
-			Parameters:  createSemanticToolParameters(action, supportedResources),
+			Parameters:  createSemanticToolParameters(action, supportedResources, resourceMappings, &spec),
+			Services:    services,
 		}
 
 		tools = append(tools, tool)
@@ -120,8 +386,27 @@ func GenerateSemanticTools(spec openapi.OpenAPISpec) ([]Tool, error) {
 	return tools, nil
 }
 
-// createSemanticToolParameters creates parameters for semantic tools
-func createSemanticToolParameters(action string, supportedResources []string) map[string]interface{} {
+// mappingServices returns the sorted services of an action's resource mappings
+func mappingServices(resourceMappings map[string]EndpointMapping) []string {
+	seen := make(map[string]bool)
+	var services []string
+	for _, mapping := range resourceMappings {
+		service := mapping.Service
+		if service == "" {
+			service = ServiceForPath(mapping.PathPattern)
+		}
+		if !seen[service] {
+			seen[service] = true
+			services = append(services, service)
+		}
+	}
+	sort.Strings(services)
+	return services
+}
+
+// createSemanticToolParameters creates parameters for semantic tools; the parameters object lists
+// the parameters of the resources, and their request body examples are given in its description
+func createSemanticToolParameters(action string, supportedResources []string, resourceMappings map[string]EndpointMapping, spec *openapi.OpenAPISpec) map[string]interface{} {
 	properties := map[string]interface{}{
 		"resource": map[string]interface{}{
 			"type":        "string",
@@ -133,8 +418,8 @@ func createSemanticToolParameters(action string, supportedResources []string) ma
 	// Add dynamic parameters section that will be populated based on resource choice
 	properties["parameters"] = map[string]interface{}{
 		"type":        "object",
-		"description": "Parameters specific to the chosen resource and action",
-		"properties":  map[string]interface{}{},
+		"description": describeBodyExamples("Parameters specific to the chosen resource and action", requestBodyExamples(resourceMappings)),
+		"properties":  resourceParameterProperties(resourceMappings, spec),
 	}
 
 	return map[string]interface{}{
@@ -144,8 +429,26 @@ func createSemanticToolParameters(action string, supportedResources []string) ma
 	}
 }
 
-// GetEndpointMapping retrieves the endpoint mapping for a given action and resource
+// GetEndpointMapping retrieves the endpoint mapping for a given action and resource,
+// serving repeated lookups from the mapping cache. The mapping is the caller's own copy.
 func GetEndpointMapping(action, resource string) (*EndpointMapping, error) {
+	key := mappingCacheKey{action: action, resource: resource}
+	cached, generation, ok := endpointMappingCache.get(key)
+	if ok {
+		return cached, nil
+	}
+
+	mapping, err := lookupEndpointMapping(action, resource)
+	if err != nil {
+		return nil, err
+	}
+	endpointMappingCache.put(key, *mapping, generation)
+	cloned := cloneEndpointMapping(*mapping)
+	return &cloned, nil
+}
+
+// lookupEndpointMapping reads the endpoint mapping for a given action and resource from the registry
+func lookupEndpointMapping(action, resource string) (*EndpointMapping, error) {
 	registryMutex.RLock()
 	defer registryMutex.RUnlock()
 
@@ -160,7 +463,12 @@ func GetEndpointMapping(action, resource string) (*EndpointMapping, error) {
 
 	mapping, exists := resourceMappings[resource]
 	if !exists {
-		return nil, fmt.Errorf("resource '%s' not supported for action '%s'", resource, action)
+		// Accept the singular/plural alias of a mapped resource, e.g. "topic" for "topics"
+		canonical, found := ResolveResourceName(resource, mappedResourceNames(resourceMappings))
+		if !found {
+			return nil, fmt.Errorf("resource '%s' not supported for action '%s'", resource, action)
+		}
+		mapping = resourceMappings[canonical]
 	}
 
 	// Debug logging for subjects to track what mapping is being returned
@@ -172,12 +480,97 @@ func GetEndpointMapping(action, resource string) (*EndpointMapping, error) {
 	return &mapping, nil
 }
 
-// GetRequiredParametersForResource returns the required parameters for a specific action+resource combination
-func GetRequiredParametersForResource(action, resource string) ([]string, error) {
+// mappedResourceNames returns the resource names of an action's mappings
+func mappedResourceNames(resourceMappings map[string]EndpointMapping) []string {
+	names := make([]string, 0, len(resourceMappings))
+	for name := range resourceMappings {
+		names = append(names, name)
+	}
+	return names
+}
+
+// GetSupportedResources returns the sorted resources the registry maps for an action
+func GetSupportedResources(action string) []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	if GlobalSemanticRegistry == nil {
+		return nil
+	}
+
+	var resources []string
+	for resource := range GlobalSemanticRegistry.Mappings[action] {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// GetRegistrySpec returns the main spec the registry was last built from, or nil before it is built
+func GetRegistrySpec() *openapi.OpenAPISpec {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	if GlobalSemanticRegistry == nil {
+		return nil
+	}
+	return GlobalSemanticRegistry.Spec
+}
+
+// GetActionMappings returns a copy of the endpoint mappings the registry holds for an action,
+// keyed by resource
+func GetActionMappings(action string) map[string]EndpointMapping {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	if GlobalSemanticRegistry == nil {
+		return nil
+	}
+
+	mappings := make(map[string]EndpointMapping, len(GlobalSemanticRegistry.Mappings[action]))
+	for resource, mapping := range GlobalSemanticRegistry.Mappings[action] {
+		mappings[resource] = mapping
+	}
+	return mappings
+}
+
+// GetSupportedActions returns the sorted actions the registry maps at least one resource for,
+// including the telemetry action
+func GetSupportedActions() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	if GlobalSemanticRegistry == nil {
+		return nil
+	}
+
+	var actions []string
+	for action, resourceMappings := range GlobalSemanticRegistry.Mappings {
+		if len(resourceMappings) > 0 {
+			actions = append(actions, action)
+		}
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// GetEndpointMappingForArgs retrieves the endpoint mapping for a given action and resource,
+// choosing among same-path query variants by the supplied arguments
+func GetEndpointMappingForArgs(action, resource string, args map[string]interface{}) (*EndpointMapping, error) {
 	mapping, err := GetEndpointMapping(action, resource)
 	if err != nil {
 		return nil, err
 	}
+	return mapping.VariantFor(args), nil
+}
+
+// GetRequiredParametersForResource returns the required parameters for a specific action+resource
+// combination, of the query variant the arguments select
+func GetRequiredParametersForResource(action, resource string, args map[string]interface{}) ([]string, error) {
+	mapping, err := GetEndpointMappingForArgs(action, resource, args)
+	if err != nil {
+		return nil, err
+	}
 	return mapping.RequiredParams, nil
 }
 
@@ -203,11 +596,12 @@ func PathParamEnvVarMap() map[string]string {
 func BuildAPIPath(pathPattern string, params map[string]interface{}) string {
 	path := pathPattern
 
-	// First, fill from params if present
+	// First, fill from params if present. Values are escaped as one path segment, so an id
+	// containing "/" or spaces cannot change the shape of the path.
 	for key, value := range params {
 		placeholder := fmt.Sprintf("{%s}", key)
 		if strings.Contains(path, placeholder) {
-			path = strings.ReplaceAll(path, placeholder, fmt.Sprintf("%v", value))
+			path = strings.ReplaceAll(path, placeholder, url.PathEscape(pathParamValue(value)))
 		}
 	}
 
@@ -217,7 +611,7 @@ func BuildAPIPath(pathPattern string, params map[string]interface{}) string {
 		placeholder := fmt.Sprintf("{%s}", param)
 		if strings.Contains(path, placeholder) {
 			if val := os.Getenv(envVar); val != "" {
-				path = strings.ReplaceAll(path, placeholder, val)
+				path = strings.ReplaceAll(path, placeholder, url.PathEscape(val))
 			}
 		}
 	}
@@ -225,6 +619,19 @@ func BuildAPIPath(pathPattern string, params map[string]interface{}) string {
 	return path
 }
 
+// pathParamValue renders a path parameter value. Numbers decoded from JSON arrive as floats, so
+// they are written in plain decimal form without trailing zeros: 123.0 becomes 123, never 1.23e+02.
+func pathParamValue(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
 // ExtractResourceFromPath extracts the primary resource name from an API path
 func ExtractResourceFromPath(path string) string {
 	parts := strings.Split(strings.TrimPrefix(path, PathSeparator), PathSeparator)
@@ -303,8 +710,8 @@ func isVersionPattern(part string) bool {
 
 // isLikelyResourceName determines if a path component looks like a resource name
 func isLikelyResourceName(part string) bool {
-	// Use heuristics only - no hardcoded exclusions
-	return isPluralResourceName(part)
+	// Use heuristics, plus the singular names allowlisted in SINGULAR_RESOURCES
+	return isPluralResourceName(part) || isSingularResource(part)
 }
 
 // isPluralResourceName checks if a part looks like a plural resource name using improved heuristics
@@ -344,6 +751,11 @@ func hasCommonPluralPattern(part string) bool {
 
 // determineSemanticAction maps HTTP method and path pattern to semantic action
 func determineSemanticAction(httpMethod, path string) string {
+	// Configured action rules take precedence over the built-in mapping
+	if action := matchSemanticActionRule(httpMethod, path); action != "" {
+		return action
+	}
+
 	// Special handling for catalog entity tag operations
 	if strings.Contains(path, "/catalog/v1/entity/tags") && !strings.Contains(path, "/{") {
 		// Bulk tag operations (no path parameters)
@@ -387,6 +799,12 @@ func determineSemanticAction(httpMethod, path string) string {
 
 // determineGetAction determines if GET operation is list or get
 func determineGetAction(path string) string {
+	// An allowlisted singular resource is a single object, so reading it is a get
+	segments := strings.Split(strings.Trim(path, PathSeparator), PathSeparator)
+	if isSingularResource(segments[len(segments)-1]) {
+		return ActionGet
+	}
+
 	// If path has no parameters, it's likely a list operation
 	if !strings.Contains(path, "{") {
 		return ActionList
@@ -449,28 +867,40 @@ func createEndpointMapping(httpMethod, path string, operation *openapi.Operation
 		return EndpointMapping{
 			Method:      httpMethod,
 			PathPattern: path,
+			Service:     ServiceForPath(path),
 		}
 	}
 
 	mapping := EndpointMapping{
 		Method:      httpMethod,
 		PathPattern: path,
+		Deprecated:  operation.Deprecated,
+		Service:     ServiceForPath(path),
+		RateLimit:   operation.RateLimitHints(),
 	}
 
 	// Extract parameters from operation
 	mapping.RequiredParams, mapping.OptionalParams = extractOperationParameters(operation)
+	mapping.RequiredQuery = extractRequiredQueryParameters(operation)
+	if httpMethod == HTTPMethodGet {
+		mapping.ResponseArrayField, _ = responseArray(operation, spec)
+		mapping.ResponseItemFields = responseItemFields(operation, spec)
+	}
 
 	// Extract path parameters and ensure they're marked as required
 	mapping.RequiredParams = ensurePathParametersRequired(path, mapping.RequiredParams)
 
 	// Extract request body info if present
 	if operation.RequestBody != nil {
-		if info := extractRequestBodySchema(operation.RequestBody, spec); info != nil {
+		if info := extractRequestBodySchema(operation.RequestBody, spec, path); info != nil {
 			// Store schema and content type in a map
 			mapping.RequestBodySchema = map[string]interface{}{
 				"schema":      info.Schema,
 				"contentType": info.ContentType,
 			}
+			if info.Example != nil {
+				mapping.RequestBodySchema["example"] = info.Example
+			}
 			// If schema is a map, add its required fields
 			if schemaMap, ok := info.Schema.(map[string]interface{}); ok {
 				mapping.RequiredParams = addRequiredFieldsFromSchema(
@@ -495,6 +925,18 @@ func extractOperationParameters(operation *openapi.Operation) (required, optiona
 	return required, optional
 }
 
+// extractRequiredQueryParameters returns the sorted names of the operation's required query parameters
+func extractRequiredQueryParameters(operation *openapi.Operation) []string {
+	var required []string
+	for _, param := range operation.Parameters {
+		if param.In == "query" && param.Required {
+			required = append(required, param.Name)
+		}
+	}
+	sort.Strings(required)
+	return required
+}
+
 // ensurePathParametersRequired ensures all path parameters are marked as required
 func ensurePathParametersRequired(path string, existingRequired []string) []string {
 	pathParams := ExtractPathParameters(path)
@@ -535,8 +977,9 @@ func addRequiredFieldsFromSchema(requestBodySchema map[string]interface{}, exist
 	return existingRequired
 }
 
-// extractRequestBodySchema extracts schema information from request body
-func extractRequestBodySchema(requestBody *openapi.RequestBody, spec *openapi.OpenAPISpec) *RequestBodyInfo {
+// extractRequestBodySchema extracts schema information from request body, choosing the
+// content type according to the configured preference order for the path's service
+func extractRequestBodySchema(requestBody *openapi.RequestBody, spec *openapi.OpenAPISpec, path string) *RequestBodyInfo {
 	logger.Debug("extractRequestBodySchema called with requestBody: %+v\n", requestBody)
 
 	if requestBody == nil {
@@ -553,50 +996,31 @@ func extractRequestBodySchema(requestBody *openapi.RequestBody, spec *openapi.Op
 		return nil
 	}
 
-	// Look for JSON content type first
-	for contentType, mediaType := range resolvedRequestBody.Content {
-		if contentType == ContentTypeJSON || contentType == ContentTypeConfluentJSON {
-			if mediaType.Schema != nil {
-				// Resolve schema reference if needed
-				resolvedSchema := spec.ResolveSchemaRef(mediaType.Schema)
-				logger.Debug("Resolved schema: %+v\n", resolvedSchema)
-
-				// Handle *Schema struct from OpenAPI parser
-				if schema, ok := resolvedSchema.(*openapi.Schema); ok {
-					return &RequestBodyInfo{
-						Schema:      schema,
-						ContentType: contentType,
-					}
-				}
-				// fallback for map[string]interface{} (legacy)
-				if schemaMap, ok := resolvedSchema.(map[string]interface{}); ok {
-					return &RequestBodyInfo{
-						Schema:      schemaMap,
-						ContentType: contentType,
-					}
-				}
-			}
+	// Walk content types in preference order, falling back to any other available type
+	for _, contentType := range orderedContentTypes(resolvedRequestBody.Content, path) {
+		mediaType := resolvedRequestBody.Content[contentType]
+		if mediaType.Schema == nil {
+			continue
 		}
-	}
 
-	// Fallback to any available content type
-	for contentType, mediaType := range resolvedRequestBody.Content {
-		if mediaType.Schema != nil {
-			// Resolve schema reference if needed
-			resolvedSchema := spec.ResolveSchemaRef(mediaType.Schema)
-			logger.Debug("Fallback resolved schema: %+v\n", resolvedSchema)
+		// Resolve schema reference if needed
+		resolvedSchema := spec.ResolveSchemaRef(mediaType.Schema)
+		logger.Debug("Resolved schema for %s: %+v\n", contentType, resolvedSchema)
 
-			if schema, ok := resolvedSchema.(*openapi.Schema); ok {
-				return &RequestBodyInfo{
-					Schema:      schema,
-					ContentType: contentType,
-				}
+		// Handle *Schema struct from OpenAPI parser
+		if schema, ok := resolvedSchema.(*openapi.Schema); ok {
+			return &RequestBodyInfo{
+				Schema:      schema,
+				ContentType: contentType,
+				Example:     mediaType.ExampleValue(),
 			}
-			if schemaMap, ok := resolvedSchema.(map[string]interface{}); ok {
-				return &RequestBodyInfo{
-					Schema:      schemaMap,
-					ContentType: contentType,
-				}
+		}
+		// fallback for map[string]interface{} (legacy)
+		if schemaMap, ok := resolvedSchema.(map[string]interface{}); ok {
+			return &RequestBodyInfo{
+				Schema:      schemaMap,
+				ContentType: contentType,
+				Example:     mediaType.ExampleValue(),
 			}
 		}
 	}
@@ -617,7 +1041,8 @@ func ExtractPathParameters(path string) []string {
 	return params
 }
 
-// ResolveResourceSchema resolves resource schema for a given action and resource
+// ResolveResourceSchema resolves resource schema for a given action and resource. The schema is
+// resolved once when the registry is built and served from the mapping cache afterwards.
 func ResolveResourceSchema(action, resource string) (map[string]interface{}, error) {
 	schema, err := GetParameterSchemaForResource(action, resource)
 	if err != nil {
@@ -691,6 +1116,12 @@ func GenerateSemanticToolsFromBothSpecs(mainSpec openapi.OpenAPISpec, telemetryS
 	allTools = append(allTools, mainTools...)
 	allTools = append(allTools, telemetryTools...)
 
+	// Semantic tool names are routed on as-is, so a clash cannot be suffixed away; the later
+	// registration would silently replace the earlier one
+	if duplicates := findDuplicateToolNames(allTools); len(duplicates) > 0 {
+		return nil, fmt.Errorf("duplicate tool names: %s", strings.Join(duplicates, ", "))
+	}
+
 	return allTools, nil
 }
 
@@ -698,20 +1129,22 @@ func GenerateSemanticToolsFromBothSpecs(mainSpec openapi.OpenAPISpec, telemetryS
 func GenerateSemanticToolsForTelemetry(spec openapi.OpenAPISpec) ([]Tool, error) {
 	// We'll store telemetry mappings in the global registry with a special prefix
 	registryMutex.Lock()
-	defer registryMutex.Unlock()
-
-	// Ensure global registry exists
+	defer func() {
+		// Cached telemetry mappings belong to the previous spec; drop them before the lock is released
+		endpointMappingCache.invalidate()
+		registryMutex.Unlock()
+	}()
+
+	// Ensure global registry exists. Its Spec stays the main spec: telemetry generation neither
+	// needs nor replaces the main spec state, so the two can be generated in either order.
 	if GlobalSemanticRegistry == nil {
 		GlobalSemanticRegistry = &SemanticToolRegistry{
 			Mappings: make(map[string]map[string]EndpointMapping),
-			Spec:     &spec,
 		}
 	}
 
-	// Initialize telemetry action if it doesn't exist
-	if GlobalSemanticRegistry.Mappings["get_telemetry"] == nil {
-		GlobalSemanticRegistry.Mappings["get_telemetry"] = make(map[string]EndpointMapping)
-	}
+	// Telemetry mappings are rebuilt from this spec, leaving the main spec actions untouched
+	GlobalSemanticRegistry.Mappings[TelemetryAction] = make(map[string]EndpointMapping)
 
 	// Parse OpenAPI paths and categorize them for telemetry
 	resourceSet := make(map[string]bool) // Use a set to avoid duplicates
@@ -726,6 +1159,9 @@ func GenerateSemanticToolsForTelemetry(spec openapi.OpenAPISpec) ([]Tool, error)
 		// Process each HTTP method using the operations we extracted
 		operations := extractHTTPOperations(&pathItem)
 		for _, op := range operations {
+			if (op.Operation.Deprecated && hideDeprecatedOperations()) || excludedByTags(op.Operation) {
+				continue
+			}
 			action := determineSemanticActionForTelemetry(op.Method, path)
 			if action != "" {
 				mapping := EndpointMapping{
@@ -733,10 +1169,11 @@ func GenerateSemanticToolsForTelemetry(spec openapi.OpenAPISpec) ([]Tool, error)
 					PathPattern:    path,
 					RequiredParams: []string{"dataset"}, // Dataset is always required for telemetry
 					OptionalParams: []string{},
+					Service:        ServiceTelemetry,
 				}
 
 				// Store in global registry with telemetry prefix
-				GlobalSemanticRegistry.Mappings["get_telemetry"][resource] = mapping
+				GlobalSemanticRegistry.Mappings[TelemetryAction][resource] = mapping
 				resourceSet[resource] = true // Add to set to avoid duplicates
 
 				logger.Debug("Mapped telemetry resource '%s' to %s %s\n", resource, op.Method, path)
@@ -757,10 +1194,11 @@ func GenerateSemanticToolsForTelemetry(spec openapi.OpenAPISpec) ([]Tool, error)
 	var tools []Tool
 	if len(supportedResources) > 0 {
 		tool := Tool{
-			Name:        "get_telemetry",
+			Name:        TelemetryAction,
 			Description: fmt.Sprintf("Get telemetry data from Confluent Telemetry API. Supported resources: %s", strings.Join(supportedResources, ", ")),
-			Endpoint:    "get_telemetry", // This will be resolved during invocation
+			Endpoint:    TelemetryAction, // This will be resolved during invocation
 			Parameters:  createTelemetryToolParameters(supportedResources),
+			Services:    []string{ServiceTelemetry},
 		}
 		tools = append(tools, tool)
 	}
@@ -819,8 +1257,26 @@ func createTelemetryToolParameters(supportedResources []string) map[string]inter
 	}
 }
 
-// GetTelemetryEndpointMapping retrieves the endpoint mapping for a telemetry resource
+// GetTelemetryEndpointMapping retrieves the endpoint mapping for a telemetry resource,
+// serving repeated lookups from the mapping cache
 func GetTelemetryEndpointMapping(resource string) (*EndpointMapping, error) {
+	key := mappingCacheKey{action: TelemetryAction, resource: resource}
+	cached, generation, ok := endpointMappingCache.get(key)
+	if ok {
+		return cached, nil
+	}
+
+	mapping, err := lookupTelemetryEndpointMapping(resource)
+	if err != nil {
+		return nil, err
+	}
+	endpointMappingCache.put(key, *mapping, generation)
+	cloned := cloneEndpointMapping(*mapping)
+	return &cloned, nil
+}
+
+// lookupTelemetryEndpointMapping reads the endpoint mapping for a telemetry resource from the registry
+func lookupTelemetryEndpointMapping(resource string) (*EndpointMapping, error) {
 	registryMutex.RLock()
 	defer registryMutex.RUnlock()
 
@@ -829,7 +1285,7 @@ func GetTelemetryEndpointMapping(resource string) (*EndpointMapping, error) {
 	}
 
 	// Look for telemetry mappings in the global registry
-	if resourceMappings, exists := GlobalSemanticRegistry.Mappings["get_telemetry"]; exists {
+	if resourceMappings, exists := GlobalSemanticRegistry.Mappings[TelemetryAction]; exists {
 		if mapping, exists := resourceMappings[resource]; exists {
 			return &mapping, nil
 		}
@@ -1,6 +1,13 @@
 package tools
 
 import (
+	"errors"
+	"mcolomerc/mcp-server/internal/openapi"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -84,3 +91,735 @@ func TestIsLikelyResourceName_ConfigsExclusion(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSemanticTools_SpecWithoutPaths(t *testing.T) {
+	testCases := []struct {
+		desc string
+		spec openapi.OpenAPISpec
+	}{
+		{
+			desc: "Empty spec",
+			spec: openapi.OpenAPISpec{},
+		},
+		{
+			desc: "Spec with empty paths section",
+			spec: openapi.OpenAPISpec{OpenAPI: "3.0.0", Paths: map[string]openapi.PathItem{}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			generated, err := GenerateSemanticTools(tc.spec)
+			if !errors.Is(err, ErrSpecHasNoPaths) {
+				t.Errorf("Expected ErrSpecHasNoPaths, got %v", err)
+			}
+			if generated != nil {
+				t.Errorf("Expected no tools, got %d", len(generated))
+			}
+		})
+	}
+}
+
+func TestGenerateToolsWithoutTelemetrySpec(t *testing.T) {
+	mainSpecPath := filepath.Join(t.TempDir(), "main.json")
+	mainSpec := `{"openapi": "3.0.0", "paths": {"/org/v2/environments": {"get": {"operationId": "listEnvironments"}}}}`
+	if err := os.WriteFile(mainSpecPath, []byte(mainSpec), 0o644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	t.Setenv("OPENAPI_SPEC_URL", mainSpecPath)
+
+	t.Run("Missing default telemetry spec keeps the main tools", func(t *testing.T) {
+		// The default telemetry spec path is relative to the repository root, so it is missing here
+		t.Setenv("TELEMETRY_OPENAPI_SPEC_URL", "")
+
+		spec, telemetrySpec, err := openapi.LoadBothSpecs()
+		if err != nil {
+			t.Fatalf("Expected loading to continue without telemetry, got %v", err)
+		}
+
+		generated, err := GenerateSemanticToolsFromBothSpecs(*spec, *telemetrySpec)
+		if err != nil {
+			t.Fatalf("Failed to generate tools: %v", err)
+		}
+		var names []string
+		for _, tool := range generated {
+			names = append(names, tool.Name)
+		}
+		if len(names) != 1 || names[0] != ActionList {
+			t.Errorf("Expected only the main spec list tool, got %v", names)
+		}
+	})
+
+	t.Run("Explicit telemetry spec that fails to load is an error", func(t *testing.T) {
+		t.Setenv("TELEMETRY_OPENAPI_SPEC_URL", filepath.Join(t.TempDir(), "missing.yaml"))
+
+		if _, _, err := openapi.LoadBothSpecs(); err == nil {
+			t.Error("Expected an error for an explicitly configured telemetry spec")
+		}
+	})
+}
+
+func TestGenerateTelemetryAndMainToolsInAnyOrder(t *testing.T) {
+	previous := GlobalSemanticRegistry
+	t.Cleanup(func() {
+		GlobalSemanticRegistry = previous
+		endpointMappingCache.invalidate()
+	})
+
+	mainSpec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/org/v2/environments": {Get: &openapi.Operation{OperationID: "listEnvironments"}},
+		},
+	}
+	telemetrySpec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/v2/metrics/{dataset}/query": {Post: &openapi.Operation{OperationID: "QueryV2"}},
+		},
+	}
+
+	toolNames := func(generated []Tool) map[string]bool {
+		names := map[string]bool{}
+		for _, tool := range generated {
+			names[tool.Name] = true
+		}
+		return names
+	}
+
+	t.Run("Telemetry only", func(t *testing.T) {
+		GlobalSemanticRegistry = nil
+		generated, err := GenerateSemanticToolsForTelemetry(telemetrySpec)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if names := toolNames(generated); len(names) != 1 || !names[TelemetryAction] {
+			t.Errorf("Expected only the telemetry tool, got %v", names)
+		}
+		if _, err := GetTelemetryEndpointMapping("metrics"); err != nil {
+			t.Errorf("Expected the telemetry mapping, got %v", err)
+		}
+		if _, err := GetEndpointMapping(ActionList, "environments"); err == nil {
+			t.Error("Expected no main spec mappings without the main spec")
+		}
+	})
+
+	orders := []struct {
+		name          string
+		telemetryLast bool
+	}{
+		{name: "Main then telemetry", telemetryLast: true},
+		{name: "Telemetry then main", telemetryLast: false},
+	}
+
+	for _, order := range orders {
+		t.Run(order.name, func(t *testing.T) {
+			GlobalSemanticRegistry = nil
+			var generated []Tool
+			generate := []func() ([]Tool, error){
+				func() ([]Tool, error) { return GenerateSemanticTools(mainSpec) },
+				func() ([]Tool, error) { return GenerateSemanticToolsForTelemetry(telemetrySpec) },
+			}
+			if !order.telemetryLast {
+				generate[0], generate[1] = generate[1], generate[0]
+			}
+			for _, step := range generate {
+				tools, err := step()
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				generated = append(generated, tools...)
+			}
+
+			if names := toolNames(generated); len(generated) != 2 || !names[ActionList] || !names[TelemetryAction] {
+				t.Errorf("Expected one list and one telemetry tool, got %v", names)
+			}
+			if _, err := GetEndpointMapping(ActionList, "environments"); err != nil {
+				t.Errorf("Expected the main spec mapping, got %v", err)
+			}
+			if _, err := GetTelemetryEndpointMapping("metrics"); err != nil {
+				t.Errorf("Expected the telemetry mapping, got %v", err)
+			}
+			if GlobalSemanticRegistry.Spec == nil || GlobalSemanticRegistry.Spec.Paths["/org/v2/environments"].Get == nil {
+				t.Error("Expected the registry to reference the main spec")
+			}
+		})
+	}
+}
+
+func TestGenerateSemanticToolsServiceTags(t *testing.T) {
+	previous := GlobalSemanticRegistry
+	t.Cleanup(func() {
+		GlobalSemanticRegistry = previous
+		endpointMappingCache.invalidate()
+	})
+
+	mainSpec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/org/v2/environments":                   {Get: &openapi.Operation{}},
+			"/kafka/v3/clusters/{cluster_id}/topics": {Get: &openapi.Operation{}},
+			"/sql/v1/statements":                     {Get: &openapi.Operation{}},
+			"/subjects":                              {Get: &openapi.Operation{}},
+			"/tableflow/v1/tableflow-topics":         {Get: &openapi.Operation{}},
+		},
+	}
+	telemetrySpec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/v2/metrics/{dataset}/query": {Post: &openapi.Operation{}},
+		},
+	}
+
+	GlobalSemanticRegistry = nil
+	generated, err := GenerateSemanticToolsFromBothSpecs(mainSpec, telemetrySpec)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	services := map[string][]string{}
+	for _, tool := range generated {
+		services[tool.Name] = tool.Services
+	}
+	wantList := []string{ServiceCloud, ServiceFlink, ServiceKafka, ServiceSchemaRegistry, ServiceTableflow}
+	if !reflect.DeepEqual(services[ActionList], wantList) {
+		t.Errorf("Expected list tool services %v, got %v", wantList, services[ActionList])
+	}
+	if !reflect.DeepEqual(services[TelemetryAction], []string{ServiceTelemetry}) {
+		t.Errorf("Expected the telemetry tool to be tagged %s, got %v", ServiceTelemetry, services[TelemetryAction])
+	}
+
+	for _, tool := range generated {
+		if tool.Name == ActionList && !strings.Contains(tool.Description, "Services: "+strings.Join(wantList, ", ")) {
+			t.Errorf("Expected the services in the list tool description, got %q", tool.Description)
+		}
+	}
+
+	resourceServices := map[string]string{
+		"topics":     ServiceKafka,
+		"statements": ServiceFlink,
+		"subjects":   ServiceSchemaRegistry,
+	}
+	for resource, want := range resourceServices {
+		mapping, err := GetEndpointMapping(ActionList, resource)
+		if err != nil {
+			t.Fatalf("Expected a list mapping for %s: %v", resource, err)
+		}
+		if mapping.Service != want {
+			t.Errorf("Expected %s to belong to %s, got %q", resource, want, mapping.Service)
+		}
+	}
+	if mapping, err := GetTelemetryEndpointMapping("metrics"); err != nil || mapping.Service != ServiceTelemetry {
+		t.Errorf("Expected the telemetry mapping to belong to %s, got %+v (%v)", ServiceTelemetry, mapping, err)
+	}
+}
+
+func TestGenerateSemanticToolsBodyExamples(t *testing.T) {
+	longName := strings.Repeat("x", MaxExampleLength)
+	spec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Post: &openapi.Operation{RequestBody: &openapi.RequestBody{Content: map[string]openapi.MediaType{
+					ContentTypeJSON: {
+						Schema:  &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{"topic_name": {Type: "string"}}},
+						Example: map[string]interface{}{"topic_name": "orders", "partitions_count": 6},
+					},
+				}}},
+			},
+			"/iam/v2/service-accounts": {
+				Post: &openapi.Operation{RequestBody: &openapi.RequestBody{Content: map[string]openapi.MediaType{
+					ContentTypeJSON: {
+						Schema: &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{"display_name": {Type: "string"}}},
+						Examples: map[string]openapi.Example{
+							"long": {Value: map[string]interface{}{"display_name": longName}},
+						},
+					},
+				}}},
+			},
+		},
+	}
+
+	generated, err := GenerateSemanticTools(spec)
+	if err != nil {
+		t.Fatalf("Failed to generate tools: %v", err)
+	}
+
+	var description string
+	for _, tool := range generated {
+		if tool.Name == ActionCreate {
+			properties := tool.Parameters["properties"].(map[string]interface{})
+			description = properties["parameters"].(map[string]interface{})["description"].(string)
+		}
+	}
+
+	if !strings.Contains(description, `topics: {"partitions_count":6,"topic_name":"orders"}`) {
+		t.Errorf("Expected the topics example in the parameters description, got %q", description)
+	}
+	if !strings.Contains(description, `service-accounts: {"display_name":"xxx`) || !strings.Contains(description, "x...; topics") {
+		t.Errorf("Expected the long service-accounts example to be trimmed, got %q", description)
+	}
+	if strings.Contains(description, longName) {
+		t.Error("Expected the long example not to be included in full")
+	}
+}
+
+func TestGetEndpointMappingQueryVariants(t *testing.T) {
+	queryParam := func(name string) openapi.Parameter {
+		return openapi.Parameter{Name: name, In: "query", Required: true, Schema: &openapi.Schema{Type: "string"}}
+	}
+	spec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/iam/v2/role-bindings": {
+				Get: &openapi.Operation{Summary: "List role bindings for a principal", Parameters: []openapi.Parameter{queryParam("principal")}},
+			},
+			"/iam/v2/role-bindings/": {
+				Get: &openapi.Operation{Summary: "List role bindings matching a CRN", Parameters: []openapi.Parameter{queryParam("crn_pattern")}},
+			},
+		},
+	}
+	if _, err := GenerateSemanticTools(spec); err != nil {
+		t.Fatalf("Failed to generate tools: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		expectedPath string
+		expectedReq  string
+	}{
+		{"Principal selects its operation", map[string]interface{}{"principal": "User:u-123"}, "/iam/v2/role-bindings", "principal"},
+		{"CRN pattern selects its operation", map[string]interface{}{"crn_pattern": "crn://confluent.cloud/*"}, "/iam/v2/role-bindings/", "crn_pattern"},
+		{"Nested parameters select an operation", map[string]interface{}{"parameters": map[string]interface{}{"principal": "User:u-123"}}, "/iam/v2/role-bindings", "principal"},
+		{"No distinguishing argument uses the default", map[string]interface{}{}, "/iam/v2/role-bindings/", "crn_pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := GetEndpointMappingForArgs(ActionList, "role-bindings", tt.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if mapping.PathPattern != tt.expectedPath {
+				t.Errorf("Expected path %s, got %s", tt.expectedPath, mapping.PathPattern)
+			}
+			required, err := GetRequiredParametersForResource(ActionList, "role-bindings", tt.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(required) != 1 || required[0] != tt.expectedReq {
+				t.Errorf("Expected required params [%s], got %v", tt.expectedReq, required)
+			}
+		})
+	}
+}
+
+func TestBuildAPIPathNumericAndBooleanParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{name: "Whole float", value: float64(123.0), expected: "/schemas/ids/123"},
+		{name: "Large whole float", value: float64(1234567), expected: "/schemas/ids/1234567"},
+		{name: "Fractional float", value: 1.5, expected: "/schemas/ids/1.5"},
+		{name: "Float32", value: float32(42), expected: "/schemas/ids/42"},
+		{name: "Integer", value: 7, expected: "/schemas/ids/7"},
+		{name: "Boolean", value: true, expected: "/schemas/ids/true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := BuildAPIPath("/schemas/ids/{id}", map[string]interface{}{"id": tt.value})
+			if path != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, path)
+			}
+		})
+	}
+}
+
+func TestBuildAPIPathEscapesValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{name: "Slash", value: "team/orders", expected: "/subjects/team%2Forders/versions"},
+		{name: "Spaces", value: "orders value", expected: "/subjects/orders%20value/versions"},
+		{name: "Query and fragment characters", value: "a?b#c", expected: "/subjects/a%3Fb%23c/versions"},
+		{name: "Percent sign", value: "100%", expected: "/subjects/100%25/versions"},
+		{name: "Plain id", value: "orders-value", expected: "/subjects/orders-value/versions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := BuildAPIPath("/subjects/{subject}/versions", map[string]interface{}{"subject": tt.value})
+			if path != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, path)
+			}
+		})
+	}
+}
+
+func TestSingularResources(t *testing.T) {
+	t.Cleanup(func() { SetSingularResources(nil) })
+
+	// Singular paths that no other rule maps, so only SINGULAR_RESOURCES decides the outcome
+	spec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/health":  {Get: &openapi.Operation{Summary: "Get health"}},
+			"/version": {Get: &openapi.Operation{Summary: "Get version"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		singular  []string
+		wantFound bool
+	}{
+		{name: "without SINGULAR_RESOURCES", singular: nil, wantFound: false},
+		{name: "with SINGULAR_RESOURCES", singular: []string{"health", "version"}, wantFound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetSingularResources(tt.singular)
+
+			for _, resource := range []string{"health", "version"} {
+				extracted := ExtractResourceFromPath("/" + resource)
+				if found := extracted == resource; found != tt.wantFound {
+					t.Errorf("Expected /%s extracted as a resource: %v, got '%s'", resource, tt.wantFound, extracted)
+				}
+			}
+
+			_, err := GenerateSemanticTools(spec)
+			if !tt.wantFound {
+				// No path yields a resource, so the registry stays empty
+				if _, mapErr := GetEndpointMapping(ActionGet, "health"); mapErr == nil {
+					t.Errorf("Expected no get mapping for health, got one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to generate semantic tools: %v", err)
+			}
+			for _, resource := range []string{"health", "version"} {
+				mapping, mapErr := GetEndpointMapping(ActionGet, resource)
+				if mapErr != nil {
+					t.Errorf("Expected a get mapping for %s, got %v", resource, GetSupportedResources(ActionGet))
+					continue
+				}
+				if mapping.PathPattern != "/"+resource {
+					t.Errorf("Expected get %s to map to /%s, got %s", resource, resource, mapping.PathPattern)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateSemanticToolsTagFilter(t *testing.T) {
+	t.Cleanup(func() { SetTagFilter(nil, nil) })
+
+	spec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Get: &openapi.Operation{Summary: "List topics", Tags: []string{"Topic (v3)"}},
+			},
+			"/connect/v1/environments/{environment_id}/clusters/{kafka_cluster_id}/connectors": {
+				Get: &openapi.Operation{Summary: "List connectors", Tags: []string{"Connectors (v1)"}},
+			},
+			"/iam/v2/service-accounts": {
+				Get: &openapi.Operation{Summary: "List service accounts"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		expected []string
+	}{
+		{name: "No filter", expected: []string{"connectors", "service-accounts", "topics"}},
+		{name: "Include tag", include: []string{"topic (v3)"}, expected: []string{"topics"}},
+		{name: "Exclude tag", exclude: []string{"Connectors (v1)"}, expected: []string{"service-accounts", "topics"}},
+		{name: "Exclusion wins", include: []string{"Topic (v3)", "Connectors (v1)"}, exclude: []string{"Topic (v3)"}, expected: []string{"connectors"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetTagFilter(tt.include, tt.exclude)
+			if _, err := GenerateSemanticTools(spec); err != nil {
+				t.Fatalf("Failed to generate semantic tools: %v", err)
+			}
+			if resources := GetSupportedResources(ActionList); !reflect.DeepEqual(resources, tt.expected) {
+				t.Errorf("Expected list resources %v, got %v", tt.expected, resources)
+			}
+		})
+	}
+}
+
+func TestGenerateSemanticToolsResourceParameters(t *testing.T) {
+	spec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/connect/v1/environments/{environment_id}/clusters/{kafka_cluster_id}/connectors": {
+				Get: &openapi.Operation{Parameters: []openapi.Parameter{
+					{Name: "expand", In: "query", Description: "Extra details to include", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"status", "info", "id"}}},
+				}},
+			},
+			"/iam/v2/service-accounts": {
+				Get: &openapi.Operation{},
+				Post: &openapi.Operation{RequestBody: &openapi.RequestBody{Content: map[string]openapi.MediaType{
+					ContentTypeJSON: {Schema: &openapi.Schema{
+						Type:     "object",
+						Required: []string{"display_name"},
+						Properties: map[string]*openapi.Schema{
+							"display_name": {Type: "string"},
+							"kind":         {Type: "string", Enum: []interface{}{"ServiceAccount"}},
+						},
+					}},
+				}}},
+			},
+		},
+	}
+
+	generated, err := GenerateSemanticTools(spec)
+	if err != nil {
+		t.Fatalf("Failed to generate tools: %v", err)
+	}
+
+	parameterProperties := func(action string) map[string]interface{} {
+		for _, tool := range generated {
+			if tool.Name == action {
+				properties := tool.Parameters["properties"].(map[string]interface{})
+				return properties["parameters"].(map[string]interface{})["properties"].(map[string]interface{})
+			}
+		}
+		t.Fatalf("Expected a %s tool", action)
+		return nil
+	}
+
+	expand, ok := parameterProperties(ActionList)["expand"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected the connectors expand parameter in the list tool schema")
+	}
+	if enum, _ := expand["enum"].([]interface{}); len(enum) != 3 || enum[0] != "status" {
+		t.Errorf("Expected the expand enum, got %v", expand["enum"])
+	}
+	if description, _ := expand["description"].(string); !strings.HasPrefix(description, "Extra details to include. Used by: connectors") {
+		t.Errorf("Expected the description to name the connectors resource, got %q", description)
+	}
+
+	createProperties := parameterProperties(ActionCreate)
+	displayName, _ := createProperties["display_name"].(map[string]interface{})
+	if displayName["type"] != "string" || displayName["description"] != "Used by: service-accounts (required)" {
+		t.Errorf("Expected display_name as a required string of service-accounts, got %v", displayName)
+	}
+	kind, _ := createProperties["kind"].(map[string]interface{})
+	if enum, _ := kind["enum"].([]interface{}); len(enum) != 1 || enum[0] != "ServiceAccount" {
+		t.Errorf("Expected the kind enum, got %v", kind)
+	}
+	if kind["description"] != "Used by: service-accounts" {
+		t.Errorf("Expected kind to be optional, got %v", kind["description"])
+	}
+}
+
+func TestResourceParameterPropertiesConflictingTypes(t *testing.T) {
+	body := func(sizeType string, kinds []interface{}, required ...string) map[string]interface{} {
+		return map[string]interface{}{"schema": &openapi.Schema{
+			Type:     "object",
+			Required: required,
+			Properties: map[string]*openapi.Schema{
+				"size": {Type: sizeType},
+				"kind": {Type: "string", Enum: kinds},
+				"mode": {Type: "string", Enum: []interface{}{"fast", "safe"}},
+			},
+		}}
+	}
+	mappings := map[string]EndpointMapping{
+		"gadgets": {Method: "POST", PathPattern: "/gadgets", RequestBodySchema: body("string", []interface{}{"round", "square"})},
+		"widgets": {Method: "POST", PathPattern: "/widgets", RequestBodySchema: body("integer", []interface{}{"flat"}, "size")},
+	}
+	properties := resourceParameterProperties(mappings, nil)
+
+	size, _ := properties["size"].(map[string]interface{})
+	if _, hasType := size["type"]; hasType {
+		t.Errorf("Expected no single type for conflicting declarations, got %v", size["type"])
+	}
+	oneOf, _ := size["oneOf"].([]interface{})
+	if len(oneOf) != 2 || oneOf[0].(map[string]interface{})["type"] != "string" || oneOf[1].(map[string]interface{})["type"] != "integer" {
+		t.Errorf("Expected a oneOf of string and integer, got %v", size["oneOf"])
+	}
+	if want := "Used by: gadgets (string), widgets (integer, required)"; size["description"] != want {
+		t.Errorf("Expected description %q, got %q", want, size["description"])
+	}
+
+	kind, _ := properties["kind"].(map[string]interface{})
+	if _, hasEnum := kind["enum"]; hasEnum || kind["type"] != "string" {
+		t.Errorf("Expected a string without enum for conflicting enums, got %v", kind)
+	}
+	mode, _ := properties["mode"].(map[string]interface{})
+	if !reflect.DeepEqual(mode["enum"], []interface{}{"fast", "safe"}) {
+		t.Errorf("Expected the enum shared by all resources to be kept, got %v", mode)
+	}
+}
+
+func TestLimitToolNames(t *testing.T) {
+	t.Cleanup(func() { SetMaxTools(0, nil) })
+
+	names := []string{ActionCreate, ActionDelete, ActionGet, ActionList, ActionUpdate, "batch"}
+
+	tests := []struct {
+		name     string
+		max      int
+		priority []string
+		want     []string
+	}{
+		{name: "no limit", max: 0, want: nil},
+		{name: "read actions first", max: 2, want: []string{"batch", ActionCreate, ActionDelete, ActionUpdate}},
+		{name: "prioritized tools first", max: 3, priority: []string{ActionDelete, "batch"}, want: []string{ActionCreate, ActionList, ActionUpdate}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetMaxTools(tt.max, tt.priority)
+			if dropped := LimitToolNames(names); !reflect.DeepEqual(dropped, tt.want) {
+				t.Errorf("Expected dropped tools %v, got %v", tt.want, dropped)
+			}
+		})
+	}
+}
+
+func TestGenerateSemanticToolsCanonicalResourceNames(t *testing.T) {
+	t.Cleanup(func() {
+		SetSingularResources(nil)
+		SetCanonicalResourceNames(false)
+	})
+	SetSingularResources([]string{"topic"})
+
+	spec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics":              {Get: &openapi.Operation{Summary: "List topics"}},
+			"/kafka/v3/clusters/{cluster_id}/topics/{topic_name}": {Delete: &openapi.Operation{Summary: "Delete topic"}},
+			"/kafka/v3/clusters/{cluster_id}/topic/{topic_name}": {
+				Get:    &openapi.Operation{Summary: "Get topic"},
+				Delete: &openapi.Operation{Summary: "Delete topic (legacy)"},
+			},
+		},
+	}
+	resourceEnum := func(semanticTools []Tool, action string) []string {
+		for _, tool := range semanticTools {
+			if tool.Name != action {
+				continue
+			}
+			properties := tool.Parameters["properties"].(map[string]interface{})
+			enum, _ := properties["resource"].(map[string]interface{})["enum"].([]string)
+			sorted := append([]string(nil), enum...)
+			sort.Strings(sorted)
+			return sorted
+		}
+		t.Fatalf("Expected a %s tool", action)
+		return nil
+	}
+
+	t.Run("Variants are kept apart by default", func(t *testing.T) {
+		semanticTools, err := GenerateSemanticTools(spec)
+		if err != nil {
+			t.Fatalf("Failed to generate semantic tools: %v", err)
+		}
+		if enum := strings.Join(resourceEnum(semanticTools, ActionDelete), ","); enum != "topic,topics" {
+			t.Errorf("Expected both variants in the delete enum, got %s", enum)
+		}
+	})
+
+	t.Run("Variants collapse to the plural form", func(t *testing.T) {
+		SetCanonicalResourceNames(true)
+		semanticTools, err := GenerateSemanticTools(spec)
+		if err != nil {
+			t.Fatalf("Failed to generate semantic tools: %v", err)
+		}
+		for _, action := range []string{ActionList, ActionGet, ActionDelete} {
+			if enum := strings.Join(resourceEnum(semanticTools, action), ","); enum != "topics" {
+				t.Errorf("Expected only topics in the %s enum, got %s", action, enum)
+			}
+		}
+
+		get, err := GetEndpointMapping(ActionGet, "topics")
+		if err != nil || get.PathPattern != "/kafka/v3/clusters/{cluster_id}/topic/{topic_name}" {
+			t.Errorf("Expected get topics to keep the singular path mapping, got %+v (%v)", get, err)
+		}
+		del, err := GetEndpointMapping(ActionDelete, "topics")
+		if err != nil || del.PathPattern != "/kafka/v3/clusters/{cluster_id}/topics/{topic_name}" {
+			t.Errorf("Expected delete topics to keep the plural path mapping, got %+v (%v)", del, err)
+		}
+	})
+}
+
+func TestCanonicalResourceNamesKeepSchemaRegistryConfig(t *testing.T) {
+	t.Cleanup(func() { SetCanonicalResourceNames(false) })
+	SetCanonicalResourceNames(true)
+
+	spec, err := openapi.ParseOpenAPISpec("../../api-spec/confluent-apispec.json")
+	if err != nil {
+		t.Fatalf("Failed to parse the bundled spec: %v", err)
+	}
+	if _, err := GenerateSemanticTools(*spec); err != nil {
+		t.Fatalf("Failed to generate semantic tools: %v", err)
+	}
+
+	for _, action := range []string{ActionList, ActionGet, ActionUpdate, ActionDelete} {
+		mapping, err := GetEndpointMapping(action, "config")
+		if err != nil {
+			t.Errorf("Expected %s config to survive, got %v", action, err)
+			continue
+		}
+		if mapping.Service != ServiceSchemaRegistry {
+			t.Errorf("Expected %s config to map Schema Registry, got %s %s", action, mapping.Method, mapping.PathPattern)
+		}
+	}
+}
+
+func TestGenerateSemanticToolsRateLimitHints(t *testing.T) {
+	t.Cleanup(func() { SetRateLimitHints(false) })
+
+	spec := openapi.OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]openapi.PathItem{
+			"/kafka/v3/clusters/{cluster_id}/topics": {
+				Get: &openapi.Operation{
+					Summary:    "List topics",
+					Extensions: map[string]interface{}{"x-ratelimit-limit": 100, "x-ratelimit-window": "60s"},
+				},
+			},
+		},
+	}
+	listDescription := func() string {
+		semanticTools, err := GenerateSemanticTools(spec)
+		if err != nil {
+			t.Fatalf("Failed to generate semantic tools: %v", err)
+		}
+		for _, tool := range semanticTools {
+			if tool.Name == ActionList {
+				return tool.Description
+			}
+		}
+		t.Fatal("Expected a list tool")
+		return ""
+	}
+
+	if description := listDescription(); strings.Contains(description, "Rate limits") {
+		t.Errorf("Expected no rate-limit hints by default, got %q", description)
+	}
+
+	SetRateLimitHints(true)
+	if description := listDescription(); !strings.Contains(description, "Rate limits: topics (limit=100, window=60s)") {
+		t.Errorf("Expected the rate-limit hints of topics, got %q", description)
+	}
+	mapping, err := GetEndpointMapping(ActionList, "topics")
+	if err != nil || mapping.RateLimit["limit"] != "100" {
+		t.Errorf("Expected the mapping to carry the rate limit, got %+v (%v)", mapping, err)
+	}
+}
@@ -0,0 +1,34 @@
+package tools
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	singularResources      map[string]bool
+	singularResourcesMutex sync.RWMutex
+)
+
+// SetSingularResources sets the singular path segments recognized as resources, such as
+// "config" or "mode", which the plural naming heuristics skip. Must be set before tools are
+// generated; nil restores the heuristics only.
+func SetSingularResources(names []string) {
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			allowed[name] = true
+		}
+	}
+
+	singularResourcesMutex.Lock()
+	defer singularResourcesMutex.Unlock()
+	singularResources = allowed
+}
+
+// isSingularResource reports whether a path segment is an allowlisted singular resource
+func isSingularResource(part string) bool {
+	singularResourcesMutex.RLock()
+	defer singularResourcesMutex.RUnlock()
+	return singularResources[part]
+}
@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"mcolomerc/mcp-server/internal/openapi"
+	"strings"
+	"sync"
+)
+
+var (
+	includeTags    map[string]bool
+	excludeTags    map[string]bool
+	tagFilterMutex sync.RWMutex
+)
+
+// SetTagFilter limits tool generation to operations tagged with one of include, when set, and
+// drops operations tagged with one of exclude. Tags are compared case-insensitively. Must be set
+// before tools are generated; nil lists disable the filter.
+func SetTagFilter(include, exclude []string) {
+	tagFilterMutex.Lock()
+	defer tagFilterMutex.Unlock()
+	includeTags = tagSet(include)
+	excludeTags = tagSet(exclude)
+}
+
+// tagSet normalizes a tag list into a lookup set
+func tagSet(tags []string) map[string]bool {
+	set := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			set[tag] = true
+		}
+	}
+	return set
+}
+
+// excludedByTags reports whether the tag filter leaves an operation out of the generated tools.
+// Exclusion wins over inclusion; untagged operations are dropped only when INCLUDE_TAGS is set.
+func excludedByTags(op *openapi.Operation) bool {
+	tagFilterMutex.RLock()
+	defer tagFilterMutex.RUnlock()
+
+	included := len(includeTags) == 0
+	for _, tag := range op.Tags {
+		tag = strings.ToLower(tag)
+		if excludeTags[tag] {
+			return true
+		}
+		if includeTags[tag] {
+			included = true
+		}
+	}
+	return !included
+}
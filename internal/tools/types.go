@@ -42,6 +42,7 @@ type Tool struct {
 	Description string
 	Endpoint    string
 	Parameters  map[string]interface{} // JSON Schema parameters
+	Services    []string               // Services of the tool's endpoints (e.g. kafka, flink), for grouping tools in clients
 }
 
 // Semantic action constants
@@ -53,24 +54,38 @@ const (
 	ActionDelete = "delete"
 )
 
-// getAllSemanticActions returns all supported semantic actions
+// TelemetryAction is the tool and registry action of the Telemetry API. Its mappings come from
+// the telemetry spec and live next to the main spec actions in the shared registry.
+const TelemetryAction = "get_telemetry"
+
+// getAllSemanticActions returns all supported semantic actions, including those added by action rules
 func getAllSemanticActions() []string {
-	return []string{ActionCreate, ActionList, ActionGet, ActionUpdate, ActionDelete}
+	actions := []string{ActionCreate, ActionList, ActionGet, ActionUpdate, ActionDelete}
+	return append(actions, customSemanticActions()...)
 }
 
 // EndpointMapping represents the mapping from semantic action+resource to API endpoint
 type EndpointMapping struct {
-	Method            string                 // HTTP method
-	PathPattern       string                 // API path pattern with {placeholders}
-	RequiredParams    []string               // Required parameters for this endpoint
-	OptionalParams    []string               // Optional parameters
-	RequestBodySchema map[string]interface{} // Schema for request body if applicable
+	Method             string                 // HTTP method
+	PathPattern        string                 // API path pattern with {placeholders}
+	RequiredParams     []string               // Required parameters for this endpoint
+	OptionalParams     []string               // Optional parameters
+	RequestBodySchema  map[string]interface{} // Schema for request body if applicable
+	SubjectPathPattern string                 // Schema Registry settings: subject-level path used when a subject is given
+	Deprecated         bool                   // The operation is marked deprecated in the spec
+	RequiredQuery      []string               // Required query parameters, which tell same-path operations apart
+	QueryVariants      []EndpointMapping      // Same-path operations selected by their RequiredQuery, see VariantFor
+	Service            string                 // Service the path belongs to, see ServiceForPath
+	ResponseArrayField string                 // Array property wrapping the items of a GET response, per its response schema
+	ResponseItemFields []string               // Properties of the items of a GET response, per its response schema
+	RateLimit          map[string]string      // x-ratelimit-* extensions of the operation keyed without the prefix, e.g. limit
 }
 
 // SemanticToolRegistry holds all the mappings for semantic tools
 type SemanticToolRegistry struct {
 	Mappings map[string]map[string]EndpointMapping // action -> resource -> endpoint mapping
 	Spec     *openapi.OpenAPISpec                  // Reference to the spec for resolving references
+	Skipped  map[string]string                     // spec path -> reason it produced no mapping
 	mutex    sync.RWMutex                          // Protects concurrent access
 }
 
@@ -84,6 +99,7 @@ type EnvironmentVariable struct {
 type RequestBodyInfo struct {
 	Schema      interface{}
 	ContentType string
+	Example     interface{} // Example body declared on the media type, if any
 }
 
 // getDefaultEnvVarMappings returns default environment variable mappings
@@ -103,6 +119,14 @@ func getDefaultEnvVarMappings() []EnvironmentVariable {
 // PostSpecialOperations contains special path suffixes that indicate update action for POST
 var PostSpecialOperations = []string{":batch", ":alter", "/request", "/undelete"}
 
+// SchemaRegistrySettingsFields maps the Schema Registry settings resources to the body field an
+// update sets, which is required when the spec marks it so. Their singular paths (/mode, /config and the /{subject} variants) are not picked up
+// by resource extraction, so they are registered explicitly.
+var SchemaRegistrySettingsFields = map[string]string{
+	"mode":   "mode",
+	"config": "compatibility",
+}
+
 // CollectionEndpoints lists common collection paths
 var CollectionEndpoints = []string{
 	"/topics", "/clusters", "/subjects", "/schemas", "/connectors",
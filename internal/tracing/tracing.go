@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of the server's spans
+const TracerName = "mcolomerc/mcp-server"
+
+// DefaultServiceName is reported as service.name unless OTEL_SERVICE_NAME or
+// OTEL_RESOURCE_ATTRIBUTES set one
+const DefaultServiceName = "confluent-mcp-server"
+
+// Setup installs a global tracer provider that exports spans over OTLP/HTTP and the W3C trace
+// context propagator. The exporter is configured with the standard OTEL_EXPORTER_OTLP_* environment
+// variables. The returned function flushes pending spans and stops the provider.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	// Attributes from the environment come last so they override the default service name
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(DefaultServiceName)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the server's tracer from the global provider. Until Setup runs this is a
+// no-op tracer, so instrumented code costs next to nothing when tracing is disabled.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
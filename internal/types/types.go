@@ -1,13 +1,41 @@
 package types
 
+import "context"
+
 // InvokeRequest represents a tool invocation request
 type InvokeRequest struct {
-	Tool      string                 `json:"tool"`
-	Arguments map[string]interface{} `json:"arguments"`
+	Tool        string                 `json:"tool"`
+	Arguments   map[string]interface{} `json:"arguments"`
+	SessionID   string                 `json:"session_id,omitempty"` // Client session, used to scope per-session state
+	ClientToken string                 `json:"-"`                    // The client's own API token, sent instead of the configured credentials
+	Context     context.Context        `json:"-"`                    // Parent context of the call, carrying its trace span; nil means none
+	PageWriter  PageWriter             `json:"-"`                    // Receives each page of an all_pages list as it is fetched; nil buffers them into the result
 }
 
+// PageWriter receives the data of one page of a list, numbered from 1. An error stops the listing.
+type PageWriter func(page int, data []interface{}) error
+
 // InvokeResponse represents a tool invocation response
 type InvokeResponse struct {
-	Result interface{} `json:"result,omitempty"`
-	Error  string      `json:"error,omitempty"`
+	Result      interface{}  `json:"result,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	Warnings    []string     `json:"warnings,omitempty"`     // Advisory messages that accompany Result without altering it
+	Cooldown    *Cooldown    `json:"cooldown,omitempty"`     // Set when the call was blocked by a loop detection cooldown
+	Repro       string       `json:"repro,omitempty"`        // curl command repeating a failed API call, credentials redacted
+	FieldErrors []FieldError `json:"field_errors,omitempty"` // Per-field validation errors of a rejected API call
+	ETag        string       `json:"etag,omitempty"`         // ETag of the API response, to send back as if_match on the next update
+}
+
+// FieldError is one field-level validation error returned by the API
+type FieldError struct {
+	Field   string `json:"field"`             // Dotted path of the rejected field, e.g. spec.display_name
+	Pointer string `json:"pointer,omitempty"` // JSON pointer the API reported, e.g. /spec/display_name
+	Code    string `json:"code,omitempty"`    // Error code, e.g. invalid_input
+	Message string `json:"message"`           // What is wrong with the field
+}
+
+// Cooldown tells a client when a blocked tool call may be retried
+type Cooldown struct {
+	CooldownUntil     string `json:"cooldown_until"`      // RFC3339 time the cooldown ends
+	RetryAfterSeconds int    `json:"retry_after_seconds"` // Seconds to wait before retrying
 }